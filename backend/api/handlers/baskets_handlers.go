@@ -2,45 +2,94 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
 	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultSearchRadiusKm = 10.0
+	maxSearchRadiusKm     = 100.0
+)
+
 type BasketHandler struct {
-	BasketService *services.BasketService
+	BasketService   *services.BasketService
+	FavoriteService *services.FavoriteService
 }
 
-func NewBasketHandler(basketService *services.BasketService) *BasketHandler {
-	return &BasketHandler{BasketService: basketService}
+func NewBasketHandler(basketService *services.BasketService, favoriteService *services.FavoriteService) *BasketHandler {
+	return &BasketHandler{BasketService: basketService, FavoriteService: favoriteService}
 }
-   
+
 // GetBaskets godoc
 // @Summary Get all baskets
-// @Description Retrieve a list of all baskets
+// @Description Retrieve a page of baskets matching the filters. When lat and lng are given, only baskets of stores within radius_km are returned. Pass nextCursor back as cursor to get the next page.
 // @Tags Baskets
 // @Accept  json
 // @Produce  json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
-// @Success 200 {array} responses.BasketResponse
+// @Param lat query number false "Latitude of the search point"
+// @Param lng query number false "Longitude of the search point"
+// @Param radius_km query number false "Search radius in km (default 10, max 100)"
+// @Param category_id query int false "Store category ID"
+// @Param min_price query number false "Minimum final price"
+// @Param max_price query number false "Maximum final price"
+// @Param min_discount query number false "Minimum discount (0.2 for 20%)"
+// @Param available query bool false "Only baskets in stock with status Disponible"
+// @Param pickup_date query string false "Only baskets whose pickup window covers this day in the store's time zone (YYYY-MM-DD)"
+// @Param pickup_from query string false "Only baskets whose pickup window ends after this time (RFC 3339)"
+// @Param pickup_to query string false "Only baskets whose pickup window starts before this time (RFC 3339)"
+// @Param sort query string false "Sort order" Enums(newest, price, discount, distance, rating)
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (default 50, max 100)"
+// @Success 200 {object} responses.BasketListResponse
+// @Failure 400 {object} map[string]string "Invalid search parameters"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/baskets/ [get]
 func (h *BasketHandler) GetBaskets(c *gin.Context) {
-	baskets, err := h.BasketService.GetBaskets()
+	search, err := parseBasketSearch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.BasketService.GetBaskets(search)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrInvalidCursor),
+			errors.Is(err, repositories.ErrInvalidSort),
+			errors.Is(err, repositories.ErrSortRequiresLocation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	favorites, err := h.FavoriteService.FavoriteStoreIDs(c.MustGet("userId").(uint))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	var response []responses.BasketResponse
+	response := responses.BasketListResponse{
+		Data:       make([]responses.BasketResponse, 0, len(page.Results)),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
 
-	for _, basket := range baskets {
+	for _, result := range page.Results {
+		basket := result.Basket
 		basketResponse := responses.BasketResponse{
 			ID:                 basket.ID,
 			Name:               basket.Name,
@@ -52,13 +101,147 @@ func (h *BasketHandler) GetBaskets(c *gin.Context) {
 			OriginalPrice:      basket.OriginalPrice,
 			DiscountPercentage: basket.DiscountPercentage,
 			Category:           basket.Store.Category.Name,
+			Quantity:           basket.Quantity,
+			Distance:           result.Distance,
+			TimeZone:           basket.Store.Location().String(),
+			IsFavorite:         favorites[basket.Store.ID],
 		}
-		response = append(response, basketResponse)
+		basketResponse.PickupStart, basketResponse.PickupEnd = basketPickupWindow(&basket)
+		response.Data = append(response.Data, basketResponse)
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// parseBasketSearch lit les paramètres de recherche de GET /api/baskets
+func parseBasketSearch(c *gin.Context) (repositories.BasketSearch, error) {
+	var search repositories.BasketSearch
+	var err error
+
+	latStr, lngStr := c.Query("lat"), c.Query("lng")
+	if latStr != "" || lngStr != "" {
+		if latStr == "" || lngStr == "" {
+			return search, errors.New("lat and lng must be provided together")
+		}
+
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil || lat < -90 || lat > 90 {
+			return search, errors.New("invalid lat")
+		}
+		lng, err := strconv.ParseFloat(lngStr, 64)
+		if err != nil || lng < -180 || lng > 180 {
+			return search, errors.New("invalid lng")
+		}
+
+		radius := defaultSearchRadiusKm
+		if radiusStr := c.Query("radius_km"); radiusStr != "" {
+			radius, err = strconv.ParseFloat(radiusStr, 64)
+			if err != nil || radius <= 0 || radius > maxSearchRadiusKm {
+				return search, errors.New("invalid radius_km")
+			}
+		}
+
+		search.Latitude = &lat
+		search.Longitude = &lng
+		search.RadiusKm = radius
+	}
+
+	if categoryStr := c.Query("category_id"); categoryStr != "" {
+		categoryID, err := strconv.ParseUint(categoryStr, 10, 32)
+		if err != nil {
+			return search, errors.New("invalid category_id")
+		}
+		id := uint(categoryID)
+		search.CategoryID = &id
+	}
+
+	if search.MinPrice, err = optionalFloatQuery(c, "min_price"); err != nil {
+		return search, err
+	}
+	if search.MaxPrice, err = optionalFloatQuery(c, "max_price"); err != nil {
+		return search, err
+	}
+	if search.MinDiscount, err = optionalFloatQuery(c, "min_discount"); err != nil {
+		return search, err
+	}
+
+	if availableStr := c.Query("available"); availableStr != "" {
+		search.AvailableOnly, err = strconv.ParseBool(availableStr)
+		if err != nil {
+			return search, errors.New("invalid available")
+		}
+	}
+
+	if pickupDate := c.Query("pickup_date"); pickupDate != "" {
+		if _, err := time.Parse("2006-01-02", pickupDate); err != nil {
+			return search, errors.New("invalid pickup_date, expected YYYY-MM-DD")
+		}
+		search.PickupDate = &pickupDate
+	}
+
+	if search.PickupFrom, err = optionalTimeQuery(c, "pickup_from"); err != nil {
+		return search, err
+	}
+	if search.PickupTo, err = optionalTimeQuery(c, "pickup_to"); err != nil {
+		return search, err
+	}
+	if search.PickupFrom != nil && search.PickupTo != nil && search.PickupTo.Before(*search.PickupFrom) {
+		return search, errors.New("pickup_to must be after pickup_from")
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		search.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || search.Limit <= 0 {
+			return search, errors.New("invalid limit")
+		}
+	}
+
+	search.Sort = c.Query("sort")
+	search.Cursor = c.Query("cursor")
+	return search, nil
+}
+
+// optionalFloatQuery lit un paramètre de requête décimal facultatif
+func optionalFloatQuery(c *gin.Context, name string) (*float64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		return nil, errors.New("invalid " + name)
+	}
+	return &parsed, nil
+}
+
+// optionalTimeQuery lit un paramètre de requête facultatif au format RFC 3339
+func optionalTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New("invalid " + name + ", expected RFC 3339")
+	}
+	return &parsed, nil
+}
+
+// basketPickupWindow retourne la plage de retrait du panier exprimée dans le fuseau de son magasin
+func basketPickupWindow(basket *models.Basket) (*time.Time, *time.Time) {
+	loc := basket.Store.Location()
+	var start, end *time.Time
+	if basket.PickupStart != nil {
+		t := basket.PickupStart.In(loc)
+		start = &t
+	}
+	if basket.PickupEnd != nil {
+		t := basket.PickupEnd.In(loc)
+		end = &t
+	}
+	return start, end
+}
+
 // GetBasket godoc
 // @Summary Get a single basket
 // @Description Retrieve a basket by its ID
@@ -85,6 +268,12 @@ func (h *BasketHandler) GetBasket(c *gin.Context) {
 		return
 	}
 
+	favorites, err := h.FavoriteService.FavoriteStoreIDs(c.MustGet("userId").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	response := responses.BasketResponse{
 		ID:                 basket.ID,
 		Name:               basket.Name,
@@ -93,7 +282,10 @@ func (h *BasketHandler) GetBasket(c *gin.Context) {
 		OriginalPrice:      basket.OriginalPrice,
 		DiscountPercentage: basket.DiscountPercentage,
 		Category:           basket.Store.Category.Name,
+		TimeZone:           basket.Store.Location().String(),
+		IsFavorite:         favorites[basket.Store.ID],
 	}
+	response.PickupStart, response.PickupEnd = basketPickupWindow(basket)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -122,7 +314,14 @@ func (h *BasketHandler) CreateBasket(c *gin.Context) {
 
 	err := h.BasketService.CreateBasket(basketRequest, userId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		statusCode := http.StatusInternalServerError
+		if isPickupWindowError(err) ||
+			errors.Is(err, services.ErrInvalidExpirationDate) ||
+			errors.Is(err, services.ErrConfigurationWrongStore) ||
+			errors.Is(err, repositories.ErrBasketConfigurationNotFound) {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -150,18 +349,22 @@ func (h *BasketHandler) UpdateBasket(c *gin.Context) {
 		return
 	}
 
-	userId := c.GetInt("userId")
+	storeID := c.MustGet("storeId").(uint)
 	var updates models.Basket
 	if err := c.ShouldBindJSON(&updates); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	updatedBasket, err := h.BasketService.UpdateBasket(id, updates, userId)
+	updatedBasket, err := h.BasketService.UpdateBasket(id, updates, storeID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "not authorized to update this basket" {
+		if errors.Is(err, services.ErrBasketWrongStore) {
 			statusCode = http.StatusForbidden
+		} else if errors.Is(err, repositories.ErrBasketNotFound) {
+			statusCode = http.StatusNotFound
+		} else if isPickupWindowError(err) {
+			statusCode = http.StatusBadRequest
 		}
 		c.JSON(statusCode, gin.H{"error": err.Error()})
 		return
@@ -179,6 +382,7 @@ func (h *BasketHandler) UpdateBasket(c *gin.Context) {
 // @Produce  json
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "Basket ID"
+// @Param store_id query int true "Store ID"
 // @Success 204 "No Content"
 // @Failure 400 {object} map[string]string "Invalid basket ID"
 // @Failure 403 {object} map[string]string "Not authorized to delete this basket"
@@ -192,11 +396,13 @@ func (h *BasketHandler) DeleteBasket(c *gin.Context) {
 		return
 	}
 
-	userId := c.GetInt("userId")
-	if err := h.BasketService.DeleteBasket(id, userId); err != nil {
+	storeID := c.MustGet("storeId").(uint)
+	if err := h.BasketService.DeleteBasket(id, storeID); err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "not authorized to delete this basket" {
+		if errors.Is(err, services.ErrBasketWrongStore) {
 			statusCode = http.StatusForbidden
+		} else if errors.Is(err, repositories.ErrBasketNotFound) {
+			statusCode = http.StatusNotFound
 		}
 		c.JSON(statusCode, gin.H{"error": err.Error()})
 		return
@@ -213,12 +419,12 @@ func (h *BasketHandler) DeleteBasket(c *gin.Context) {
 // @Produce  json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
-// @Param store_id path int true "Store ID"
-// @Success 200 {array} responses.BasketResponse
+// @Param id path int true "Store ID"
+// @Success 200 {array} responses.BasketByStoreResponse
 // @Failure 400 {object} map[string]string "Invalid store ID"
 // @Failure 404 {object} map[string]string "Store not found"
 // @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/stores/{store_id}/baskets [get]
+// @Router /api/stores/{id}/baskets [get]
 func (h *BasketHandler) GetBasketsByStore(c *gin.Context) {
 	storeId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -235,17 +441,32 @@ func (h *BasketHandler) GetBasketsByStore(c *gin.Context) {
 	var response []responses.BasketByStoreResponse
 
 	for _, basket := range baskets {
-		basketByStoreResponse := responses.BasketByStoreResponse{
-			ID:                 basket.ID,
-			Name:               basket.Name,
-			OriginalPrice:      basket.OriginalPrice,
-			DiscountPercentage: basket.DiscountPercentage,
-			Category:           basket.Store.Category.Name,
-			Description:        basket.Description,
-			Quantity:           basket.Quantity,
-		}
-		response = append(response, basketByStoreResponse)
+		response = append(response, newBasketByStoreResponse(&basket))
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+func newBasketByStoreResponse(basket *models.Basket) responses.BasketByStoreResponse {
+	response := responses.BasketByStoreResponse{
+		ID:                 basket.ID,
+		Name:               basket.Name,
+		OriginalPrice:      basket.OriginalPrice,
+		DiscountPercentage: basket.DiscountPercentage,
+		Category:           basket.Store.Category.Name,
+		Description:        basket.Description,
+		Quantity:           basket.Quantity,
+		TimeZone:           basket.Store.Location().String(),
+	}
+	response.PickupStart, response.PickupEnd = basketPickupWindow(basket)
+	return response
+}
+
+// isPickupWindowError indique si err provient de la validation d'une plage de retrait
+func isPickupWindowError(err error) bool {
+	return errors.Is(err, services.ErrPickupWindowIncomplete) ||
+		errors.Is(err, services.ErrPickupWindowInvalid) ||
+		errors.Is(err, services.ErrPickupWindowPast) ||
+		errors.Is(err, services.ErrPickupOutsideOpeningHours) ||
+		errors.Is(err, utils.ErrInvalidTimeOfDay)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FavoriteHandler struct {
+	service *services.FavoriteService
+}
+
+func NewFavoriteHandler(service *services.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{service: service}
+}
+
+// AddFavorite godoc
+// @Summary Ajouter un magasin aux favoris
+// @Description Ajoute le magasin aux favoris de l'utilisateur connecté ; sans effet s'il y est déjà
+// @Tags Favorites
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Magasin introuvable"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/stores/{id}/favorite [post]
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	if err := h.service.AddFavorite(userID, uint(storeID)); err != nil {
+		if errors.Is(err, services.ErrStoreNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'ajout aux favoris"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveFavorite godoc
+// @Summary Retirer un magasin des favoris
+// @Description Retire le magasin des favoris de l'utilisateur connecté ; sans effet s'il n'y est pas
+// @Tags Favorites
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/stores/{id}/favorite [delete]
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	if err := h.service.RemoveFavorite(userID, uint(storeID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du retrait des favoris"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFavorites godoc
+// @Summary Magasins favoris
+// @Description Retourne les magasins favoris de l'utilisateur connecté avec leurs paniers actuellement réservables
+// @Tags Favorites
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} models.Response{data=[]responses.FavoriteStoreResponse}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/favorites [get]
+func (h *FavoriteHandler) GetFavorites(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	favorites, err := h.service.GetFavorites(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des favoris"})
+		return
+	}
+
+	response := make([]responses.FavoriteStoreResponse, 0, len(favorites))
+	for _, favorite := range favorites {
+		item := responses.FavoriteStoreResponse{
+			Store:   favorite.Store,
+			Baskets: make([]responses.BasketByStoreResponse, 0, len(favorite.Baskets)),
+		}
+		for i := range favorite.Baskets {
+			item.Baskets = append(item.Baskets, newBasketByStoreResponse(&favorite.Baskets[i]))
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
 	"github.com/Sebiche09/app-anti-gaspillage.git/services"
-	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,195 +25,337 @@ func NewInvitationHandler(invitationService *services.InvitationService) *Invita
 
 // CreateInvitation envoie une invitation à rejoindre un magasin
 // @Summary Envoyer une invitation à rejoindre un magasin
-// @Description Permet à un marchand d'inviter une personne à rejoindre son magasin en tant que staff
+// @Description Permet au commerçant ou à un responsable du magasin d'inviter une personne à rejoindre son équipe. Un lien signé est envoyé par email ; seul un compte avec cet email pourra accepter l'invitation.
 // @Tags invitations
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
-// @Param request body object{store_id=integer,email=string} true "Informations de l'invitation"
-// @Success 201 {object} object{message=string,code=string} "Invitation envoyée avec succès"
+// @Param request body requests.CreateInvitationRequest true "Informations de l'invitation"
+// @Success 201 {object} object{message=string,code=string,data=responses.InvitationResponse} "Invitation envoyée avec succès"
 // @Failure 400 {object} object{error=string} "Erreur dans la requête"
 // @Failure 401 {object} object{error=string} "Non authentifié"
 // @Failure 403 {object} object{error=string} "Accès non autorisé"
+// @Failure 404 {object} object{error=string} "Magasin introuvable"
+// @Failure 409 {object} object{error=string} "Invitation déjà en attente pour cet email"
 // @Router /api/invitations [post]
 func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
-	tokenString := c.GetHeader("Authorization")
-	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization token required"})
-		return
-	}
-
-	userID, _, isMerchant, _, err := utils.VerifyToken(tokenString)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-		return
-	}
-
-	if !isMerchant {
-		c.JSON(http.StatusForbidden, gin.H{"error": "only merchants can send invitations"})
-		return
-	}
-
-	var req struct {
-		StoreID uint   `json:"store_id" binding:"required"`
-		Email   string `json:"email" binding:"required,email"`
-	}
-
+	var req requests.CreateInvitationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	userID := c.MustGet("userId").(uint)
+
 	invitation, err := h.invitationService.CreateInvitation(userID, req.StoreID, req.Email)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Invitation sent successfully",
 		"code":    invitation.Code,
+		"data":    newInvitationResponse(invitation),
 	})
 }
 
 // AcceptInvitation accepte une invitation à rejoindre un store
 // @Summary Accepter une invitation à rejoindre un store
-// @Description Permet à un utilisateur d'accepter une invitation à rejoindre un store en tant que staff
+// @Description Permet à l'utilisateur invité d'accepter une invitation avec le lien signé reçu par email (token) ou avec son code. L'email du compte doit être celui de l'invitation.
 // @Tags invitations
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
-// @Param code query string true "Code d'invitation"
+// @Param token query string false "Jeton signé du lien d'invitation"
+// @Param code query string false "Code d'invitation"
 // @Success 200 {object} object{message=string} "Invitation acceptée avec succès"
 // @Failure 400 {object} object{error=string} "Erreur dans la requête"
 // @Failure 401 {object} object{error=string} "Non authentifié"
+// @Failure 403 {object} object{error=string} "Invitation adressée à un autre email"
+// @Failure 404 {object} object{error=string} "Invitation introuvable"
+// @Failure 409 {object} object{error=string} "Invitation expirée ou déjà traitée"
 // @Router /api/invitations/accept [get]
 func (h *InvitationHandler) AcceptInvitation(ctx *gin.Context) {
-	// Extraire userID du token
-	tokenString := ctx.GetHeader("Authorization")
-	if tokenString == "" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization token required"})
+	userID := ctx.MustGet("userId").(uint)
+
+	var err error
+	if token := ctx.Query("token"); token != "" {
+		err = h.invitationService.AcceptInvitationByToken(token, userID)
+	} else if code := ctx.Query("code"); code != "" {
+		err = h.invitationService.AcceptInvitationByCode(code, userID)
+	} else {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invitation token or code is required"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	userID, _, _, _, err := utils.VerifyToken(tokenString)
+	ctx.JSON(http.StatusOK, gin.H{"message": "You have successfully joined the store team"})
+}
+
+// GetMyInvitations godoc
+// @Summary Mes invitations
+// @Description Retourne les invitations en attente adressées à l'email de l'utilisateur connecté
+// @Tags invitations
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} models.Response{data=[]responses.InvitationResponse}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/invitations [get]
+func (h *InvitationHandler) GetMyInvitations(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	invitations, err := h.invitationService.GetMyInvitations(userID)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des invitations"})
 		return
 	}
 
-	// Get invitation code from query
-	code := ctx.Query("code")
-	if code == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invitation code is required"})
+	c.JSON(http.StatusOK, gin.H{"data": newInvitationResponses(invitations)})
+}
+
+// AcceptMyInvitation godoc
+// @Summary Accepter une invitation reçue
+// @Description Permet à l'utilisateur invité d'accepter une invitation de sa liste
+// @Tags invitations
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de l'invitation"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Invitation adressée à un autre email"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Invitation expirée ou déjà traitée"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/invitations/{id}/accept [post]
+func (h *InvitationHandler) AcceptMyInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID format"})
 		return
 	}
 
-	if err := h.invitationService.AcceptInvitation(code, userID); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	userID := c.MustGet("userId").(uint)
+
+	if err := h.invitationService.AcceptInvitation(uint(invitationID), userID); err != nil {
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "You have successfully joined the store team"})
+	c.JSON(http.StatusOK, gin.H{"message": "You have successfully joined the store team"})
+}
+
+// DeclineInvitation godoc
+// @Summary Refuser une invitation
+// @Description Permet à l'utilisateur invité de refuser une invitation qui lui est adressée
+// @Tags invitations
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de l'invitation"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Invitation adressée à un autre email"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Invitation expirée ou déjà traitée"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/invitations/{id}/decline [post]
+func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID format"})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	if err := h.invitationService.DeclineInvitation(uint(invitationID), userID); err != nil {
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 // GetPendingInvitations récupère les invitations en attente pour un magasin
 // @Summary Récupérer les invitations en attente pour un magasin
-// @Description Permet à un marchand de voir toutes les invitations en attente pour son magasin
+// @Description Permet au commerçant ou à un responsable de voir toutes les invitations en attente pour son magasin
 // @Tags invitations
 // @Produce json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
-// @Param storeId path integer true "ID du store"
-// @Success 200 {array} models.Invitation "Liste des invitations en attente"
+// @Param id path integer true "ID du store"
+// @Success 200 {object} models.Response{data=[]responses.InvitationResponse} "Liste des invitations en attente"
 // @Failure 400 {object} object{error=string} "Erreur dans la requête"
 // @Failure 401 {object} object{error=string} "Non authentifié"
 // @Failure 403 {object} object{error=string} "Accès non autorisé"
-// @Router /api/stores/{storeId}/invitations [get]
+// @Failure 404 {object} object{error=string} "Magasin introuvable"
+// @Router /api/stores/{id}/invitations [get]
 func (h *InvitationHandler) GetPendingInvitations(c *gin.Context) {
-	// Extraire userID du token
-	tokenString := c.GetHeader("Authorization")
-	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization token required"})
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID format"})
 		return
 	}
 
-	userID, _, isMerchant, _, err := utils.VerifyToken(tokenString)
+	userID := c.MustGet("userId").(uint)
+
+	invitations, err := h.invitationService.GetPendingInvitations(uint(storeID), userID)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	if !isMerchant {
-		c.JSON(http.StatusForbidden, gin.H{"error": "only merchants can view invitations"})
+	c.JSON(http.StatusOK, gin.H{"data": newInvitationResponses(invitations)})
+}
+
+// GetPendingInvitationsLegacy godoc
+// @Summary Récupérer les invitations en attente pour un magasin (ancienne route)
+// @Description Ancienne adresse de GET /api/stores/{id}/invitations, conservée pour les clients existants : même contrôle d'accès, liste renvoyée sans enveloppe data. Les en-têtes Deprecation et Link indiquent la route qui la remplace.
+// @Tags invitations
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path integer true "ID du store"
+// @Success 200 {array} responses.InvitationResponse "Liste des invitations en attente"
+// @Failure 400 {object} object{error=string} "Erreur dans la requête"
+// @Failure 401 {object} object{error=string} "Non authentifié"
+// @Failure 403 {object} object{error=string} "Accès non autorisé"
+// @Failure 404 {object} object{error=string} "Magasin introuvable"
+// @Deprecated
+// @Router /api/stores/{id}/request-status-statustions [get]
+func (h *InvitationHandler) GetPendingInvitationsLegacy(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID format"})
 		return
 	}
 
-	storeID := c.Param("id")
-	if storeID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "store ID is required"})
+	c.Header("Deprecation", "true")
+	c.Header("Link", fmt.Sprintf("</api/stores/%d/invitations>; rel=\"successor-version\"", storeID))
+
+	userID := c.MustGet("userId").(uint)
+
+	invitations, err := h.invitationService.GetPendingInvitations(uint(storeID), userID)
+	if err != nil {
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	var restIDUint uint
-	if _, err := fmt.Sscanf(storeID, "%d", &restIDUint); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID format"})
+	c.JSON(http.StatusOK, newInvitationResponses(invitations))
+}
+
+// ResendInvitation godoc
+// @Summary Renvoyer une invitation
+// @Description Renvoie une invitation en attente ou expirée avec un nouveau code et une validité de 7 jours ; les liens envoyés précédemment ne fonctionnent plus
+// @Tags invitations
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de l'invitation"
+// @Success 200 {object} object{message=string,code=string,data=responses.InvitationResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Invitation acceptée, refusée ou annulée, ou une autre invitation déjà en attente pour cette adresse"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/invitations/{id}/resend [post]
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID format"})
 		return
 	}
 
-	invitations, err := h.invitationService.GetPendingInvitations(restIDUint, userID)
+	userID := c.MustGet("userId").(uint)
+
+	invitation, err := h.invitationService.ResendInvitation(uint(invitationID), userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, invitations)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation sent successfully",
+		"code":    invitation.Code,
+		"data":    newInvitationResponse(invitation),
+	})
 }
 
 // CancelInvitation annule une invitation
 // @Summary Annuler une invitation
-// @Description Permet à un marchand d'annuler une invitation envoyée
+// @Description Permet au commerçant ou à un responsable du magasin d'annuler une invitation en attente
 // @Tags invitations
 // @Produce json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
-// @Param invitationId path integer true "ID de l'invitation"
+// @Param id path integer true "ID de l'invitation"
 // @Success 200 {object} object{message=string} "Invitation annulée avec succès"
 // @Failure 400 {object} object{error=string} "Erreur dans la requête"
 // @Failure 401 {object} object{error=string} "Non authentifié"
-// @Router /api/invitations/{invitationId} [delete]
+// @Failure 403 {object} object{error=string} "Accès non autorisé"
+// @Failure 404 {object} object{error=string} "Invitation introuvable"
+// @Failure 409 {object} object{error=string} "Invitation déjà traitée"
+// @Router /api/invitations/{id} [delete]
 func (h *InvitationHandler) CancelInvitation(c *gin.Context) {
-	// Extraire userID du token
-	tokenString := c.GetHeader("Authorization")
-	if tokenString == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization token required"})
-		return
-	}
-
-	userID, _, _, _, err := utils.VerifyToken(tokenString)
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID format"})
 		return
 	}
 
-	invitationID := c.Param("invitationId")
-	if invitationID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invitation ID is required"})
+	userID := c.MustGet("userId").(uint)
+
+	if err := h.invitationService.CancelInvitation(uint(invitationID), userID); err != nil {
+		c.JSON(invitationErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	var invIDUint uint
-	if _, err := fmt.Sscanf(invitationID, "%d", &invIDUint); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID format"})
-		return
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation cancelled successfully"})
+}
+
+// invitationErrorStatus retourne le code HTTP correspondant à une erreur de gestion des invitations
+func invitationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrInvitationNotFound), errors.Is(err, authz.ErrStoreNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, authz.ErrPermissionDenied), errors.Is(err, services.ErrInvitationEmailMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, repositories.ErrInvitationNotPending),
+		errors.Is(err, services.ErrInvitationExpired),
+		errors.Is(err, services.ErrInvitationExists),
+		errors.Is(err, services.ErrAlreadyStaffMember):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidInvitationToken):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
 	}
+}
 
-	if err := h.invitationService.CancelInvitation(invIDUint, userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+func newInvitationResponse(invitation *models.Invitation) responses.InvitationResponse {
+	return responses.InvitationResponse{
+		ID:        invitation.ID,
+		StoreID:   invitation.StoreID,
+		StoreName: invitation.Store.Name,
+		Email:     invitation.Email,
+		Status:    string(invitation.Status),
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
 	}
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Invitation cancelled successfully"})
+func newInvitationResponses(invitations []models.Invitation) []responses.InvitationResponse {
+	response := make([]responses.InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		response = append(response, newInvitationResponse(&invitations[i]))
+	}
+	return response
 }
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	service *services.NotificationService
+}
+
+func NewNotificationHandler(service *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// GetNotifications godoc
+// @Summary Boîte de réception
+// @Description Retourne les notifications de l'utilisateur connecté, de la plus récente à la plus ancienne, avec le nombre de notifications non lues
+// @Tags Notifications
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param unread query bool false "Uniquement les notifications non lues"
+// @Param page query int false "Numéro de page (à partir de 1)"
+// @Param page_size query int false "Nombre de notifications par page (20 par défaut, 100 au maximum)"
+// @Success 200 {object} responses.NotificationListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid unread"})
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page"})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page_size"})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	result, err := h.service.GetNotifications(userID, unreadOnly, page, pageSize)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNotificationPage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des notifications"})
+		return
+	}
+
+	response := responses.NotificationListResponse{
+		Data:        make([]responses.NotificationResponse, 0, len(result.Notifications)),
+		Total:       result.Total,
+		UnreadCount: result.Unread,
+		Page:        result.Page,
+		PageSize:    result.PageSize,
+	}
+	for i := range result.Notifications {
+		response.Data = append(response.Data, newNotificationResponse(&result.Notifications[i]))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MarkNotificationRead godoc
+// @Summary Marquer une notification comme lue
+// @Description Marque comme lue une notification de l'utilisateur connecté
+// @Tags Notifications
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de la notification"
+// @Success 200 {object} responses.NotificationResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID format"})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	notification, err := h.service.MarkRead(userID, uint(notificationID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour de la notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationResponse(notification))
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Tout marquer comme lu
+// @Description Marque comme lues toutes les notifications de l'utilisateur connecté
+// @Tags Notifications
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} responses.MarkAllReadResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/notifications/read-all [put]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	updated, err := h.service.MarkAllRead(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la mise à jour des notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.MarkAllReadResponse{Updated: updated})
+}
+
+// GetNotificationPreferences godoc
+// @Summary Préférences de notification
+// @Description Retourne les préférences de notification de l'utilisateur connecté
+// @Tags Notifications
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} responses.NotificationPreferencesResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/notification-preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	preference, err := h.service.GetPreferences(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des préférences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationPreferencesResponse(preference))
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Modifier les préférences de notification
+// @Description Active ou désactive les alertes de nouveaux paniers et les canaux de diffusion ; les champs absents sont conservés
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param input body requests.UpdateNotificationPreferencesRequest true "Préférences"
+// @Success 200 {object} responses.NotificationPreferencesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/notification-preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	var req requests.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	preference, err := h.service.UpdatePreferences(userID, req.NewBaskets, req.Email, req.Push, req.InApp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement des préférences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationPreferencesResponse(preference))
+}
+
+func newNotificationResponse(notification *models.Notification) responses.NotificationResponse {
+	return responses.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		StoreID:   notification.StoreID,
+		BasketID:  notification.BasketID,
+		OrderID:   notification.OrderID,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+func newNotificationPreferencesResponse(preference *models.NotificationPreference) responses.NotificationPreferencesResponse {
+	return responses.NotificationPreferencesResponse{
+		NewBaskets: preference.NewBaskets,
+		Email:      preference.Email,
+		Push:       preference.Push,
+		InApp:      preference.InApp,
+	}
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type OrderHandler struct {
+	service *services.OrderService
+}
+
+func NewOrderHandler(service *services.OrderService) *OrderHandler {
+	return &OrderHandler{service: service}
+}
+
+// ReserveBasket godoc
+// @Summary Réserver un panier
+// @Description Réserve un exemplaire d'un panier pour l'utilisateur connecté et génère un code de retrait unique
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param input body requests.CreateOrderRequest true "Panier à réserver"
+// @Success 201 {object} responses.OrderResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Panier introuvable"
+// @Failure 409 {object} models.ErrorResponse "Panier plus disponible ou plage de retrait terminée"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orders [post]
+func (h *OrderHandler) ReserveBasket(c *gin.Context) {
+	var req requests.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	order, err := h.service.ReserveBasket(userID, req.BasketID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrBasketNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrBasketUnavailable), errors.Is(err, services.ErrPickupWindowClosed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la réservation du panier"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, newOrderResponse(order))
+}
+
+// GetMyOrders godoc
+// @Summary Historique des commandes
+// @Description Retourne les commandes de l'utilisateur connecté, de la plus récente à la plus ancienne
+// @Tags Orders
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} responses.OrderResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orders/me [get]
+func (h *OrderHandler) GetMyOrders(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	orders, err := h.service.GetUserOrders(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des commandes"})
+		return
+	}
+
+	response := make([]responses.OrderResponse, 0, len(orders))
+	for i := range orders {
+		response = append(response, newOrderResponse(&orders[i]))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PayOrder godoc
+// @Summary Payer une commande
+// @Description Règle une commande en attente ; elle passe à l'état "confirmed" une fois le paiement réussi
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de la commande"
+// @Param input body requests.PayOrderRequest true "Moyen de paiement"
+// @Success 200 {object} responses.OrderResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 402 {object} models.ErrorResponse "Paiement refusé"
+// @Failure 403 {object} models.ErrorResponse "Commande d'un autre utilisateur"
+// @Failure 404 {object} models.ErrorResponse "Commande introuvable"
+// @Failure 409 {object} models.ErrorResponse "Commande déjà payée, annulée ou expirée"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orders/{id}/pay [post]
+func (h *OrderHandler) PayOrder(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req requests.PayOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	order, err := h.service.PayOrder(userID, uint(orderID), req.PaymentMethodID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrOrderNotOwned):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrOrderNotPending), errors.Is(err, services.ErrOrderExpired):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPaymentDeclined):
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du paiement de la commande"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrderResponse(order))
+}
+
+// VerifyOrder godoc
+// @Summary Valider un code de retrait
+// @Description Vérifie au comptoir le code de retrait (ou le QR code) d'une commande du magasin et la marque comme remise
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Param input body requests.VerifyOrderRequest true "Code de retrait ou contenu du QR code"
+// @Success 200 {object} responses.OrderResponse
+// @Failure 400 {object} models.ErrorResponse "Code manquant ou QR code invalide"
+// @Failure 403 {object} models.ErrorResponse "Commande d'un autre magasin"
+// @Failure 404 {object} models.ErrorResponse "Commande introuvable"
+// @Failure 409 {object} models.ErrorResponse "Commande non payée, déjà remise, annulée ou hors de la plage de retrait"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/stores/{id}/orders/verify [post]
+func (h *OrderHandler) VerifyOrder(c *gin.Context) {
+	var req requests.VerifyOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Code == "" && req.QRPayload == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code or qr_payload is required"})
+		return
+	}
+
+	storeID := c.MustGet("storeId").(uint)
+	staffID := c.MustGet("userId").(uint)
+
+	order, err := h.service.VerifyOrder(storeID, staffID, req.Code, req.QRPayload)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrInvalidQRPayload):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrOrderWrongStore):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrOrderNotPaid), errors.Is(err, services.ErrOrderClosed),
+			errors.Is(err, services.ErrPickupNotStarted), errors.Is(err, services.ErrPickupWindowClosed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la validation de la commande"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrderResponse(order))
+}
+
+func newOrderResponse(order *models.Order) responses.OrderResponse {
+	return responses.OrderResponse{
+		ID:          order.ID,
+		Code:        order.Code,
+		QRPayload:   utils.OrderQRPayload(order.Code),
+		Status:      order.Status,
+		Price:       order.Price,
+		BasketID:    order.BasketID,
+		BasketName:  order.Basket.Name,
+		StoreID:     order.StoreID,
+		StoreName:   order.Basket.Store.Name,
+		Address:     order.Basket.Store.Address,
+		ReservedAt:  order.ReservedAt,
+		ExpiredAt:   order.ExpiredAt,
+		PickupStart: order.PickupStart,
+		PickupEnd:   order.PickupEnd,
+	}
+}
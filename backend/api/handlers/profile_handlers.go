@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	service *services.ProfileService
+}
+
+func NewProfileHandler(service *services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{service: service}
+}
+
+// GetProfile godoc
+// @Summary Profil de l'utilisateur connecté
+// @Description Retourne le profil, les rôles (administrateur, marchand, équipes de magasin), le changement d'email en attente et les préférences de notification de l'utilisateur connecté
+// @Tags Users
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} responses.ProfileResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me [get]
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	profile, err := h.service.GetProfile(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération du profil"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newProfileResponse(profile))
+}
+
+// UpdateProfile godoc
+// @Summary Modifier le profil
+// @Description Modifie le nom affiché, le téléphone, la langue, le lieu de recherche par défaut et les préférences de notification ; les champs absents sont conservés
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param input body requests.UpdateProfileRequest true "Profil"
+// @Success 200 {object} responses.ProfileResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me [put]
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	var req requests.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	profile, err := h.service.UpdateProfile(userID, req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPhone) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Numéro de téléphone invalide, utilisez le format international (+32470123456)"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement du profil"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newProfileResponse(profile))
+}
+
+// ClearDefaultLocation godoc
+// @Summary Effacer le lieu de recherche par défaut
+// @Description Efface le lieu de recherche par défaut de l'utilisateur connecté
+// @Tags Users
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 204
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/default-location [delete]
+func (h *ProfileHandler) ClearDefaultLocation(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	if err := h.service.ClearDefaultLocation(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement du profil"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestEmailChange godoc
+// @Summary Changer d'adresse email
+// @Description Envoie un code de vérification, valable 10 minutes, à la nouvelle adresse après vérification du mot de passe. L'email du compte n'est remplacé qu'à la confirmation du code ; une nouvelle demande remplace la précédente.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param input body requests.ChangeEmailRequest true "Nouvelle adresse et mot de passe actuel"
+// @Success 202 {object} responses.PendingEmailResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/email [post]
+func (h *ProfileHandler) RequestEmailChange(c *gin.Context) {
+	var req requests.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	request, err := h.service.RequestEmailChange(userID, req.NewEmail, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPassword):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Mot de passe incorrect"})
+		case errors.Is(err, services.ErrEmailUnchanged):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cette adresse est déjà celle du compte"})
+		case errors.Is(err, repositories.ErrEmailAlreadyUsed):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cette adresse est déjà utilisée par un autre compte"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la demande de changement d'email"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, responses.PendingEmailResponse{Email: request.NewEmail, ExpiresAt: request.ExpiresAt})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirmer le changement d'email
+// @Description Remplace l'email du compte par la nouvelle adresse avec le code qui lui a été envoyé ; l'ancienne adresse en est avertie. Le code est bloqué après 5 saisies erronées. Le prochain rafraîchissement des jetons reflète la nouvelle adresse.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param input body requests.ConfirmEmailChangeRequest true "Code reçu à la nouvelle adresse"
+// @Success 200 {object} responses.ProfileResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/email/confirm [post]
+func (h *ProfileHandler) ConfirmEmailChange(c *gin.Context) {
+	var req requests.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Requête invalide"})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	profile, err := h.service.ConfirmEmailChange(userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrEmailChangeInvalid):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Code invalide ou expiré"})
+		case errors.Is(err, repositories.ErrEmailAlreadyUsed):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cette adresse est déjà utilisée par un autre compte"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du changement d'email"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newProfileResponse(profile))
+}
+
+// CancelEmailChange godoc
+// @Summary Annuler le changement d'email
+// @Description Annule le changement d'email en attente de l'utilisateur connecté
+// @Tags Users
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 204
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/email [delete]
+func (h *ProfileHandler) CancelEmailChange(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	if err := h.service.CancelEmailChange(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'annulation du changement d'email"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func newProfileResponse(profile *services.Profile) responses.ProfileResponse {
+	user := profile.User
+	response := responses.ProfileResponse{
+		ID:                      user.ID,
+		Email:                   user.Email,
+		EmailConfirmed:          user.IsEmailConfirmed,
+		DisplayName:             user.DisplayName,
+		Phone:                   user.Phone,
+		PreferredLanguage:       user.PreferredLanguage,
+		IsAdmin:                 user.IsAdmin,
+		IsMerchant:              profile.IsMerchant,
+		StaffStoreIDs:           profile.StaffStoreIDs,
+		NotificationPreferences: newNotificationPreferencesResponse(profile.Preferences),
+		CreatedAt:               user.CreatedAt,
+	}
+	if response.StaffStoreIDs == nil {
+		response.StaffStoreIDs = []uint{}
+	}
+	if pending := profile.PendingEmail; pending != nil {
+		response.PendingEmail = &responses.PendingEmailResponse{Email: pending.NewEmail, ExpiresAt: pending.ExpiresAt}
+	}
+	if user.DefaultLatitude != nil && user.DefaultLongitude != nil {
+		response.DefaultLocation = &responses.DefaultLocationResponse{
+			Label:     user.DefaultLocationLabel,
+			Latitude:  *user.DefaultLatitude,
+			Longitude: *user.DefaultLongitude,
+			RadiusKm:  user.DefaultRadiusKm,
+		}
+	}
+	return response
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	service *services.ReviewService
+}
+
+func NewReviewHandler(service *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{service: service}
+}
+
+// CreateReview godoc
+// @Summary Noter une commande
+// @Description Permet au client de noter (1 à 5) une de ses commandes remises, avec un commentaire facultatif. La note du magasin est recalculée.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de la commande"
+// @Param input body requests.CreateReviewRequest true "Avis"
+// @Success 201 {object} responses.ReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Commande d'un autre utilisateur"
+// @Failure 404 {object} models.ErrorResponse "Commande introuvable"
+// @Failure 409 {object} models.ErrorResponse "Commande non remise ou déjà notée"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orders/{id}/review [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req requests.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+
+	review, err := h.service.CreateReview(userID, uint(orderID), req.Rating, req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidReviewScore):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrOrderNotOwned):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrReviewNotAllowed), errors.Is(err, repositories.ErrReviewExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement de l'avis"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, newReviewResponse(review))
+}
+
+// GetStoreReviews godoc
+// @Summary Avis d'un magasin
+// @Description Retourne les avis visibles du magasin, du plus récent au plus ancien
+// @Tags Reviews
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Param page query int false "Numéro de page (à partir de 1)"
+// @Param page_size query int false "Nombre d'avis par page (20 par défaut, 100 au maximum)"
+// @Success 200 {object} responses.ReviewListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/stores/{id}/reviews [get]
+func (h *ReviewHandler) GetStoreReviews(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page"})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page_size"})
+		return
+	}
+
+	result, err := h.service.GetStoreReviews(uint(storeID), page, pageSize)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReviewPage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des avis"})
+		return
+	}
+
+	response := responses.ReviewListResponse{
+		Data:     make([]responses.ReviewResponse, 0, len(result.Reviews)),
+		Total:    result.Total,
+		Page:     result.Page,
+		PageSize: result.PageSize,
+	}
+	for i := range result.Reviews {
+		response.Data = append(response.Data, newReviewResponse(&result.Reviews[i]))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReplyToReview godoc
+// @Summary Répondre à un avis
+// @Description Permet au commerçant ou à un responsable du magasin de répondre à un avis ; une nouvelle réponse remplace la précédente
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Param reviewId path int true "ID de l'avis"
+// @Param input body requests.ReplyReviewRequest true "Réponse"
+// @Success 200 {object} responses.ReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/merchants/stores/{id}/reviews/{reviewId}/reply [put]
+func (h *ReviewHandler) ReplyToReview(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID format"})
+		return
+	}
+
+	var req requests.ReplyReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.service.ReplyToReview(uint(storeID), uint(reviewID), req.Reply)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrReviewNotFound),
+			errors.Is(err, services.ErrReviewWrongStore):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de l'enregistrement de la réponse"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newReviewResponse(review))
+}
+
+// ModerateReview godoc
+// @Summary Masquer ou réafficher un avis
+// @Description Permet à un administrateur de masquer un avis (ou de le réafficher) ; la note du magasin est recalculée
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de l'avis"
+// @Param input body requests.ModerateReviewRequest true "Modération"
+// @Success 200 {object} responses.ReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/reviews/{id} [put]
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID format"})
+		return
+	}
+
+	var req requests.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := c.MustGet("userId").(uint)
+
+	review, err := h.service.SetReviewHidden(uint(reviewID), adminID, *req.Hidden)
+	if err != nil {
+		if errors.Is(err, repositories.ErrReviewNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la modération de l'avis"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newReviewResponse(review))
+}
+
+// DeleteReview godoc
+// @Summary Supprimer un avis
+// @Description Permet à un administrateur de supprimer un avis ; la note du magasin est recalculée
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de l'avis"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/admin/reviews/{id} [delete]
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID format"})
+		return
+	}
+
+	if err := h.service.DeleteReview(uint(reviewID)); err != nil {
+		if errors.Is(err, repositories.ErrReviewNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la suppression de l'avis"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func newReviewResponse(review *models.Review) responses.ReviewResponse {
+	return responses.ReviewResponse{
+		ID:        review.ID,
+		OrderID:   review.OrderID,
+		StoreID:   review.StoreID,
+		Rating:    review.Rating,
+		Comment:   review.Comment,
+		Reply:     review.Reply,
+		RepliedAt: review.RepliedAt,
+		Hidden:    review.Hidden,
+		CreatedAt: review.CreatedAt,
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	service *services.SessionService
+}
+
+func NewSessionHandler(service *services.SessionService) *SessionHandler {
+	return &SessionHandler{service: service}
+}
+
+// refresh-token godoc
+// @Summary Rafraîchir le jeton d'accès
+// @Description Échange un refresh token contre un nouveau jeton d'accès et un nouveau refresh token. Le refresh token présenté ne peut plus servir : s'il est présenté de nouveau, la session entière est révoquée.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param refresh_token body requests.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} responses.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/refresh-token [post]
+func (h *SessionHandler) RefreshToken(c *gin.Context) {
+	var req requests.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	token, refreshToken, err := h.service.Refresh(req.RefreshToken, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenInvalid) || errors.Is(err, repositories.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du rafraîchissement de la session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.LoginResponse{Token: token, RefreshToken: refreshToken})
+}
+
+// logout godoc
+// @Summary Se déconnecter
+// @Description Révoque la session du refresh token ; le jeton d'accès en cours expire de lui-même
+// @Tags Sessions
+// @Accept json
+// @Param refresh_token body requests.RefreshTokenRequest true "Refresh token"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/logout [post]
+func (h *SessionHandler) Logout(c *gin.Context) {
+	var req requests.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.service.Logout(req.RefreshToken); err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenInvalid) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la déconnexion"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSessions godoc
+// @Summary Sessions actives
+// @Description Retourne les appareils sur lesquels l'utilisateur est connecté, le plus récemment utilisé en premier
+// @Tags Sessions
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} models.Response{data=[]responses.SessionResponse}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/sessions [get]
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+	currentID := c.MustGet("sessionId").(uint)
+
+	sessions, err := h.service.GetSessions(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des sessions"})
+		return
+	}
+
+	response := make([]responses.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, responses.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// RevokeSession godoc
+// @Summary Déconnecter un appareil
+// @Description Révoque une session de l'utilisateur : son refresh token ne peut plus servir
+// @Tags Sessions
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID de la session"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID format"})
+		return
+	}
+
+	userID := c.MustGet("userId").(uint)
+	if err := h.service.Revoke(userID, uint(sessionID)); err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session introuvable"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la révocation de la session"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary Se déconnecter de tous les appareils
+// @Description Révoque toutes les sessions de l'utilisateur, y compris la session courante
+// @Tags Sessions
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} map[string]int64
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/me/logout-all [post]
+func (h *SessionHandler) LogoutAll(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	revoked, err := h.service.LogoutAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la déconnexion"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"log"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
+	"github.com/Sebiche09/app-anti-gaspillage.git/events"
 	"github.com/Sebiche09/app-anti-gaspillage.git/geocoding"
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
 	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
 	"github.com/Sebiche09/app-anti-gaspillage.git/services"
 	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
@@ -9,24 +15,62 @@ import (
 )
 
 type Handlers struct {
-	User       *UserHandler
-	Basket     *BasketHandler
-	Merchant   *MerchantHandler
-	Store      *StoreHandler
-	Invitation *InvitationHandler
+	User         *UserHandler
+	Basket       *BasketHandler
+	Merchant     *MerchantHandler
+	Store        *StoreHandler
+	Invitation   *InvitationHandler
+	Order        *OrderHandler
+	Webhook      *WebhookHandler
+	Review       *ReviewHandler
+	Favorite     *FavoriteHandler
+	Notification *NotificationHandler
+	Stream       *StreamHandler
+	Session      *SessionHandler
+	Profile      *ProfileHandler
+}
+
+// Jobs regroupe les services des tâches périodiques, construits avec les mêmes instances que les handlers
+type Jobs struct {
+	Expiry      *services.ExpiryService
+	Publication *services.BasketPublicationService
+	Mail        *services.MailService
+	Refunds     *services.RefundService
 }
 
-func NewHandlers(db *gorm.DB) *Handlers {
+// NewHandlers construit les handlers de l'API et les tâches périodiques. basketBroker diffuse
+// les changements de disponibilité des paniers et orderBroker le cycle de vie des commandes.
+func NewHandlers(db *gorm.DB, basketBroker, orderBroker *events.Broker) (*Handlers, *Jobs) {
+	mailService := newMailService(db)
+	appURL := utils.GetEnvDefault("APP_URL", "http://localhost:3000")
+
 	userRepo := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepo)
+	passwordResetRepo := repositories.NewPasswordResetRepository(db)
+	sessionService := services.NewSessionService(repositories.NewSessionRepository(db), userRepo)
+	sessionHandler := NewSessionHandler(sessionService)
+	userService := services.NewUserService(userRepo, passwordResetRepo, sessionService, mailService, appURL)
 	userHandler := NewUserHandler(userService)
 
+	storeRepo := repositories.NewStoreRepository(db)
+	storeScheduleRepo := repositories.NewStoreScheduleRepository(db)
+
+	favoriteRepo := repositories.NewFavoriteRepository(db)
+	favoriteService := services.NewFavoriteService(favoriteRepo, storeRepo)
+	favoriteHandler := NewFavoriteHandler(favoriteService)
+
+	notificationService := newNotificationService(db, mailService)
+	notificationHandler := NewNotificationHandler(notificationService)
+
+	profileService := services.NewProfileService(userRepo, repositories.NewEmailChangeRepository(db), notificationService, mailService)
+	profileHandler := NewProfileHandler(profileService)
+
 	basketRepo := repositories.NewBasketRepository(db)
-	basketService := services.NewBasketService(basketRepo)
-	basketHandler := NewBasketHandler(basketService)
+	basketEvents := services.NewBasketEvents(basketBroker, basketRepo)
+	basketService := services.NewBasketService(basketRepo, storeRepo, storeScheduleRepo, notificationService, basketEvents)
+	basketHandler := NewBasketHandler(basketService, favoriteService)
 
 	merchantRepo := repositories.NewMerchantRepository(db)
-	merchantService := services.NewMerchantService(merchantRepo)
+	merchantService := services.NewMerchantService(merchantRepo, notificationService)
 	merchantHandler := NewMerchantHandler(merchantService)
 
 	geocodingConfig := geocoding.Config{
@@ -34,29 +78,172 @@ func NewHandlers(db *gorm.DB) *Handlers {
 	}
 	geocodingService := geocoding.NewService(geocodingConfig)
 
-	storeRepo := repositories.NewStoreRepository(db)
-	storeService := services.NewStoreService(storeRepo, merchantRepo, geocodingService)
-	storeHandler := NewStoreHandler(storeService)
-
 	invitationRepo := repositories.NewInvitationRepository(db)
 	storeStaffRepo := repositories.NewStoreStaffRepository(db)
+	policy := authz.NewPolicy(userRepo, storeRepo, merchantRepo, storeStaffRepo)
 
-	emailService := services.NewNoopEmailService()
+	storeService := services.NewStoreService(storeRepo, merchantRepo, storeScheduleRepo, storeStaffRepo, geocodingService)
+	storeHandler := NewStoreHandler(storeService, favoriteService)
 
 	invitationService := services.NewInvitationService(
 		invitationRepo,
 		storeRepo,
-		merchantRepo,
 		storeStaffRepo,
-		emailService,
+		userRepo,
+		policy,
+		mailService,
+		notificationService,
+		appURL,
 	)
 	invitationHandler := NewInvitationHandler(invitationService)
 
-	return &Handlers{
-		User:       userHandler,
-		Basket:     basketHandler,
-		Merchant:   merchantHandler,
-		Store:      storeHandler,
-		Invitation: invitationHandler,
+	orderRepo := repositories.NewOrderRepository(db)
+	orderEvents := services.NewOrderEvents(orderBroker, orderRepo)
+	streamHandler := NewStreamHandler(basketEvents, orderEvents, policy)
+	stripeCustomerRepo := repositories.NewStripeCustomerRepository(db)
+	paymentEventRepo := repositories.NewPaymentEventRepository(db)
+	paymentProvider := newPaymentProvider()
+	refundService := services.NewRefundService(repositories.NewPaymentRefundRepository(db), paymentProvider, utils.SystemClock{})
+	orderService := services.NewOrderService(orderRepo, stripeCustomerRepo, userRepo, paymentEventRepo, paymentProvider, refundService, notificationService, basketEvents, orderEvents)
+	orderHandler := NewOrderHandler(orderService)
+
+	paymentWebhookService := services.NewPaymentWebhookService(
+		utils.GetEnvDefault("PAYMENT_WEBHOOK_SECRET", ""),
+		orderService,
+		utils.SystemClock{},
+	)
+	webhookHandler := NewWebhookHandler(paymentWebhookService)
+
+	reviewRepo := repositories.NewReviewRepository(db)
+	reviewService := services.NewReviewService(reviewRepo, orderRepo)
+	reviewHandler := NewReviewHandler(reviewService)
+
+	expiryService := services.NewExpiryService(
+		orderRepo,
+		basketRepo,
+		invitationRepo,
+		notificationService,
+		basketEvents,
+		orderEvents,
+		utils.SystemClock{},
+	)
+	publicationService := services.NewBasketPublicationService(
+		basketRepo,
+		storeScheduleRepo,
+		notificationService,
+		basketEvents,
+		utils.SystemClock{},
+	)
+
+	handlers := &Handlers{
+		User:         userHandler,
+		Basket:       basketHandler,
+		Merchant:     merchantHandler,
+		Store:        storeHandler,
+		Invitation:   invitationHandler,
+		Order:        orderHandler,
+		Webhook:      webhookHandler,
+		Review:       reviewHandler,
+		Favorite:     favoriteHandler,
+		Notification: notificationHandler,
+		Stream:       streamHandler,
+		Session:      sessionHandler,
+		Profile:      profileHandler,
+	}
+	jobs := &Jobs{
+		Expiry:      expiryService,
+		Publication: publicationService,
+		Mail:        mailService,
+		Refunds:     refundService,
+	}
+	return handlers, jobs
+}
+
+// newNotificationService construit le service de notification avec ses canaux : in-app, push (journalisé)
+// et email, mis dans l'outbox de mailService.
+// BASKET_ALERT_INTERVAL (durée Go, 30m par défaut) limite la fréquence des alertes de nouveau panier.
+func newNotificationService(db *gorm.DB, mailService *services.MailService) *services.NotificationService {
+	notificationRepo := repositories.NewNotificationRepository(db)
+	notifiers := []services.Notifier{
+		services.NewInAppNotifier(notificationRepo),
+		services.NewPushNotifier(),
+		services.NewEmailNotifier(mailService),
+	}
+
+	alertInterval := services.DefaultBasketAlertInterval
+	if value := utils.GetEnvDefault("BASKET_ALERT_INTERVAL", ""); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < 0 {
+			log.Fatalf("BASKET_ALERT_INTERVAL invalide : %s", value)
+		}
+		alertInterval = interval
+	}
+
+	return services.NewNotificationService(
+		repositories.NewFavoriteRepository(db),
+		notificationRepo,
+		repositories.NewStoreRepository(db),
+		repositories.NewUserRepository(db),
+		notifiers,
+		mailService,
+		utils.SystemClock{},
+		alertInterval,
+	)
+}
+
+// newMailService construit le service des emails transactionnels, expédiés par EMAIL_FROM.
+// MAIL_TRANSPORT choisit le transport : "smtp" (SMTP_HOST, SMTP_PORT, SMTP_USER, SMTP_PASS) ou "file",
+// qui écrit les emails dans MAIL_DIR (tmp/mail par défaut) ; par défaut SMTP si SMTP_HOST est défini.
+func newMailService(db *gorm.DB) *services.MailService {
+	return services.NewMailService(
+		repositories.NewEmailOutboxRepository(db),
+		mailer.NewRenderer(),
+		newMailTransport(),
+		utils.GetEnvDefault("EMAIL_FROM", "no-reply@localhost"),
+		utils.SystemClock{},
+	)
+}
+
+func newMailTransport() mailer.Transport {
+	transport := utils.GetEnvDefault("MAIL_TRANSPORT", "")
+	if transport == "" {
+		transport = "file"
+		if utils.GetEnvDefault("SMTP_HOST", "") != "" {
+			transport = "smtp"
+		}
+	}
+
+	switch transport {
+	case "smtp":
+		return mailer.NewSMTPTransport(
+			utils.GetEnv("SMTP_HOST"),
+			utils.GetEnv("SMTP_PORT"),
+			utils.GetEnvDefault("SMTP_USER", ""),
+			utils.GetEnvDefault("SMTP_PASS", ""),
+		)
+	case "file":
+		return mailer.NewFileTransport(utils.GetEnvDefault("MAIL_DIR", "tmp/mail"))
+	default:
+		log.Fatalf("MAIL_TRANSPORT invalide : %s", transport)
+		return nil
+	}
+}
+
+// newPaymentProvider choisit le prestataire de paiement selon PAYMENT_PROVIDER, obligatoire :
+// "stripe" utilise l'API Stripe, "fake" un prestataire fictif pour le développement local,
+// qui accepte tous les paiements
+func newPaymentProvider() services.PaymentProvider {
+	switch provider := utils.GetEnvDefault("PAYMENT_PROVIDER", ""); provider {
+	case "stripe":
+		return services.NewStripePaymentProvider(
+			utils.GetEnv("STRIPE_SECRET_KEY"),
+			utils.GetEnvDefault("STRIPE_API_BASE_URL", ""),
+		)
+	case "fake":
+		log.Println("⚠️ PAYMENT_PROVIDER=fake : les paiements sont simulés")
+		return services.NewFakePaymentProvider()
+	default:
+		log.Fatalf("PAYMENT_PROVIDER invalide (stripe ou fake attendu) : %q", provider)
+		return nil
 	}
 }
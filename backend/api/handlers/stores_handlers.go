@@ -1,20 +1,28 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
 	"github.com/Sebiche09/app-anti-gaspillage.git/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type StoreHandler struct {
-	service *services.StoreService
+	service         *services.StoreService
+	favoriteService *services.FavoriteService
 }
 
-func NewStoreHandler(service *services.StoreService) *StoreHandler {
-	return &StoreHandler{service: service}
+func NewStoreHandler(service *services.StoreService, favoriteService *services.FavoriteService) *StoreHandler {
+	return &StoreHandler{service: service, favoriteService: favoriteService}
 }
 
 // summary: Récupérer toutes les catégories
@@ -62,6 +70,10 @@ func (h *StoreHandler) CreateStore(c *gin.Context) {
 	userID := c.MustGet("userId").(uint)
 
 	if err := h.service.CreateStore(req, userID); err != nil {
+		if errors.Is(err, services.ErrInvalidTimeZone) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -79,7 +91,10 @@ func (h *StoreHandler) CreateStore(c *gin.Context) {
 // @Param id path int true "Magasin ID"
 // @Param input body requests.UpdateStoreRequest true "Données de la demande"
 // @Success 200 {object} models.Response
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Magasin d'un autre commerçant"
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/merchants/stores/{id} [put]
 func (h *StoreHandler) UpdateStore(c *gin.Context) {
@@ -96,6 +111,10 @@ func (h *StoreHandler) UpdateStore(c *gin.Context) {
 	}
 
 	if err := h.service.UpdateStore(req, uint(id)); err != nil {
+		if errors.Is(err, services.ErrInvalidTimeZone) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -113,6 +132,8 @@ func (h *StoreHandler) UpdateStore(c *gin.Context) {
 // @Param id path int true "Magasin ID"
 // @Success 200 {object} models.Response
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Magasin d'un autre commerçant"
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /api/merchants/stores/{id} [delete]
 func (h *StoreHandler) DeleteStore(c *gin.Context) {
@@ -171,6 +192,15 @@ func (h *StoreHandler) GetStores(c *gin.Context) {
 		return
 	}
 
+	favorites, err := h.favoriteService.FavoriteStoreIDs(c.MustGet("userId").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range stores {
+		stores[i].IsFavorite = favorites[stores[i].ID]
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": stores})
 }
 
@@ -182,7 +212,7 @@ func (h *StoreHandler) GetStores(c *gin.Context) {
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "ID du magasin"
-// @Success 200 {object} models.Response
+// @Success 200 {object} responses.StoreDetailResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
@@ -198,27 +228,54 @@ func (h *StoreHandler) GetStore(c *gin.Context) {
 	storeID := uint(parsedID)
 
 	store, err := h.service.GetStoreByID(storeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Magasin introuvable"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	availability, err := h.service.GetStoreAvailability(store, time.Now())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": store})
+	favorites, err := h.favoriteService.FavoriteStoreIDs(c.MustGet("userId").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	store.IsFavorite = favorites[store.ID]
+
+	c.JSON(http.StatusOK, gin.H{"data": responses.StoreDetailResponse{
+		Store:         *store,
+		OpenNow:       availability.OpenNow,
+		ClosesAt:      availability.ClosesAt,
+		NextOpeningAt: availability.NextOpeningAt,
+		OpeningHours:  newOpeningHourResponses(availability.OpeningHours),
+		Closures:      newStoreClosureResponses(availability.Closures),
+	}})
 }
 
-// summary: Obtenir les membres d'un magasin
-// description: Permet de récupérer la liste des membres d'un magasin
+// GeStoreStaff godoc
+// @Summary Obtenir les membres d'un magasin
+// @Description Retourne les membres du staff du magasin avec leur rôle ; réservé au commerçant propriétaire et aux responsables
 // @Tags Stores
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "ID du magasin"
-// @Success 200 {object} models.Response
+// @Success 200 {object} models.Response{data=[]responses.StaffMemberResponse}
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/stores/{id}/staffs [get]
+// @Router /api/merchants/stores/{id}/staff [get]
 func (h *StoreHandler) GeStoreStaff(c *gin.Context) {
 	id := c.Param("id")
 
@@ -231,47 +288,131 @@ func (h *StoreHandler) GeStoreStaff(c *gin.Context) {
 
 	members, err := h.service.GetStoreStaff(storeID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(staffErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": members})
+	response := make([]responses.StaffMemberResponse, 0, len(members))
+	for i := range members {
+		response = append(response, newStaffMemberResponse(&members[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
 }
 
-// summmary: Obtenir les configurations panier du magasin
-// description: Permet de récupérer les configurations panier du magasin
+// UpdateStaffRole godoc
+// @Summary Changer le rôle d'un membre du staff
+// @Description Permet au commerçant propriétaire de nommer un membre du staff responsable (manager) ou employé (clerk)
 // @Tags Stores
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "ID du magasin"
-// @Success 200 {object} models.Response
-// @Failure 401 {object} models.ErrorResponse
+// @Param userId path int true "ID de l'utilisateur membre du staff"
+// @Param input body requests.UpdateStaffRoleRequest true "Rôle"
+// @Success 200 {object} responses.StaffMemberResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/stores/{id}/basket-config [get]
-func (h *StoreHandler) GetStoreBasketConfig(c *gin.Context) {
-	id := c.Param("id")
+// @Router /api/merchants/stores/{id}/staff/{userId} [put]
+func (h *StoreHandler) UpdateStaffRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
 
-	parsedID, err := strconv.ParseUint(id, 10, 32)
+	var req requests.UpdateStaffRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	storeID := c.MustGet("storeId").(uint)
+	actor := c.MustGet("storeRole").(authz.Role)
+
+	member, err := h.service.UpdateStaffRole(storeID, uint(userID), req.Role, actor)
+	if err != nil {
+		c.JSON(staffErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newStaffMemberResponse(member))
+}
+
+// RemoveStaffMember godoc
+// @Summary Retirer un membre du staff
+// @Description Retire l'utilisateur de l'équipe du magasin ; ses accès au magasin sont révoqués immédiatement, sans attendre l'expiration de son JWT. Un responsable ne peut retirer que des employés.
+// @Tags Stores
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Param userId path int true "ID de l'utilisateur membre du staff"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/merchants/stores/{id}/staff/{userId} [delete]
+func (h *StoreHandler) RemoveStaffMember(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	storeID := c.MustGet("storeId").(uint)
+	actorID := c.MustGet("userId").(uint)
+	actor := c.MustGet("storeRole").(authz.Role)
+
+	if err := h.service.RemoveStaffMember(storeID, uint(userID), actorID, actor); err != nil {
+		c.JSON(staffErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// summary: Obtenir les configurations panier du magasin
+// description: Permet au commerçant de récupérer les configurations panier de son magasin
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Success 200 {array} responses.BasketConfigurationResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/merchants/stores/{id}/basket-configurations [get]
+func (h *StoreHandler) GetStoreBasketConfigs(c *gin.Context) {
+	parsedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
 		return
 	}
-	storeID := uint(parsedID)
 
-	config, err := h.service.GetStoreBasketConfig(storeID)
+	configs, err := h.service.GetStoreBasketConfigs(uint(parsedID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": config})
+	response := make([]responses.BasketConfigurationResponse, 0, len(configs))
+	for i := range configs {
+		response = append(response, newBasketConfigurationResponse(&configs[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
 }
 
 // summary: Ajouter une configuration panier au magasin
-// description: Permet d'ajouter une configuration panier au magasin
+// description: Permet au commerçant d'ajouter une configuration panier à son magasin. Si des jours de publication sont renseignés, un panier est publié automatiquement chacun de ces jours.
 // @Tags Stores
 // @Accept json
 // @Produce json
@@ -279,21 +420,19 @@ func (h *StoreHandler) GetStoreBasketConfig(c *gin.Context) {
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "ID du magasin"
 // @Param input body requests.CreateBasketConfigurationRequest true "Données de la demande"
-// @Success 201 {object} models.Response
+// @Success 201 {object} responses.BasketConfigurationResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/stores/{id}/basket-config [post]
+// @Router /api/merchants/stores/{id}/basket-configurations [post]
 func (h *StoreHandler) CreateStoreBasketConfig(c *gin.Context) {
-	id := c.Param("id")
-
-	parsedID, err := strconv.ParseUint(id, 10, 32)
+	parsedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
 		return
 	}
-	storeID := uint(parsedID)
 
 	var req requests.CreateBasketConfigurationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -301,38 +440,43 @@ func (h *StoreHandler) CreateStoreBasketConfig(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateStoreBasketConfig(req, storeID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	config, err := h.service.CreateStoreBasketConfig(req, uint(parsedID))
+	if err != nil {
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Configuration panier ajoutée avec succès"})
+	c.JSON(http.StatusCreated, gin.H{"data": newBasketConfigurationResponse(config)})
 }
 
-// summary: Mettre à jour la configuration panier du magasin
-// description: Permet de mettre à jour la configuration panier du magasin
+// summary: Mettre à jour une configuration panier du magasin
+// description: Permet au commerçant de mettre à jour une configuration panier de son magasin
 // @Tags Stores
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "ID du magasin"
+// @Param configId path int true "ID de la configuration"
 // @Param input body requests.UpdateBasketConfigurationRequest true "Données de la demande"
-// @Success 200 {object} models.Response
+// @Success 200 {object} responses.BasketConfigurationResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/stores/{id}/basket-config [put]
+// @Router /api/merchants/stores/{id}/basket-configurations/{configId} [put]
 func (h *StoreHandler) UpdateStoreBasketConfig(c *gin.Context) {
-	id := c.Param("id")
-
-	parsedID, err := strconv.ParseUint(id, 10, 32)
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
 		return
 	}
-	storeID := uint(parsedID)
+	configID, err := strconv.ParseUint(c.Param("configId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid configuration ID format"})
+		return
+	}
 
 	var req requests.UpdateBasketConfigurationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -340,41 +484,326 @@ func (h *StoreHandler) UpdateStoreBasketConfig(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.UpdateStoreBasketConfig(req, storeID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	config, err := h.service.UpdateStoreBasketConfig(req, uint(storeID), uint(configID))
+	if err != nil {
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Configuration panier mise à jour avec succès"})
+	c.JSON(http.StatusOK, gin.H{"data": newBasketConfigurationResponse(config)})
 }
 
-// summary: Supprimer la configuration panier du magasin
-// description: Permet de supprimer la configuration panier du magasin
+// summary: Supprimer une configuration panier du magasin
+// description: Permet au commerçant de supprimer une configuration panier de son magasin ; les paniers déjà publiés sont conservés
 // @Tags Stores
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param Authorization header string true "Bearer token"
 // @Param id path int true "ID du magasin"
+// @Param configId path int true "ID de la configuration"
 // @Success 200 {object} models.Response
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /api/stores/{id}/basket-config [delete]
+// @Router /api/merchants/stores/{id}/basket-configurations/{configId} [delete]
 func (h *StoreHandler) DeleteStoreBasketConfig(c *gin.Context) {
-	id := c.Param("id")
-
-	parsedID, err := strconv.ParseUint(id, 10, 32)
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
 		return
 	}
-	storeID := uint(parsedID)
+	configID, err := strconv.ParseUint(c.Param("configId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid configuration ID format"})
+		return
+	}
 
-	if err := h.service.DeleteStoreBasketConfig(storeID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.DeleteStoreBasketConfig(uint(storeID), uint(configID)); err != nil {
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Configuration panier supprimée avec succès"})
 }
+
+// summary: Remplacer les horaires d'ouverture du magasin
+// description: Permet au commerçant de définir le planning hebdomadaire complet de son magasin ; une liste vide supprime les horaires
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Param input body requests.UpdateOpeningHoursRequest true "Planning hebdomadaire"
+// @Success 200 {array} responses.OpeningHourResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/merchants/stores/{id}/opening-hours [put]
+func (h *StoreHandler) UpdateOpeningHours(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var req requests.UpdateOpeningHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hours, err := h.service.ReplaceOpeningHours(req, uint(storeID))
+	if err != nil {
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newOpeningHourResponses(hours)})
+}
+
+// summary: Obtenir les fermetures exceptionnelles du magasin
+// description: Permet au commerçant de récupérer les fermetures exceptionnelles à venir de son magasin
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Success 200 {array} responses.StoreClosureResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/merchants/stores/{id}/closures [get]
+func (h *StoreHandler) GetStoreClosures(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	closures, err := h.service.GetStoreClosures(uint(storeID))
+	if err != nil {
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newStoreClosureResponses(closures)})
+}
+
+// summary: Ajouter une fermeture exceptionnelle au magasin
+// description: Permet au commerçant de déclarer une fermeture exceptionnelle (jours fériés, congés...)
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Param input body requests.StoreClosureRequest true "Fermeture exceptionnelle"
+// @Success 201 {object} responses.StoreClosureResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/merchants/stores/{id}/closures [post]
+func (h *StoreHandler) CreateStoreClosure(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var req requests.StoreClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	closure, err := h.service.CreateStoreClosure(req, uint(storeID))
+	if err != nil {
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": newStoreClosureResponse(closure)})
+}
+
+// summary: Mettre à jour une fermeture exceptionnelle du magasin
+// description: Permet au commerçant de modifier une fermeture exceptionnelle de son magasin
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Param closureId path int true "ID de la fermeture"
+// @Param input body requests.StoreClosureRequest true "Fermeture exceptionnelle"
+// @Success 200 {object} responses.StoreClosureResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/merchants/stores/{id}/closures/{closureId} [put]
+func (h *StoreHandler) UpdateStoreClosure(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	closureID, err := strconv.ParseUint(c.Param("closureId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid closure ID format"})
+		return
+	}
+
+	var req requests.StoreClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	closure, err := h.service.UpdateStoreClosure(req, uint(storeID), uint(closureID))
+	if err != nil {
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": newStoreClosureResponse(closure)})
+}
+
+// summary: Supprimer une fermeture exceptionnelle du magasin
+// description: Permet au commerçant de supprimer une fermeture exceptionnelle de son magasin
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Param closureId path int true "ID de la fermeture"
+// @Success 200 {object} models.Response
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/merchants/stores/{id}/closures/{closureId} [delete]
+func (h *StoreHandler) DeleteStoreClosure(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	closureID, err := strconv.ParseUint(c.Param("closureId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid closure ID format"})
+		return
+	}
+
+	if err := h.service.DeleteStoreClosure(uint(storeID), uint(closureID)); err != nil {
+		c.JSON(merchantStoreErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Fermeture supprimée avec succès"})
+}
+
+// merchantStoreErrorStatus retourne le code HTTP correspondant à une erreur de gestion d'un magasin
+// (configurations panier, horaires, fermetures)
+func merchantStoreErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrStoreNotFound),
+		errors.Is(err, repositories.ErrBasketConfigurationNotFound),
+		errors.Is(err, repositories.ErrStoreClosureNotFound):
+		return http.StatusNotFound
+	case isPickupWindowError(err),
+		errors.Is(err, services.ErrInvalidWeekday),
+		errors.Is(err, services.ErrScheduleRequiresPickup),
+		errors.Is(err, services.ErrInvalidOpeningHours),
+		errors.Is(err, services.ErrOverlappingOpeningHours),
+		errors.Is(err, services.ErrInvalidClosureDates):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// staffErrorStatus retourne le code HTTP correspondant à une erreur de gestion de l'équipe du magasin
+func staffErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrStoreNotFound),
+		errors.Is(err, services.ErrStaffMemberNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrStaffChangeForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrInvalidStaffRole):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func newStaffMemberResponse(member *models.StoreStaff) responses.StaffMemberResponse {
+	return responses.StaffMemberResponse{
+		UserID: member.UserID,
+		Email:  member.User.Email,
+		Role:   member.Role,
+	}
+}
+
+func newBasketConfigurationResponse(config *models.BasketConfiguration) responses.BasketConfigurationResponse {
+	var lastPublishedOn *string
+	if config.LastPublishedOn != nil {
+		day := services.DateOnly(*config.LastPublishedOn)
+		lastPublishedOn = &day
+	}
+
+	return responses.BasketConfigurationResponse{
+		ID:                 config.ID,
+		StoreID:            config.StoreID,
+		Name:               config.Name,
+		Description:        config.Description,
+		DiscountPercentage: config.DiscountPercentage,
+		OriginalPrice:      config.OriginalPrice,
+		Quantity:           config.Quantity,
+		PickupStartTime:    config.PickupStartTime,
+		PickupEndTime:      config.PickupEndTime,
+		Weekdays:           config.WeekdayList(),
+		Active:             config.Active,
+		LastPublishedOn:    lastPublishedOn,
+	}
+}
+
+func newOpeningHourResponses(hours []models.StoreOpeningHour) []responses.OpeningHourResponse {
+	response := make([]responses.OpeningHourResponse, 0, len(hours))
+	for _, hour := range hours {
+		response = append(response, responses.OpeningHourResponse{
+			Weekday:  hour.Weekday,
+			OpensAt:  hour.OpensAt,
+			ClosesAt: hour.ClosesAt,
+		})
+	}
+	return response
+}
+
+func newStoreClosureResponse(closure *models.StoreClosure) responses.StoreClosureResponse {
+	return responses.StoreClosureResponse{
+		ID:        closure.ID,
+		StartDate: services.DateOnly(closure.StartDate),
+		EndDate:   services.DateOnly(closure.EndDate),
+		Reason:    closure.Reason,
+	}
+}
+
+func newStoreClosureResponses(closures []models.StoreClosure) []responses.StoreClosureResponse {
+	response := make([]responses.StoreClosureResponse, 0, len(closures))
+	for i := range closures {
+		response = append(response, newStoreClosureResponse(&closures[i]))
+	}
+	return response
+}
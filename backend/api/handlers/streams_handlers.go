@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
+	"github.com/Sebiche09/app-anti-gaspillage.git/events"
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat est l'intervalle des commentaires envoyés pour garder ouverte une connexion sans événement
+const streamHeartbeat = 20 * time.Second
+
+// orderSnapshotEvent est le type de l'événement qui transmet l'état complet des commandes ouvertes
+const orderSnapshotEvent = "orders.snapshot"
+
+type StreamHandler struct {
+	basketEvents *services.BasketEvents
+	orderEvents  *services.OrderEvents
+	policy       *authz.Policy
+}
+
+func NewStreamHandler(basketEvents *services.BasketEvents, orderEvents *services.OrderEvents, policy *authz.Policy) *StreamHandler {
+	return &StreamHandler{basketEvents: basketEvents, orderEvents: orderEvents, policy: policy}
+}
+
+// StreamStoreBaskets godoc
+// @Summary Flux temps réel des paniers d'un magasin
+// @Description Server-Sent Events : un événement (basket.created, basket.updated, basket.reserved, basket.sold_out, basket.restocked, basket.deleted, basket.expired) est envoyé à chaque changement de quantité ou de statut d'un panier du magasin
+// @Tags Baskets
+// @Produce text/event-stream
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "ID du magasin"
+// @Success 200 {object} responses.BasketStreamEvent "Contenu (data) de chaque événement"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/stores/{id}/baskets/stream [get]
+func (h *StreamHandler) StreamStoreBaskets(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	streamEvents(c, h.basketEvents.SubscribeStore(uint(storeID)), newBasketStreamEvent)
+}
+
+// StreamNearbyBaskets godoc
+// @Summary Flux temps réel des paniers autour d'un point
+// @Description Server-Sent Events : mêmes événements que le flux d'un magasin, pour tous les magasins situés à moins de radius_km du point donné
+// @Tags Baskets
+// @Produce text/event-stream
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param lat query number true "Latitude du point"
+// @Param lng query number true "Longitude du point"
+// @Param radius_km query number false "Rayon en km (10 par défaut, 100 au maximum)"
+// @Success 200 {object} responses.BasketStreamEvent "Contenu (data) de chaque événement"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/baskets/stream [get]
+func (h *StreamHandler) StreamNearbyBaskets(c *gin.Context) {
+	search, err := parseBasketSearch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if search.Latitude == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lat and lng are required"})
+		return
+	}
+
+	streamEvents(c, h.basketEvents.SubscribeNearby(*search.Latitude, *search.Longitude, search.RadiusKm), newBasketStreamEvent)
+}
+
+// StreamStoreOrders godoc
+// @Summary Flux temps réel des commandes du magasin
+// @Description Server-Sent Events pour l'écran des retraits : un événement (order.reserved, order.paid, order.cancelled, order.picked_up) est envoyé à chaque étape du cycle de vie d'une commande des magasins dont l'utilisateur est propriétaire ou membre du staff.
+// @Description Les droits sont vérifiés en base à la connexion puis régulièrement : le flux est fermé dès que l'utilisateur est retiré du staff.
+// @Description À la connexion, un événement orders.snapshot contient les commandes en attente de paiement ou de retrait. Un client qui se reconnecte avec l'en-tête Last-Event-ID (ou le paramètre last_event_id) reçoit les événements manqués à la place, ou un nouveau snapshot s'ils ne sont plus disponibles.
+// @Tags Orders
+// @Produce text/event-stream
+// @Security Bearer
+// @Param Authorization header string true "Bearer token"
+// @Param store_id query int false "Limite le flux à un magasin"
+// @Param Last-Event-ID header string false "ID du dernier événement reçu"
+// @Param last_event_id query int false "ID du dernier événement reçu (clients ne pouvant pas définir d'en-tête)"
+// @Success 200 {object} responses.OrderStreamEvent "Contenu (data) de chaque événement order.* ; orders.snapshot contient responses.OrderSnapshotEvent"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/orders/stream [get]
+func (h *StreamHandler) StreamStoreOrders(c *gin.Context) {
+	userID := c.MustGet("userId").(uint)
+
+	var storeIDs []uint
+	if storeID := c.Query("store_id"); storeID != "" {
+		parsedID, err := strconv.ParseUint(storeID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store_id"})
+			return
+		}
+		if _, err := h.policy.Authorize(userID, uint(parsedID), authz.PermissionOrdersRead); err != nil {
+			switch {
+			case errors.Is(err, authz.ErrStoreNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, authz.ErrPermissionDenied):
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification des droits"})
+			}
+			return
+		}
+		storeIDs = []uint{uint(parsedID)}
+	} else {
+		var err error
+		if storeIDs, err = h.policy.StoreIDs(userID, authz.PermissionOrdersRead); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la vérification des droits"})
+			return
+		}
+	}
+	if len(storeIDs) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not a staff member of any store"})
+		return
+	}
+
+	var lastID *uint64
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		parsedID, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+		lastID = &parsedID
+	}
+
+	// L'abonnement précède la lecture du snapshot : un changement concurrent est au pire reçu deux fois
+	sub, complete := h.orderEvents.Subscribe(storeIDs, lastID)
+	defer sub.Close()
+
+	var snapshot []services.OrderEvent
+	if !complete {
+		var err error
+		if snapshot, err = h.orderEvents.OpenOrders(storeIDs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la récupération des commandes"})
+			return
+		}
+	}
+
+	openStream(c)
+	if !complete {
+		response := responses.OrderSnapshotEvent{Orders: make([]responses.OrderStreamEvent, 0, len(snapshot))}
+		for i := range snapshot {
+			response.Orders = append(response.Orders, newOrderStreamEvent(&snapshot[i]))
+		}
+		writeEvent(c, sub.StartID(), orderSnapshotEvent, response)
+	}
+	// Un membre retiré du staff ne doit plus recevoir les commandes, même si sa connexion reste ouverte
+	authorized := func() bool {
+		for _, storeID := range storeIDs {
+			if _, err := h.policy.Authorize(userID, storeID, authz.PermissionOrdersRead); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+	pumpEvents(c, sub, func(event events.Event) interface{} {
+		order, _ := event.Data.(services.OrderEvent)
+		return newOrderStreamEvent(&order)
+	}, authorized)
+}
+
+// streamEvents envoie les événements de l'abonnement au client au format Server-Sent Events
+// jusqu'à sa déconnexion ou la fin de l'abonnement, qui est libéré dans tous les cas
+func streamEvents(c *gin.Context, sub *events.Subscription, render func(event events.Event) interface{}) {
+	defer sub.Close()
+
+	openStream(c)
+	pumpEvents(c, sub, render, nil)
+}
+
+// openStream envoie les en-têtes d'une réponse Server-Sent Events
+func openStream(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Désactive la mise en tampon des proxys nginx
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+}
+
+// writeEvent envoie un événement au client
+func writeEvent(c *gin.Context, id uint64, eventType string, data interface{}) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatUint(id, 10),
+		Event: eventType,
+		Data:  data,
+	})
+	c.Writer.Flush()
+}
+
+// pumpEvents transmet les événements de l'abonnement jusqu'à la déconnexion du client ou la fin de l'abonnement.
+// Si authorized est renseigné, il est appelé à chaque heartbeat et le flux est fermé s'il retourne false.
+func pumpEvents(c *gin.Context, sub *events.Subscription, render func(event events.Event) interface{}, authorized func() bool) {
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			writeEvent(c, event.ID, event.Type, render(event))
+		case <-heartbeat.C:
+			if authorized != nil && !authorized() {
+				return
+			}
+			if _, err := c.Writer.WriteString(": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+func newBasketStreamEvent(event events.Event) interface{} {
+	basket, _ := event.Data.(services.BasketEvent)
+	return responses.BasketStreamEvent{
+		BasketID:  basket.BasketID,
+		StoreID:   basket.StoreID,
+		Quantity:  basket.Quantity,
+		Status:    basket.Status,
+		Available: basket.Available,
+	}
+}
+
+func newOrderStreamEvent(order *services.OrderEvent) responses.OrderStreamEvent {
+	return responses.OrderStreamEvent{
+		OrderID:       order.OrderID,
+		StoreID:       order.StoreID,
+		Code:          order.Code,
+		Status:        order.Status,
+		BasketID:      order.BasketID,
+		BasketName:    order.BasketName,
+		Price:         order.Price,
+		CustomerEmail: order.CustomerEmail,
+		ReservedAt:    order.ReservedAt,
+		ExpiredAt:     order.ExpiredAt,
+		PickupStart:   order.PickupStart,
+		PickupEnd:     order.PickupEnd,
+		DeliveredAt:   order.DeliveredAt,
+	}
+}
@@ -1,13 +1,13 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/responses"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
 	"github.com/Sebiche09/app-anti-gaspillage.git/services"
-	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -37,7 +37,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, refreshToken, err := h.UserService.Login(req.Email, req.Password)
+	token, refreshToken, err := h.UserService.Login(req.Email, req.Password, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
 		if err.Error() == "invalid credentials" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
@@ -68,28 +68,11 @@ func (h *UserHandler) Signup(c *gin.Context) {
 		return
 	}
 
-	createdUser, err := h.UserService.Create(registerReq)
-	if err != nil {
+	if _, err := h.UserService.Create(registerReq); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	emailService := services.NewEmailService(
-		utils.GetEnv("SMTP_HOST"),
-		utils.GetEnv("SMTP_PORT"),
-		utils.GetEnv("SMTP_USER"),
-		utils.GetEnv("SMTP_PASS"),
-		utils.GetEnv("EMAIL_FROM"),
-	)
-
-	emailBody := fmt.Sprintf("Bonjour,\n\nVoici votre code de validation : %s\n\nIl est valable pendant 10 minutes.\n\nMerci !", createdUser.ValidationCode)
-
-	err = emailService.SendEmail(createdUser.Email, "Votre code de validation", emailBody)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Échec de l'envoi de l'email", "details": err.Error()})
-		return
-	}
-
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Utilisateur créé. Un code de validation a été envoyé par email.",
 	})
@@ -97,13 +80,14 @@ func (h *UserHandler) Signup(c *gin.Context) {
 
 // validate-code godoc
 // @Summary Valider le code de confirmation
-// @Description Valide le code envoyé par email et active le compte
+// @Description Valide le code envoyé par email et active le compte. Le code expire après 10 minutes et est bloqué après 5 saisies erronées ; un nouveau code doit alors être demandé. La même erreur est retournée que l'email corresponde ou non à un compte.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param validation body requests.CodeValidationRequest true "Email et code"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
 // @Router /api/auth/validate-code [post]
 func (h *UserHandler) ValidateCode(c *gin.Context) {
 	var req requests.CodeValidationRequest
@@ -112,25 +96,11 @@ func (h *UserHandler) ValidateCode(c *gin.Context) {
 		return
 	}
 
-	user, err := h.UserService.GetByEmail(req.Email)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Utilisateur introuvable"})
-		return
-	}
-
-	if user.IsEmailConfirmed {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email déjà confirmé"})
-		return
-	}
-
-	if user.ValidationCode != req.Code {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Code invalide"})
-		return
-	}
-
-	user.IsEmailConfirmed = true
-	user.ValidationCode = ""
-	if err := h.UserService.Save(user); err != nil {
+	if err := h.UserService.ConfirmEmail(req.Email, req.Code); err != nil {
+		if errors.Is(err, services.ErrInvalidValidationCode) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Code invalide ou expiré"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la confirmation"})
 		return
 	}
@@ -140,13 +110,14 @@ func (h *UserHandler) ValidateCode(c *gin.Context) {
 
 // resend-code godoc
 // @Summary Resend validation code
-// @Description Resend the validation code to the user's email
+// @Description Send a new validation code, replacing the previous one, to an unconfirmed account. At most one code is sent per minute. The response is the same whether or not the email matches an account.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param email body requests.ResendCodeRequest true "Email address"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
 // @Router /api/auth/resend-code [post]
 func (h *UserHandler) ResendCode(c *gin.Context) {
 	var req requests.ResendCodeRequest
@@ -154,29 +125,11 @@ func (h *UserHandler) ResendCode(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Requête invalide"})
 		return
 	}
-	user, err := h.UserService.GetByEmail(req.Email)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Utilisateur introuvable"})
-		return
-	}
-	if user.IsEmailConfirmed {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email déjà confirmé"})
-		return
-	}
-	emailService := services.NewEmailService(
-		utils.GetEnv("SMTP_HOST"),
-		utils.GetEnv("SMTP_PORT"),
-		utils.GetEnv("SMTP_USER"),
-		utils.GetEnv("SMTP_PASS"),
-		utils.GetEnv("EMAIL_FROM"),
-	)
-	emailBody := fmt.Sprintf("Bonjour,\n\nVoici votre code de validation : %s\n\nIl est valable pendant 10 minutes.\n\nMerci !", user.ValidationCode)
-	err = emailService.SendEmail(user.Email, "Votre code de validation", emailBody)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Échec de l'envoi de l'email", "details": err.Error()})
+	if err := h.UserService.ResendValidationCode(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Échec de l'envoi de l'email"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Code de validation renvoyé par email."})
+	c.JSON(http.StatusOK, gin.H{"message": "Si un compte non confirmé correspond à cet email, un nouveau code de validation lui a été envoyé."})
 }
 
 // getUsers godoc
@@ -200,34 +153,93 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 	c.JSON(http.StatusOK, users)
 }
 
-// refresh-token godoc
-// @Summary Refresh user token
-// @Description Refresh the user's authentication token
+// forgot-password godoc
+// @Summary Mot de passe oublié
+// @Description Envoie un lien de réinitialisation du mot de passe, valable une heure et à usage unique. La réponse est la même que l'email corresponde ou non à un compte.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param input body requests.ForgotPasswordRequest true "Email du compte"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/forgot-password [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req requests.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.UserService.RequestPasswordReset(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la demande de réinitialisation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Si un compte correspond à cet email, un lien de réinitialisation lui a été envoyé."})
+}
+
+// reset-password godoc
+// @Summary Réinitialiser le mot de passe
+// @Description Remplace le mot de passe avec le jeton reçu par email. Le jeton ne sert qu'une fois et les sessions ouvertes doivent se reconnecter.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param input body requests.ResetPasswordRequest true "Jeton et nouveau mot de passe"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/reset-password [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req requests.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.UserService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, repositories.ErrPasswordResetTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Lien de réinitialisation invalide ou expiré"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors de la réinitialisation du mot de passe"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mot de passe réinitialisé. Vous pouvez vous connecter."})
+}
+
+// change-password godoc
+// @Summary Changer de mot de passe
+// @Description Remplace le mot de passe de l'utilisateur connecté après vérification du mot de passe actuel ; ses autres sessions sont déconnectées
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security Bearer
-// @Param refresh_token body requests.RefreshTokenRequest true "Refresh token"
-// @Success 200 {object} responses.LoginResponse
-// @Failure 401 {object} map[string]string
+// @Param Authorization header string true "Bearer token"
+// @Param input body requests.ChangePasswordRequest true "Mot de passe actuel et nouveau mot de passe"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /api/auth/refresh-token [post]
-func (h *UserHandler) RefreshToken(c *gin.Context) {
-	var req requests.RefreshTokenRequest
+// @Router /api/me/password [post]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var req requests.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	newToken, newRefreshToken, err := h.UserService.RefreshToken(req.RefreshToken)
-	if err != nil {
-		if err.Error() == "invalid refresh token" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	userID := c.MustGet("userId").(uint)
+	sessionID := c.MustGet("sessionId").(uint)
+	if err := h.UserService.ChangePassword(userID, sessionID, req.CurrentPassword, req.NewPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidPassword) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Mot de passe actuel incorrect"})
+			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du changement de mot de passe"})
 		return
 	}
 
-	c.JSON(http.StatusOK, responses.LoginResponse{Token: newToken, RefreshToken: newRefreshToken})
+	c.JSON(http.StatusOK, gin.H{"message": "Mot de passe modifié. Reconnectez-vous sur vos autres appareils."})
 }
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// paymentSignatureHeader contient la signature HMAC des webhooks de paiement
+const paymentSignatureHeader = "X-Payment-Signature"
+
+// maxWebhookBodySize borne la taille des webhooks acceptés
+const maxWebhookBodySize = 64 << 10
+
+type WebhookHandler struct {
+	paymentService *services.PaymentWebhookService
+}
+
+func NewWebhookHandler(paymentService *services.PaymentWebhookService) *WebhookHandler {
+	return &WebhookHandler{paymentService: paymentService}
+}
+
+// PaymentWebhook godoc
+// @Summary Recevoir un événement de paiement
+// @Description Reçoit les événements du prestataire de paiement (payment_succeeded, payment_failed, payment_refunded), signés par HMAC dans l'en-tête X-Payment-Signature. Chaque événement n'est appliqué qu'une seule fois.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "Signature t=<timestamp>,v1=<hmac>"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.ErrorResponse "Événement invalide"
+// @Failure 401 {object} models.ErrorResponse "Signature invalide"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/webhooks/payments [post]
+func (h *WebhookHandler) PaymentWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err = h.paymentService.HandleWebhook(payload, c.GetHeader(paymentSignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrInvalidWebhookSignature):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidPaymentEvent):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur lors du traitement de l'événement"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event processed"})
+}
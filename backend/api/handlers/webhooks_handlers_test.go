@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/events"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/services"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// flakyPayments est le prestataire fictif dont les failures premiers remboursements échouent
+type flakyPayments struct {
+	*services.FakePaymentProvider
+	failures int
+}
+
+func (p *flakyPayments) RefundPaymentIntent(intentID string) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("provider unavailable")
+	}
+	return p.FakePaymentProvider.RefundPaymentIntent(intentID)
+}
+
+// newWebhookRouter expose le webhook de paiement sur un service des commandes construit avec le prestataire
+// et le service de remboursement donnés
+func newWebhookRouter(db *gorm.DB, payments services.PaymentProvider, refunds *services.RefundService, clock utils.Clock) *gin.Engine {
+	orderRepo := repositories.NewOrderRepository(db)
+	orderService := services.NewOrderService(
+		orderRepo,
+		repositories.NewStripeCustomerRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewPaymentEventRepository(db),
+		payments,
+		refunds,
+		newNotificationService(db, newMailService(db)),
+		services.NewBasketEvents(events.NewBroker(8, 0), repositories.NewBasketRepository(db)),
+		services.NewOrderEvents(events.NewBroker(8, 0), orderRepo),
+	)
+	handler := NewWebhookHandler(services.NewPaymentWebhookService(testWebhookSecret, orderService, clock))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/webhooks/payments", handler.PaymentWebhook)
+	return router
+}
+
+// createPaidOrder crée une commande au statut donné dont le paiement a réussi chez le prestataire
+func createPaidOrder(t *testing.T, db *gorm.DB, payments services.PaymentProvider, basket *models.Basket, user *models.User, status string) (*models.Order, string) {
+	t.Helper()
+	intent, err := payments.CreatePaymentIntent(1000, "eur", "cus_test", "", nil)
+	if err == nil {
+		_, err = payments.ConfirmPaymentIntent(intent.ID, "pm_card_visa")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := testutil.CreateOrder(t, db, basket, user, status, time.Now().Add(time.Hour))
+	if err := db.Model(order).Update("stripe_payment_intent_id", intent.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return order, intent.ID
+}
+
+// sendWebhook poste le webhook avec l'en-tête de signature donné
+func sendWebhook(router *gin.Engine, payload, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/payments", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		req.Header.Set(paymentSignatureHeader, signature)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// sendSignedWebhook poste le webhook signé avec le secret de test, à l'heure de l'horloge
+func sendSignedWebhook(router *gin.Engine, clock utils.Clock, payload string) *httptest.ResponseRecorder {
+	return sendWebhook(router, payload, utils.SignWebhookPayload(testWebhookSecret, clock.Now(), []byte(payload)))
+}
+
+func eventPayload(eventID, eventType, intentID string) string {
+	return fmt.Sprintf(`{"id":%q,"type":%q,"data":{"payment_intent_id":%q}}`, eventID, eventType, intentID)
+}
+
+func TestPaymentWebhookAppliesEventOnce(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := &utils.FixedClock{Time: time.Now()}
+	payments := &flakyPayments{FakePaymentProvider: services.NewFakePaymentProvider()}
+	router := newWebhookRouter(db, payments, services.NewRefundService(repositories.NewPaymentRefundRepository(db), payments, clock), clock)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+	basket := testutil.CreateBasket(t, db, store, "Panier", models.BasketStatusAvailable, nil)
+	customer := testutil.CreateUser(t, db, "customer@example.com")
+	order, intentID := createPaidOrder(t, db, payments, basket, customer, models.OrderPending)
+	payload := eventPayload("evt_1", services.PaymentEventSucceeded, intentID)
+
+	// Le prestataire renvoie l'événement : il n'est appliqué qu'une fois
+	for i := 0; i < 2; i++ {
+		if w := sendSignedWebhook(router, clock, payload); w.Code != http.StatusOK {
+			t.Fatalf("delivery %d: status %d, body %s", i+1, w.Code, w.Body)
+		}
+	}
+
+	if got := testutil.OrderStatus(t, db, order.ID); got != models.OrderConfirmed {
+		t.Errorf("order status = %q, want %q", got, models.OrderConfirmed)
+	}
+	var processed, notifications int64
+	db.Model(&models.ProcessedPaymentEvent{}).Count(&processed)
+	db.Model(&models.Notification{}).Where("user_id = ? AND type = ?", customer.ID, models.NotificationTypeOrderReady).Count(&notifications)
+	if processed != 1 || notifications != 1 {
+		t.Errorf("processed events = %d, ready notifications = %d; want 1 and 1", processed, notifications)
+	}
+}
+
+func TestPaymentWebhookCancelsOnFailure(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := &utils.FixedClock{Time: time.Now()}
+	payments := &flakyPayments{FakePaymentProvider: services.NewFakePaymentProvider()}
+	router := newWebhookRouter(db, payments, services.NewRefundService(repositories.NewPaymentRefundRepository(db), payments, clock), clock)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+	basket := testutil.CreateBasket(t, db, store, "Panier", models.BasketStatusAvailable, nil)
+	customer := testutil.CreateUser(t, db, "customer@example.com")
+	order, intentID := createPaidOrder(t, db, payments, basket, customer, models.OrderPending)
+
+	if w := sendSignedWebhook(router, clock, eventPayload("evt_1", services.PaymentEventFailed, intentID)); w.Code != http.StatusOK {
+		t.Fatalf("status %d, body %s", w.Code, w.Body)
+	}
+
+	if got := testutil.OrderStatus(t, db, order.ID); got != models.OrderCancelled {
+		t.Errorf("order status = %q, want %q", got, models.OrderCancelled)
+	}
+	if got := testutil.LoadBasket(t, db, basket.ID).Quantity; got != basket.Quantity+1 {
+		t.Errorf("basket quantity = %d, want %d", got, basket.Quantity+1)
+	}
+}
+
+func TestPaymentWebhookRejectsInvalidRequests(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := &utils.FixedClock{Time: time.Now()}
+	payments := &flakyPayments{FakePaymentProvider: services.NewFakePaymentProvider()}
+	router := newWebhookRouter(db, payments, services.NewRefundService(repositories.NewPaymentRefundRepository(db), payments, clock), clock)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+	basket := testutil.CreateBasket(t, db, store, "Panier", models.BasketStatusAvailable, nil)
+	customer := testutil.CreateUser(t, db, "customer@example.com")
+	order, intentID := createPaidOrder(t, db, payments, basket, customer, models.OrderPending)
+	payload := eventPayload("evt_1", services.PaymentEventSucceeded, intentID)
+	now := clock.Now()
+
+	tests := []struct {
+		name      string
+		payload   string
+		signature string
+		want      int
+	}{
+		{"sans signature", payload, "", http.StatusUnauthorized},
+		{"autre secret", payload, utils.SignWebhookPayload("whsec_other", now, []byte(payload)), http.StatusUnauthorized},
+		{"signature trop ancienne", payload, utils.SignWebhookPayload(testWebhookSecret, now.Add(-10*time.Minute), []byte(payload)), http.StatusUnauthorized},
+		{"contenu modifié", strings.Replace(payload, "evt_1", "evt_2", 1), utils.SignWebhookPayload(testWebhookSecret, now, []byte(payload)), http.StatusUnauthorized},
+		{"JSON invalide", "{", utils.SignWebhookPayload(testWebhookSecret, now, []byte("{")), http.StatusBadRequest},
+		{"sans ID d'événement", `{"type":"payment_succeeded"}`, utils.SignWebhookPayload(testWebhookSecret, now, []byte(`{"type":"payment_succeeded"}`)), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := sendWebhook(router, tt.payload, tt.signature); w.Code != tt.want {
+				t.Errorf("status %d, want %d (body %s)", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+
+	if got := testutil.OrderStatus(t, db, order.ID); got != models.OrderPending {
+		t.Errorf("order status = %q, want %q", got, models.OrderPending)
+	}
+	var processed int64
+	db.Model(&models.ProcessedPaymentEvent{}).Count(&processed)
+	if processed != 0 {
+		t.Errorf("processed events = %d, want 0", processed)
+	}
+}
+
+func TestPaymentWebhookRefundsCancelledOrder(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := &utils.FixedClock{Time: time.Now()}
+	payments := &flakyPayments{FakePaymentProvider: services.NewFakePaymentProvider()}
+	refunds := services.NewRefundService(repositories.NewPaymentRefundRepository(db), payments, clock)
+	router := newWebhookRouter(db, payments, refunds, clock)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+	basket := testutil.CreateBasket(t, db, store, "Panier", models.BasketStatusAvailable, nil)
+	customer := testutil.CreateUser(t, db, "customer@example.com")
+	order, intentID := createPaidOrder(t, db, payments, basket, customer, models.OrderCancelled)
+	payments.failures = 1
+
+	if w := sendSignedWebhook(router, clock, eventPayload("evt_1", services.PaymentEventSucceeded, intentID)); w.Code != http.StatusOK {
+		t.Fatalf("status %d, body %s", w.Code, w.Body)
+	}
+	if got := testutil.OrderStatus(t, db, order.ID); got != models.OrderCancelled {
+		t.Errorf("order status = %q, want %q", got, models.OrderCancelled)
+	}
+
+	refund := func() models.PaymentRefund {
+		t.Helper()
+		var refund models.PaymentRefund
+		if err := db.Where("payment_intent_id = ?", intentID).First(&refund).Error; err != nil {
+			t.Fatalf("refund not recorded with the event: %v", err)
+		}
+		return refund
+	}
+	if r := refund(); r.Status != models.RefundPending || r.OrderID != order.ID {
+		t.Fatalf("refund = %+v, want pending for order %d", r, order.ID)
+	}
+
+	// La première tentative échoue chez le prestataire : le remboursement est retenté plus tard
+	if err := refunds.Process(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r := refund(); r.Status != models.RefundPending || r.Attempts != 1 || r.LastError == "" {
+		t.Errorf("after failure: refund = %+v, want pending with 1 attempt and an error", r)
+	}
+	if err := refunds.Process(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r := refund(); r.Attempts != 1 {
+		t.Errorf("refund retried before its delay: %d attempts", r.Attempts)
+	}
+
+	clock.Time = clock.Time.Add(time.Minute)
+	if err := refunds.Process(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r := refund(); r.Status != models.RefundRefunded || r.Attempts != 2 || r.RefundedAt == nil {
+		t.Errorf("after retry: refund = %+v, want refunded after 2 attempts", r)
+	}
+	if !payments.Refunded(intentID) {
+		t.Error("payment not refunded at the provider")
+	}
+
+	// Un renvoi de l'événement ne programme pas de second remboursement
+	if w := sendSignedWebhook(router, clock, eventPayload("evt_2", services.PaymentEventSucceeded, intentID)); w.Code != http.StatusOK {
+		t.Fatalf("status %d, body %s", w.Code, w.Body)
+	}
+	var count int64
+	db.Model(&models.PaymentRefund{}).Count(&count)
+	if count != 1 {
+		t.Errorf("refunds = %d, want 1", count)
+	}
+}
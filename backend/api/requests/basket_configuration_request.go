@@ -4,11 +4,21 @@ type CreateBasketConfigurationRequest struct {
 	Name               string  `json:"name" binding:"required"`
 	Description        string  `json:"description"`
 	DiscountPercentage float64 `json:"discount_percentage" binding:"required"`
+	OriginalPrice      float64 `json:"original_price" binding:"required,gt=0" example:"22"` // Prix original des paniers publiés
 	Quantity           int     `json:"quantity" binding:"required"`
+	PickupStartTime    string  `json:"pickup_start_time" example:"18:00"` // Heure de début de retrait (HH:MM, fuseau du magasin)
+	PickupEndTime      string  `json:"pickup_end_time" example:"20:00"`   // Heure de fin de retrait (HH:MM, fuseau du magasin)
+	Weekdays           []int   `json:"weekdays" example:"1,2,3,4,5,6"`    // Jours de publication automatique (1 = lundi ... 7 = dimanche)
+	Active             *bool   `json:"active" example:"true"`             // Publication automatique activée (true par défaut)
 }
 type UpdateBasketConfigurationRequest struct {
 	Name               string  `json:"name" binding:"required"`
 	Description        string  `json:"description"`
 	DiscountPercentage float64 `json:"discount_percentage" binding:"required"`
+	OriginalPrice      float64 `json:"original_price" binding:"required,gt=0" example:"22"` // Prix original des paniers publiés
 	Quantity           int     `json:"quantity" binding:"required"`
+	PickupStartTime    string  `json:"pickup_start_time" example:"18:00"` // Heure de début de retrait (HH:MM, fuseau du magasin)
+	PickupEndTime      string  `json:"pickup_end_time" example:"20:00"`   // Heure de fin de retrait (HH:MM, fuseau du magasin)
+	Weekdays           []int   `json:"weekdays" example:"1,2,3,4,5,6"`    // Jours de publication automatique (1 = lundi ... 7 = dimanche)
+	Active             *bool   `json:"active" example:"true"`             // Publication automatique activée (inchangée si absente)
 }
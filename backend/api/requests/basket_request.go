@@ -3,14 +3,16 @@ package requests
 import "time"
 
 type CreateBasketRequest struct {
-	StoreID            int     `json:"store_id" example:"1" binding:"required"`
-	ConfigurationID    *int    `json:"configuration_id" example:"1"`
-	Name               string  `json:"name" example:"panier surprise" binding:"required"`
-	Description        string  `json:"description" example:"Ceci est un panier suprise" gorm:"type:text"`
-	DiscountPercentage float64 `json:"discount_percentage" binding:"required" example:"0.2" gorm:"not null;default:0"` // 20% de réduction
-	OriginalPrice      float64 `json:"original_price" binding:"required" example:"22" gorm:"not null"`                 // Prix original avant réduction
-	Quantity           int     `json:"quantity" binding:"required" example:"2" gorm:"default:0"`                       // Quantité disponible
-	ExpirationDate     *string `json:"expiration_date" example:"2022-12-31" gorm:"type:date"`                          // Date d'expiration du panier, au format YYYY-MM-DD
+	StoreID            int        `json:"store_id" example:"1" binding:"required"`
+	ConfigurationID    *int       `json:"configuration_id" example:"1"`
+	Name               string     `json:"name" example:"panier surprise" binding:"required"`
+	Description        string     `json:"description" example:"Ceci est un panier suprise" gorm:"type:text"`
+	DiscountPercentage float64    `json:"discount_percentage" binding:"required" example:"0.2" gorm:"not null;default:0"` // 20% de réduction
+	OriginalPrice      float64    `json:"original_price" binding:"required" example:"22" gorm:"not null"`                 // Prix original avant réduction
+	Quantity           int        `json:"quantity" binding:"required" example:"2" gorm:"default:0"`                       // Quantité disponible
+	ExpirationDate     *string    `json:"expiration_date" example:"2022-12-31" gorm:"type:date"`                          // Date d'expiration du panier, au format YYYY-MM-DD
+	PickupStart        *time.Time `json:"pickup_start" example:"2022-12-31T18:00:00+01:00"`                               // Début de la plage de retrait (RFC 3339, avec fuseau)
+	PickupEnd          *time.Time `json:"pickup_end" example:"2022-12-31T20:00:00+01:00"`                                 // Fin de la plage de retrait (RFC 3339, avec fuseau)
 }
 
 type UpdateBasketRequest struct {
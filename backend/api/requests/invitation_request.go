@@ -0,0 +1,6 @@
+package requests
+
+type CreateInvitationRequest struct {
+	StoreID uint   `json:"store_id" binding:"required"`
+	Email   string `json:"email" binding:"required,email"`
+}
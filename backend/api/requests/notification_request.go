@@ -0,0 +1,9 @@
+package requests
+
+// UpdateNotificationPreferencesRequest modifie les préférences de notification ; les champs absents sont conservés
+type UpdateNotificationPreferencesRequest struct {
+	NewBaskets *bool `json:"new_baskets" example:"true"` // Alertes de nouveaux paniers des magasins favoris
+	Email      *bool `json:"email" example:"false"`      // Diffusion par email
+	Push       *bool `json:"push" example:"true"`        // Diffusion par notification push
+	InApp      *bool `json:"in_app" example:"true"`      // Diffusion dans la boîte de réception in-app
+}
@@ -0,0 +1,15 @@
+package requests
+
+type CreateOrderRequest struct {
+	BasketID uint `json:"basket_id" example:"1" binding:"required"`
+}
+
+type PayOrderRequest struct {
+	PaymentMethodID string `json:"payment_method_id" example:"pm_card_visa" binding:"required"`
+}
+
+// VerifyOrderRequest contient soit le code de retrait saisi, soit le contenu du QR code scanné
+type VerifyOrderRequest struct {
+	Code      string `json:"code" example:"K7M2Q9XA"`
+	QRPayload string `json:"qr_payload" example:"sovemanje://orders/K7M2Q9XA"`
+}
@@ -0,0 +1,29 @@
+package requests
+
+// UpdateProfileRequest modifie le profil de l'utilisateur connecté ; les champs absents sont conservés
+type UpdateProfileRequest struct {
+	DisplayName             *string                               `json:"display_name" example:"Marie" binding:"omitempty,max=80"`
+	Phone                   *string                               `json:"phone" example:"+32470123456"` // Format international ; vide pour l'effacer
+	PreferredLanguage       *string                               `json:"preferred_language" example:"fr" binding:"omitempty,oneof=fr nl en"`
+	DefaultLocation         *DefaultLocationRequest               `json:"default_location"`         // Lieu de recherche par défaut, effacé par DELETE /api/me/default-location
+	NotificationPreferences *UpdateNotificationPreferencesRequest `json:"notification_preferences"` // Préférences de notification
+}
+
+// DefaultLocationRequest est le lieu autour duquel l'application cherche les paniers par défaut
+type DefaultLocationRequest struct {
+	Label     string   `json:"label" example:"Grand-Place, Bruxelles" binding:"max=255"`
+	Latitude  *float64 `json:"latitude" example:"50.8467" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" example:"4.3525" binding:"required,min=-180,max=180"`
+	RadiusKm  *float64 `json:"radius_km" example:"5" binding:"omitempty,gt=0,max=100"` // Absent pour le rayon par défaut de la recherche
+}
+
+// ChangeEmailRequest demande le remplacement de l'email du compte ; un code est envoyé à la nouvelle adresse
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" example:"new@example.com" binding:"required,email"`
+	Password string `json:"password" example:"password123" binding:"required"`
+}
+
+// ConfirmEmailChangeRequest confirme le changement d'email avec le code reçu à la nouvelle adresse
+type ConfirmEmailChangeRequest struct {
+	Code string `json:"code" example:"123456" binding:"required"`
+}
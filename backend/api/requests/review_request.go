@@ -0,0 +1,14 @@
+package requests
+
+type CreateReviewRequest struct {
+	Rating  int    `json:"rating" example:"5" binding:"required,min=1,max=5"`         // Note de 1 à 5
+	Comment string `json:"comment" example:"Panier très généreux" binding:"max=2000"` // Commentaire (optionnel)
+}
+
+type ReplyReviewRequest struct {
+	Reply string `json:"reply" example:"Merci pour votre visite !" binding:"required,max=2000"`
+}
+
+type ModerateReviewRequest struct {
+	Hidden *bool `json:"hidden" example:"true" binding:"required"` // true pour masquer l'avis, false pour le réafficher
+}
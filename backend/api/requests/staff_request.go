@@ -0,0 +1,6 @@
+package requests
+
+// UpdateStaffRoleRequest change le rôle d'un membre du staff
+type UpdateStaffRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=clerk manager" example:"manager"` // clerk ou manager
+}
@@ -7,6 +7,7 @@ type CreateStoreRequest struct {
 	PostalCode  string `json:"postal_code" example:"97300" gorm:"type:varchar(10);not null"`    // Code postal (limité à 10 caractères pour compatibilité internationale)
 	PhoneNumber string `json:"phone_number" example:"+32470542125" gorm:"type:varchar(15)"`     // Numéro de téléphone (optionnel, max 15 caractères)
 	CategoryID  uint   `json:"category_id" example:"1" gorm:"type:int;not null"`                // ID de la catégorie (obligatoire)
+	TimeZone    string `json:"time_zone" example:"Europe/Brussels"`                             // Fuseau horaire IANA (optionnel, Europe/Brussels par défaut)
 }
 
 type UpdateStoreRequest struct {
@@ -16,6 +17,23 @@ type UpdateStoreRequest struct {
 	PostalCode  string `json:"postal_code" example:"97301" gorm:"type:varchar(10);not null"`    // Code postal (limité à 10 caractères pour compatibilité internationale)
 	PhoneNumber string `json:"phone_number" example:"+32470542125" gorm:"type:varchar(15)"`     // Numéro de téléphone (optionnel, max 15 caractères)
 	CategoryID  uint   `json:"category_id" example:"1" gorm:"type:int;not null"`                // ID de la catégorie (obligatoire)
+	TimeZone    string `json:"time_zone" example:"Europe/Brussels"`                             // Fuseau horaire IANA (optionnel, inchangé s'il est absent)
+}
+
+type OpeningHourRequest struct {
+	Weekday  int    `json:"weekday" example:"1" binding:"required,min=1,max=7"` // Jour de la semaine (1 = lundi ... 7 = dimanche)
+	OpensAt  string `json:"opens_at" example:"08:00" binding:"required"`        // Heure d'ouverture (HH:MM, fuseau du magasin)
+	ClosesAt string `json:"closes_at" example:"19:30" binding:"required"`       // Heure de fermeture (HH:MM, fuseau du magasin)
+}
+
+type UpdateOpeningHoursRequest struct {
+	OpeningHours []OpeningHourRequest `json:"opening_hours" binding:"dive"` // Planning hebdomadaire complet ; remplace le planning existant
+}
+
+type StoreClosureRequest struct {
+	StartDate string `json:"start_date" example:"2025-12-24" binding:"required"` // Premier jour de fermeture (YYYY-MM-DD)
+	EndDate   string `json:"end_date" example:"2025-12-26" binding:"required"`   // Dernier jour de fermeture, inclus (YYYY-MM-DD)
+	Reason    string `json:"reason" example:"Fêtes de fin d'année"`              // Motif affiché aux clients (optionnel)
 }
 
 type InviteStaffRequest struct {
@@ -13,9 +13,26 @@ type ResendCodeRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
 type CodeValidationRequest struct {
-	Email string `json:"email"`
-	Code  string `json:"code"`
+	Email string `json:"email" example:"user@example.com" binding:"required,email"`
+	Code  string `json:"code" example:"123456" binding:"required"`
 }
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
+
+// ForgotPasswordRequest demande l'envoi d'un lien de réinitialisation du mot de passe
+type ForgotPasswordRequest struct {
+	Email string `json:"email" example:"user@example.com" binding:"required,email"`
+}
+
+// ResetPasswordRequest choisit un nouveau mot de passe avec le jeton reçu par email
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" example:"newpassword123" binding:"required,min=8"`
+}
+
+// ChangePasswordRequest change le mot de passe de l'utilisateur connecté
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" example:"password123" binding:"required"`
+	NewPassword     string `json:"new_password" example:"newpassword123" binding:"required,min=8"`
+}
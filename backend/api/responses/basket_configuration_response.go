@@ -0,0 +1,16 @@
+package responses
+
+type BasketConfigurationResponse struct {
+	ID                 uint    `json:"id"`
+	StoreID            uint    `json:"storeId"`
+	Name               string  `json:"name"`
+	Description        string  `json:"description"`
+	DiscountPercentage float64 `json:"discountPercentage"`
+	OriginalPrice      float64 `json:"originalPrice"`
+	Quantity           int     `json:"quantity"`
+	PickupStartTime    string  `json:"pickupStartTime"`
+	PickupEndTime      string  `json:"pickupEndTime"`
+	Weekdays           []int   `json:"weekdays"` // 1 = lundi ... 7 = dimanche
+	Active             bool    `json:"active"`
+	LastPublishedOn    *string `json:"lastPublishedOn"`
+}
@@ -1,24 +1,40 @@
 package responses
 
+import "time"
+
 type BasketResponse struct {
-	ID                 uint    `json:"id"`
-	Name               string  `json:"name"`
-	Address            string  `json:"address"`
-	Description        string  `json:"description"`
-	Rating             float64 `json:"rating"`
-	OriginalPrice      float64 `json:"originalPrice"`
-	DiscountPercentage float64 `json:"discountPercentage"`
-	Category           string  `json:"category"`
-	Quantity           int     `json:"quantity"`
-	Latitude           float64 `json:"latitude"`
-	Longitude          float64 `json:"longitude"`
+	ID                 uint       `json:"id"`
+	Name               string     `json:"name"`
+	Address            string     `json:"address"`
+	Description        string     `json:"description"`
+	Rating             float64    `json:"rating"`
+	OriginalPrice      float64    `json:"originalPrice"`
+	DiscountPercentage float64    `json:"discountPercentage"`
+	Category           string     `json:"category"`
+	Quantity           int        `json:"quantity"`
+	Latitude           float64    `json:"latitude"`
+	Longitude          float64    `json:"longitude"`
+	Distance           *float64   `json:"distance,omitempty"` // Distance en km depuis le point de recherche
+	PickupStart        *time.Time `json:"pickupStart"`        // Début de la plage de retrait, dans le fuseau du magasin
+	PickupEnd          *time.Time `json:"pickupEnd"`          // Fin de la plage de retrait, dans le fuseau du magasin
+	TimeZone           string     `json:"timeZone"`           // Fuseau horaire IANA du magasin
+	IsFavorite         bool       `json:"isFavorite"`         // Magasin dans les favoris de l'utilisateur
+}
+type BasketListResponse struct {
+	Data       []BasketResponse `json:"data"`
+	NextCursor string           `json:"nextCursor,omitempty"` // À renvoyer dans le paramètre cursor pour obtenir la page suivante
+	Total      int64            `json:"total"`
 }
+
 type BasketByStoreResponse struct {
-	ID                 uint    `json:"id"`
-	Name               string  `json:"name"`
-	OriginalPrice      float64 `json:"originalPrice"`
-	DiscountPercentage float64 `json:"discountPercentage"`
-	Category           string  `json:"category"`
-	Description        string  `json:"description"`
-	Quantity           int     `json:"quantity"`
+	ID                 uint       `json:"id"`
+	Name               string     `json:"name"`
+	OriginalPrice      float64    `json:"originalPrice"`
+	DiscountPercentage float64    `json:"discountPercentage"`
+	Category           string     `json:"category"`
+	Description        string     `json:"description"`
+	Quantity           int        `json:"quantity"`
+	PickupStart        *time.Time `json:"pickupStart"`
+	PickupEnd          *time.Time `json:"pickupEnd"`
+	TimeZone           string     `json:"timeZone"`
 }
@@ -0,0 +1,14 @@
+package responses
+
+import "time"
+
+// InvitationResponse est une invitation à rejoindre l'équipe d'un magasin
+type InvitationResponse struct {
+	ID        uint      `json:"id"`
+	StoreID   uint      `json:"storeId"`
+	StoreName string    `json:"storeName"`
+	Email     string    `json:"email"`
+	Status    string    `json:"status"` // PENDING, ACCEPTED, DECLINED, CANCELLED ou EXPIRED
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,34 @@
+package responses
+
+import "time"
+
+type NotificationPreferencesResponse struct {
+	NewBaskets bool `json:"newBaskets"`
+	Email      bool `json:"email"`
+	Push       bool `json:"push"`
+	InApp      bool `json:"inApp"`
+}
+
+type NotificationResponse struct {
+	ID        uint       `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	StoreID   *uint      `json:"storeId,omitempty"`
+	BasketID  *uint      `json:"basketId,omitempty"`
+	OrderID   *uint      `json:"orderId,omitempty"`
+	ReadAt    *time.Time `json:"readAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type NotificationListResponse struct {
+	Data        []NotificationResponse `json:"data"`
+	Total       int64                  `json:"total"`
+	UnreadCount int64                  `json:"unreadCount"` // Nombre total de notifications non lues
+	Page        int                    `json:"page"`
+	PageSize    int                    `json:"pageSize"`
+}
+
+type MarkAllReadResponse struct {
+	Updated int64 `json:"updated"` // Nombre de notifications marquées comme lues
+}
@@ -0,0 +1,20 @@
+package responses
+
+import "time"
+
+type OrderResponse struct {
+	ID          uint       `json:"id"`
+	Code        string     `json:"code"`
+	QRPayload   string     `json:"qrPayload"`
+	Status      string     `json:"status"`
+	Price       float64    `json:"price"`
+	BasketID    uint       `json:"basketId"`
+	BasketName  string     `json:"basketName"`
+	StoreID     uint       `json:"storeId"`
+	StoreName   string     `json:"storeName"`
+	Address     string     `json:"address"`
+	ReservedAt  *time.Time `json:"reservedAt"`
+	ExpiredAt   *time.Time `json:"expiredAt"`
+	PickupStart *time.Time `json:"pickupStart"`
+	PickupEnd   *time.Time `json:"pickupEnd"`
+}
@@ -0,0 +1,32 @@
+package responses
+
+import "time"
+
+// ProfileResponse est le profil de l'utilisateur connecté, avec ses rôles et ses préférences
+type ProfileResponse struct {
+	ID                      uint                            `json:"id"`
+	Email                   string                          `json:"email"`
+	EmailConfirmed          bool                            `json:"emailConfirmed"`
+	PendingEmail            *PendingEmailResponse           `json:"pendingEmail"` // Changement d'email en attente de confirmation
+	DisplayName             string                          `json:"displayName"`
+	Phone                   string                          `json:"phone"`
+	PreferredLanguage       string                          `json:"preferredLanguage"`
+	DefaultLocation         *DefaultLocationResponse        `json:"defaultLocation"`
+	IsAdmin                 bool                            `json:"isAdmin"`
+	IsMerchant              bool                            `json:"isMerchant"`
+	StaffStoreIDs           []uint                          `json:"staffStoreIds"` // Magasins dont l'utilisateur fait partie de l'équipe
+	NotificationPreferences NotificationPreferencesResponse `json:"notificationPreferences"`
+	CreatedAt               time.Time                       `json:"createdAt"`
+}
+
+type PendingEmailResponse struct {
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type DefaultLocationResponse struct {
+	Label     string   `json:"label"`
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	RadiusKm  *float64 `json:"radiusKm"`
+}
@@ -0,0 +1,22 @@
+package responses
+
+import "time"
+
+type ReviewResponse struct {
+	ID        uint       `json:"id"`
+	OrderID   uint       `json:"orderId"`
+	StoreID   uint       `json:"storeId"`
+	Rating    int        `json:"rating"`
+	Comment   string     `json:"comment"`
+	Reply     string     `json:"reply,omitempty"`
+	RepliedAt *time.Time `json:"repliedAt,omitempty"`
+	Hidden    bool       `json:"hidden"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type ReviewListResponse struct {
+	Data     []ReviewResponse `json:"data"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"pageSize"`
+}
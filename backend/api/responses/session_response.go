@@ -0,0 +1,14 @@
+package responses
+
+import "time"
+
+// SessionResponse est une session active de l'utilisateur, sur un de ses appareils
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"` // Session du jeton d'accès de la requête
+}
@@ -0,0 +1,8 @@
+package responses
+
+// StaffMemberResponse est un membre de l'équipe d'un magasin
+type StaffMemberResponse struct {
+	UserID uint   `json:"userId"`
+	Email  string `json:"email"`
+	Role   string `json:"role"` // clerk ou manager
+}
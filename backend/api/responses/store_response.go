@@ -0,0 +1,36 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+)
+
+type OpeningHourResponse struct {
+	Weekday  int    `json:"weekday"` // 1 = lundi ... 7 = dimanche
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+}
+
+type StoreClosureResponse struct {
+	ID        uint   `json:"id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason"`
+}
+
+// StoreDetailResponse est le magasin enrichi de ses horaires et de son état d'ouverture
+type StoreDetailResponse struct {
+	models.Store
+	OpenNow       bool                   `json:"open_now"`
+	ClosesAt      *time.Time             `json:"closes_at"`       // Fin de la plage d'ouverture en cours, si le magasin est ouvert
+	NextOpeningAt *time.Time             `json:"next_opening_at"` // Prochaine ouverture, si le magasin est fermé
+	OpeningHours  []OpeningHourResponse  `json:"opening_hours"`
+	Closures      []StoreClosureResponse `json:"closures"` // Fermetures exceptionnelles à venir
+}
+
+// FavoriteStoreResponse est un magasin favori avec ses paniers actuellement réservables
+type FavoriteStoreResponse struct {
+	models.Store
+	Baskets []BasketByStoreResponse `json:"baskets"`
+}
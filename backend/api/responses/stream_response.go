@@ -0,0 +1,36 @@
+package responses
+
+import "time"
+
+// BasketStreamEvent est le contenu d'un événement du flux temps réel des paniers
+type BasketStreamEvent struct {
+	BasketID  uint   `json:"basketId"`
+	StoreID   uint   `json:"storeId"`
+	Quantity  int    `json:"quantity"`
+	Status    string `json:"status"`
+	Available bool   `json:"available"` // Réservable : en stock, au statut Disponible et plage de retrait non terminée
+}
+
+// OrderStreamEvent est le contenu d'un événement du flux temps réel des commandes d'un magasin :
+// l'état de la commande après le changement, suffisant pour afficher la liste des retraits
+type OrderStreamEvent struct {
+	OrderID       uint       `json:"orderId"`
+	StoreID       uint       `json:"storeId"`
+	Code          string     `json:"code"`
+	Status        string     `json:"status"`
+	BasketID      uint       `json:"basketId"`
+	BasketName    string     `json:"basketName"`
+	Price         float64    `json:"price"`
+	CustomerEmail string     `json:"customerEmail"`
+	ReservedAt    *time.Time `json:"reservedAt"`
+	ExpiredAt     *time.Time `json:"expiredAt"` // Fin du délai de paiement d'une commande en attente
+	PickupStart   *time.Time `json:"pickupStart"`
+	PickupEnd     *time.Time `json:"pickupEnd"`
+	DeliveredAt   *time.Time `json:"deliveredAt"`
+}
+
+// OrderSnapshotEvent est le contenu de l'événement orders.snapshot : les commandes en attente
+// de paiement ou de retrait, triées par début de retrait
+type OrderSnapshotEvent struct {
+	Orders []OrderStreamEvent `json:"orders"`
+}
@@ -0,0 +1,189 @@
+// Package authz centralise les règles d'autorisation : le rôle d'un utilisateur dans un magasin
+// est évalué depuis la base de données, puis comparé aux permissions de ce rôle.
+package authz
+
+import (
+	"errors"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStoreNotFound    = errors.New("store not found")
+	ErrPermissionDenied = errors.New("you are not authorized to manage this store")
+)
+
+// Role est le rôle d'un utilisateur vis-à-vis d'un magasin
+type Role string
+
+const (
+	RoleCustomer Role = "customer" // Aucun lien avec le magasin
+	RoleClerk    Role = "clerk"    // Membre du staff (models.StaffRoleClerk)
+	RoleManager  Role = "manager"  // Responsable du magasin (models.StaffRoleManager)
+	RoleOwner    Role = "owner"    // Commerçant propriétaire du magasin
+	RoleAdmin    Role = "admin"    // Administrateur de la plateforme, autorisé partout
+)
+
+// Permission est une action sur un magasin, de la forme "ressource:action"
+type Permission string
+
+const (
+	PermissionStoreManage  Permission = "store:manage"  // Modifier ou supprimer le magasin
+	PermissionStaffManage  Permission = "staff:manage"  // Consulter et gérer l'équipe du magasin
+	PermissionBasketsWrite Permission = "baskets:write" // Créer, modifier et supprimer les paniers
+	PermissionOrdersRead   Permission = "orders:read"   // Suivre les commandes du magasin en temps réel
+	PermissionOrdersVerify Permission = "orders:verify" // Remettre les commandes aux clients
+	// Gérer les horaires, les fermetures exceptionnelles et la publication automatique des paniers
+	PermissionScheduleManage Permission = "schedule:manage"
+	PermissionReviewsReply   Permission = "reviews:reply" // Répondre aux avis des clients
+)
+
+// rolePermissions liste les permissions de chaque rôle ; l'administrateur les a toutes
+var rolePermissions = map[Role][]Permission{
+	RoleClerk: {PermissionBasketsWrite, PermissionOrdersRead, PermissionOrdersVerify},
+	RoleManager: {
+		PermissionBasketsWrite, PermissionOrdersRead, PermissionOrdersVerify,
+		PermissionStaffManage, PermissionScheduleManage, PermissionReviewsReply,
+	},
+	RoleOwner: {
+		PermissionBasketsWrite, PermissionOrdersRead, PermissionOrdersVerify,
+		PermissionStaffManage, PermissionScheduleManage, PermissionReviewsReply, PermissionStoreManage,
+	},
+}
+
+// Allows indique si le rôle accorde la permission
+func (r Role) Allows(permission Permission) bool {
+	if r == RoleAdmin {
+		return true
+	}
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveRole détermine le rôle de l'utilisateur dans le magasin. merchant est le commerçant associé
+// à l'utilisateur et member son appartenance au staff du magasin ; chacun vaut nil s'il n'existe pas.
+func ResolveRole(user *models.User, store *models.Store, merchant *models.Merchant, member *models.StoreStaff) Role {
+	switch {
+	case user != nil && user.IsAdmin:
+		return RoleAdmin
+	case merchant != nil && merchant.ID == store.MerchantID:
+		return RoleOwner
+	case member != nil && member.StoreID == store.ID:
+		return memberRole(member)
+	default:
+		return RoleCustomer
+	}
+}
+
+// memberRole retourne le rôle correspondant à l'appartenance au staff
+func memberRole(member *models.StoreStaff) Role {
+	if member.Role == models.StaffRoleManager {
+		return RoleManager
+	}
+	return RoleClerk
+}
+
+// Policy évalue les permissions des utilisateurs sur les magasins à partir de la base de données,
+// sans se fier aux rôles du JWT qui peuvent être périmés
+type Policy struct {
+	userRepo     *repositories.UserRepository
+	storeRepo    *repositories.StoreRepository
+	merchantRepo *repositories.MerchantRepository
+	staffRepo    *repositories.StoreStaffRepository
+}
+
+func NewPolicy(
+	userRepo *repositories.UserRepository,
+	storeRepo *repositories.StoreRepository,
+	merchantRepo *repositories.MerchantRepository,
+	staffRepo *repositories.StoreStaffRepository,
+) *Policy {
+	return &Policy{userRepo: userRepo, storeRepo: storeRepo, merchantRepo: merchantRepo, staffRepo: staffRepo}
+}
+
+// StoreRole retourne le rôle de l'utilisateur dans le magasin
+func (p *Policy) StoreRole(userID, storeID uint) (Role, error) {
+	store, err := p.storeRepo.GetStoreByID(storeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return RoleCustomer, ErrStoreNotFound
+		}
+		return RoleCustomer, err
+	}
+
+	user, err := p.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return RoleCustomer, nil
+		}
+		return RoleCustomer, err
+	}
+	if user.IsAdmin {
+		return RoleAdmin, nil
+	}
+
+	merchant, err := p.merchantRepo.FindMerchantByUserID(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return RoleCustomer, err
+	}
+	member, err := p.staffRepo.FindMember(storeID, userID)
+	if err != nil {
+		return RoleCustomer, err
+	}
+	return ResolveRole(user, store, merchant, member), nil
+}
+
+// Authorize vérifie que l'utilisateur a la permission sur le magasin et retourne son rôle ;
+// ErrPermissionDenied sinon
+func (p *Policy) Authorize(userID, storeID uint, permission Permission) (Role, error) {
+	role, err := p.StoreRole(userID, storeID)
+	if err != nil {
+		return role, err
+	}
+	if !role.Allows(permission) {
+		return role, ErrPermissionDenied
+	}
+	return role, nil
+}
+
+// StoreIDs retourne les magasins sur lesquels l'utilisateur a la permission en tant que commerçant
+// propriétaire ou membre du staff. Les droits d'administrateur ne s'étendent pas ici à tous les magasins.
+func (p *Policy) StoreIDs(userID uint, permission Permission) ([]uint, error) {
+	var storeIDs []uint
+	seen := map[uint]bool{}
+
+	if RoleOwner.Allows(permission) {
+		merchant, err := p.merchantRepo.FindMerchantByUserID(userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if merchant != nil {
+			stores, err := p.storeRepo.GetStoresMerchant(merchant.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, store := range stores {
+				seen[store.ID] = true
+				storeIDs = append(storeIDs, store.ID)
+			}
+		}
+	}
+
+	members, err := p.staffRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range members {
+		if !seen[members[i].StoreID] && memberRole(&members[i]).Allows(permission) {
+			seen[members[i].StoreID] = true
+			storeIDs = append(storeIDs, members[i].StoreID)
+		}
+	}
+	return storeIDs, nil
+}
@@ -0,0 +1,109 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+	"gorm.io/gorm"
+)
+
+func TestResolveRole(t *testing.T) {
+	storeA := &models.Store{Model: gorm.Model{ID: 1}, MerchantID: 10}
+	merchantA := &models.Merchant{Model: gorm.Model{ID: 10}}
+	merchantB := &models.Merchant{Model: gorm.Model{ID: 20}}
+	user := &models.User{}
+	admin := &models.User{IsAdmin: true}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		merchant *models.Merchant
+		member   *models.StoreStaff
+		want     Role
+	}{
+		{"client", user, nil, nil, RoleCustomer},
+		{"administrateur", admin, nil, nil, RoleAdmin},
+		{"propriétaire", user, merchantA, nil, RoleOwner},
+		{"commerçant d'un autre magasin", user, merchantB, nil, RoleCustomer},
+		{"responsable", user, nil, &models.StoreStaff{StoreID: 1, Role: models.StaffRoleManager}, RoleManager},
+		{"employé", user, nil, &models.StoreStaff{StoreID: 1, Role: models.StaffRoleClerk}, RoleClerk},
+		{"responsable d'un autre magasin", user, nil, &models.StoreStaff{StoreID: 2, Role: models.StaffRoleManager}, RoleCustomer},
+		{"commerçant d'un autre magasin employé ici", user, merchantB, &models.StoreStaff{StoreID: 1, Role: models.StaffRoleClerk}, RoleClerk},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveRole(tt.user, storeA, tt.merchant, tt.member); got != tt.want {
+				t.Errorf("ResolveRole() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		permission Permission
+		allowed    []Role // Les autres rôles sont refusés
+	}{
+		{PermissionStoreManage, []Role{RoleOwner, RoleAdmin}},
+		{PermissionStaffManage, []Role{RoleManager, RoleOwner, RoleAdmin}},
+		{PermissionScheduleManage, []Role{RoleManager, RoleOwner, RoleAdmin}},
+		{PermissionReviewsReply, []Role{RoleManager, RoleOwner, RoleAdmin}},
+		{PermissionBasketsWrite, []Role{RoleClerk, RoleManager, RoleOwner, RoleAdmin}},
+		{PermissionOrdersRead, []Role{RoleClerk, RoleManager, RoleOwner, RoleAdmin}},
+		{PermissionOrdersVerify, []Role{RoleClerk, RoleManager, RoleOwner, RoleAdmin}},
+	}
+	roles := []Role{RoleCustomer, RoleClerk, RoleManager, RoleOwner, RoleAdmin}
+
+	for _, tt := range tests {
+		allowed := map[Role]bool{}
+		for _, role := range tt.allowed {
+			allowed[role] = true
+		}
+		for _, role := range roles {
+			if got := role.Allows(tt.permission); got != allowed[role] {
+				t.Errorf("%s.Allows(%s) = %v, want %v", role, tt.permission, got, allowed[role])
+			}
+		}
+	}
+}
+
+func TestPolicyAuthorizeAcrossStores(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ownerA := testutil.CreateUser(t, db, "owner-a@example.com")
+	ownerB := testutil.CreateUser(t, db, "owner-b@example.com")
+	managerB := testutil.CreateUser(t, db, "manager-b@example.com")
+	storeA := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, ownerA), "A")
+	storeB := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, ownerB), "B")
+	testutil.AddStaff(t, db, storeB, managerB, models.StaffRoleManager)
+
+	policy := NewPolicy(
+		repositories.NewUserRepository(db),
+		repositories.NewStoreRepository(db),
+		repositories.NewMerchantRepository(db),
+		repositories.NewStoreStaffRepository(db),
+	)
+
+	tests := []struct {
+		name    string
+		userID  uint
+		storeID uint
+		want    error
+	}{
+		{"propriétaire sur son magasin", ownerA.ID, storeA.ID, nil},
+		{"propriétaire sur le magasin d'un autre", ownerA.ID, storeB.ID, ErrPermissionDenied},
+		{"responsable sur son magasin", managerB.ID, storeB.ID, nil},
+		{"responsable sur un autre magasin", managerB.ID, storeA.ID, ErrPermissionDenied},
+		{"magasin inconnu", ownerA.ID, 999, ErrStoreNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := policy.Authorize(tt.userID, tt.storeID, PermissionScheduleManage)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Authorize() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package db
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
 	"gorm.io/driver/postgres"
@@ -15,8 +18,23 @@ func Init() *gorm.DB {
 		panic("failed to connect database")
 	}
 
+	// Le serveur ne démarre pas sur un schéma incomplet : un index manquant ferait échouer
+	// les requêtes qui en dépendent (ON CONFLICT…) seulement à l'exécution
+	if err := Migrate(db); err != nil {
+		panic(fmt.Sprintf("failed to migrate database: %v", err))
+	}
+	return db
+
+}
+
+// Migrate met le schéma à jour et crée les données de référence
+func Migrate(db *gorm.DB) error {
+	if err := dedupeFavorites(db); err != nil {
+		return err
+	}
+
 	// Auto-migrations
-	db.AutoMigrate(
+	err := db.AutoMigrate(
 		&models.Basket{},
 		&models.BasketStatus{},
 		&models.BasketConfiguration{},
@@ -28,18 +46,61 @@ func Init() *gorm.DB {
 		&models.StoreCategory{},
 		&models.Category{},
 		&models.StoreFavorite{},
+		&models.StoreOpeningHour{},
+		&models.StoreClosure{},
 		&models.StripeCustomer{},
 		&models.Order{},
+		&models.ProcessedPaymentEvent{},
+		&models.PaymentRefund{},
+		&models.Review{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.OutboxEmail{},
+		&models.PasswordResetToken{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.EmailChangeRequest{},
 
 		&models.Invitation{},
 	)
-	initDefaultCategories(db)
-	initDefaultStatuses(db)
-	return db
+	if err != nil {
+		return err
+	}
+	if err := dropLegacyColumns(db); err != nil {
+		return err
+	}
+	if err := initDefaultCategories(db); err != nil {
+		return err
+	}
+	return initDefaultStatuses(db)
+}
+
+// dedupeFavorites supprime les favoris en double avant la création de l'index unique
+// (user_id, store_id), dont dépend l'ajout idempotent d'un favori. Seul le plus ancien est conservé.
+func dedupeFavorites(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.StoreFavorite{}) ||
+		db.Migrator().HasIndex(&models.StoreFavorite{}, "idx_store_favorites_user_store") {
+		return nil
+	}
+	return db.Exec(`DELETE FROM store_favorites a USING store_favorites b
+		WHERE a.user_id = b.user_id AND a.store_id = b.store_id
+		AND (a.created_at, a.ctid) > (b.created_at, b.ctid)`).Error
+}
 
+// dropLegacyColumns supprime les colonnes qui ne sont plus utilisées ; les refresh tokens étaient
+// stockés en clair dans users avant d'être déplacés dans la table des sessions
+func dropLegacyColumns(db *gorm.DB) error {
+	for _, column := range []string{"refresh_token", "expiry_time"} {
+		if db.Migrator().HasColumn("users", column) {
+			if err := db.Migrator().DropColumn("users", column); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-func initDefaultCategories(db *gorm.DB) {
+func initDefaultCategories(db *gorm.DB) error {
 	defaultCategories := []models.Category{
 		{Name: "Boulangerie"},
 		{Name: "Epicerie"},
@@ -51,25 +112,37 @@ func initDefaultCategories(db *gorm.DB) {
 		var existingCategory models.Category
 		result := db.Where("name = ?", category.Name).First(&existingCategory)
 
+		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return result.Error
+		}
 		if result.RowsAffected == 0 {
-			db.Create(&category)
+			if err := db.Create(&category).Error; err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
-func initDefaultStatuses(db *gorm.DB) {
+func initDefaultStatuses(db *gorm.DB) error {
 	defaultStatuses := []models.BasketStatus{
-		{Name: "Disponible"},
-		{Name: "Réservé"},
-		{Name: "Vendu"},
-		{Name: "Annulé"},
+		{Name: models.BasketStatusAvailable},
+		{Name: models.BasketStatusReserved},
+		{Name: models.BasketStatusSold},
+		{Name: models.BasketStatusCancelled},
 	}
 
 	for _, status := range defaultStatuses {
 		var existingStatus models.BasketStatus
 		result := db.Where("name = ?", status.Name).First(&existingStatus)
 
+		if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return result.Error
+		}
 		if result.RowsAffected == 0 {
-			db.Create(&status)
+			if err := db.Create(&status).Error; err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
@@ -202,6 +202,131 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/admin/reviews/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permet à un administrateur de masquer un avis (ou de le réafficher) ; la note du magasin est recalculée",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Masquer ou réafficher un avis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'avis",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Modération",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ModerateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permet à un administrateur de supprimer un avis ; la note du magasin est recalculée",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Supprimer un avis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'avis",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/admin/users": {
             "get": {
                 "security": [
@@ -251,9 +376,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/auth/login": {
+        "/api/auth/forgot-password": {
             "post": {
-                "description": "Authenticate a user using email and password",
+                "description": "Envoie un lien de réinitialisation du mot de passe, valable une heure et à usage unique. La réponse est la même que l'email corresponde ou non à un compte.",
                 "consumes": [
                     "application/json"
                 ],
@@ -263,15 +388,15 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Authenticate user",
+                "summary": "Mot de passe oublié",
                 "parameters": [
                     {
-                        "description": "User credentials",
-                        "name": "credentials",
+                        "description": "Email du compte",
+                        "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.LoginRequest"
+                            "$ref": "#/definitions/requests.ForgotPasswordRequest"
                         }
                     }
                 ],
@@ -279,7 +404,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.LoginResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -291,8 +419,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -303,14 +431,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/auth/refresh-token": {
+        "/api/auth/login": {
             "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Refresh the user's authentication token",
+                "description": "Authenticate a user using email and password",
                 "consumes": [
                     "application/json"
                 ],
@@ -320,15 +443,15 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Refresh user token",
+                "summary": "Authenticate user",
                 "parameters": [
                     {
-                        "description": "Refresh token",
-                        "name": "refresh_token",
+                        "description": "User credentials",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.RefreshTokenRequest"
+                            "$ref": "#/definitions/requests.LoginRequest"
                         }
                     }
                 ],
@@ -339,8 +462,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.LoginResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -348,8 +471,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -360,33 +483,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/auth/resend-code": {
+        "/api/auth/logout": {
             "post": {
-                "description": "Resend the validation code to the user's email",
+                "description": "Révoque la session du refresh token ; le jeton d'accès en cours expire de lui-même",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Users"
+                    "Sessions"
                 ],
-                "summary": "Resend validation code",
+                "summary": "Se déconnecter",
                 "parameters": [
                     {
-                        "description": "Email address",
-                        "name": "email",
+                        "description": "Refresh token",
+                        "name": "refresh_token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.ResendCodeRequest"
+                            "$ref": "#/definitions/requests.RefreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -394,8 +517,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -406,9 +538,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/auth/signup": {
+        "/api/auth/refresh-token": {
             "post": {
-                "description": "Create a user with the provided details",
+                "description": "Échange un refresh token contre un nouveau jeton d'accès et un nouveau refresh token. Le refresh token présenté ne peut plus servir : s'il est présenté de nouveau, la session entière est révoquée.",
                 "consumes": [
                     "application/json"
                 ],
@@ -416,33 +548,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Sessions"
                 ],
-                "summary": "Create a new user",
+                "summary": "Rafraîchir le jeton d'accès",
                 "parameters": [
                     {
-                        "description": "User data",
-                        "name": "user",
+                        "description": "Refresh token",
+                        "name": "refresh_token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.RegisterRequest"
+                            "$ref": "#/definitions/requests.RefreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/responses.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/auth/validate-code": {
+        "/api/auth/resend-code": {
             "post": {
-                "description": "Valide le code envoyé par email et active le compte",
+                "description": "Send a new validation code, replacing the previous one, to an unconfirmed account. At most one code is sent per minute. The response is the same whether or not the email matches an account.",
                 "consumes": [
                     "application/json"
                 ],
@@ -452,15 +611,15 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Valider le code de confirmation",
+                "summary": "Resend validation code",
                 "parameters": [
                     {
-                        "description": "Email et code",
-                        "name": "validation",
+                        "description": "Email address",
+                        "name": "email",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.CodeValidationRequest"
+                            "$ref": "#/definitions/requests.ResendCodeRequest"
                         }
                     }
                 ],
@@ -482,18 +641,171 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/baskets/": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Retrieve a list of all baskets",
+        "/api/auth/reset-password": {
+            "post": {
+                "description": "Remplace le mot de passe avec le jeton reçu par email. Le jeton ne sert qu'une fois et les sessions ouvertes doivent se reconnecter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Réinitialiser le mot de passe",
+                "parameters": [
+                    {
+                        "description": "Jeton et nouveau mot de passe",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/signup": {
+            "post": {
+                "description": "Create a user with the provided details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/validate-code": {
+            "post": {
+                "description": "Valide le code envoyé par email et active le compte. Le code expire après 10 minutes et est bloqué après 5 saisies erronées ; un nouveau code doit alors être demandé. La même erreur est retournée que l'email corresponde ou non à un compte.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Valider le code de confirmation",
+                "parameters": [
+                    {
+                        "description": "Email et code",
+                        "name": "validation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CodeValidationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/baskets/": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a page of baskets matching the filters. When lat and lng are given, only baskets of stores within radius_km are returned. Pass nextCursor back as cursor to get the next page.",
                 "consumes": [
                     "application/json"
                 ],
@@ -511,15 +823,112 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the search point",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the search point",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Search radius in km (default 10, max 100)",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Store category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum final price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum final price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum discount (0.2 for 20%)",
+                        "name": "min_discount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only baskets in stock with status Disponible",
+                        "name": "available",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only baskets whose pickup window covers this day in the store's time zone (YYYY-MM-DD)",
+                        "name": "pickup_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only baskets whose pickup window ends after this time (RFC 3339)",
+                        "name": "pickup_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only baskets whose pickup window starts before this time (RFC 3339)",
+                        "name": "pickup_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "newest",
+                            "price",
+                            "discount",
+                            "distance",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/responses.BasketResponse"
+                            "$ref": "#/definitions/responses.BasketListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid search parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -597,6 +1006,66 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/baskets/stream": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Server-Sent Events : mêmes événements que le flux d'un magasin, pour tous les magasins situés à moins de radius_km du point donné",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Baskets"
+                ],
+                "summary": "Flux temps réel des paniers autour d'un point",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude du point",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude du point",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Rayon en km (10 par défaut, 100 au maximum)",
+                        "name": "radius_km",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Contenu (data) de chaque événement",
+                        "schema": {
+                            "$ref": "#/definitions/responses.BasketStreamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/baskets/{id}": {
             "get": {
                 "security": [
@@ -764,6 +1233,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "store_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -863,7 +1339,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Permet à un marchand d'inviter une personne à rejoindre son magasin en tant que staff",
+                "description": "Permet au commerçant ou à un responsable du magasin d'inviter une personne à rejoindre son équipe. Un lien signé est envoyé par email ; seul un compte avec cet email pourra accepter l'invitation.",
                 "consumes": [
                     "application/json"
                 ],
@@ -888,15 +1364,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "email": {
-                                    "type": "string"
-                                },
-                                "store_id": {
-                                    "type": "integer"
-                                }
-                            }
+                            "$ref": "#/definitions/requests.CreateInvitationRequest"
                         }
                     }
                 ],
@@ -909,6 +1377,9 @@ const docTemplate = `{
                                 "code": {
                                     "type": "string"
                                 },
+                                "data": {
+                                    "$ref": "#/definitions/responses.InvitationResponse"
+                                },
                                 "message": {
                                     "type": "string"
                                 }
@@ -947,18 +1418,40 @@ const docTemplate = `{
                                 }
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/invitations/accept": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
+                    },
+                    "404": {
+                        "description": "Magasin introuvable",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation déjà en attente pour cet email",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/invitations/accept": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
                     }
                 ],
-                "description": "Permet à un utilisateur d'accepter une invitation à rejoindre un store en tant que staff",
+                "description": "Permet à l'utilisateur invité d'accepter une invitation avec le lien signé reçu par email (token) ou avec son code. L'email du compte doit être celui de l'invitation.",
                 "consumes": [
                     "application/json"
                 ],
@@ -977,12 +1470,17 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Jeton signé du lien d'invitation",
+                        "name": "token",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Code d'invitation",
                         "name": "code",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1018,18 +1516,51 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    },
+                    "403": {
+                        "description": "Invitation adressée à un autre email",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation introuvable",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation expirée ou déjà traitée",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/invitations/{invitationId}": {
+        "/api/invitations/{id}": {
             "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Permet à un marchand d'annuler une invitation envoyée",
+                "description": "Permet au commerçant ou à un responsable du magasin d'annuler une invitation en attente",
                 "produces": [
                     "application/json"
                 ],
@@ -1048,7 +1579,7 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "description": "ID de l'invitation",
-                        "name": "invitationId",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -1086,25 +1617,58 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    },
+                    "403": {
+                        "description": "Accès non autorisé",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation introuvable",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation déjà traitée",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/merchants": {
-            "get": {
+        "/api/invitations/{id}/resend": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Récupère les informations du marchand actuel",
+                "description": "Renvoie une invitation en attente ou expirée avec un nouveau code et une validité de 7 jours ; les liens envoyés précédemment ne fonctionnent plus",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Merchants"
+                    "invitations"
                 ],
-                "summary": "Récupérer information d'un marchand",
+                "summary": "Renvoyer une invitation",
                 "parameters": [
                     {
                         "type": "string",
@@ -1112,35 +1676,99 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'invitation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Informations du marchand",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Merchant"
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                },
+                                "data": {
+                                    "$ref": "#/definitions/responses.InvitationResponse"
+                                },
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Non authentifié",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Non autorisé",
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Marchand non trouvé",
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation acceptée, refusée ou annulée, ou une autre invitation déjà en attente pour cette adresse",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Erreur serveur",
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retourne le profil, les rôles (administrateur, marchand, équipes de magasin), le changement d'email en attente et les préférences de notification de l'utilisateur connecté",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Profil de l'utilisateur connecté",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProfileResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1153,7 +1781,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Permet à un marchand de mettre à jour ses informations",
+                "description": "Modifie le nom affiché, le téléphone, la langue, le lieu de recherche par défaut et les préférences de notification ; les champs absents sont conservés",
                 "consumes": [
                     "application/json"
                 ],
@@ -1161,9 +1789,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Merchants"
+                    "Users"
                 ],
-                "summary": "Update un marchand",
+                "summary": "Modifier le profil",
                 "parameters": [
                     {
                         "type": "string",
@@ -1173,12 +1801,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Données du marchand",
+                        "description": "Profil",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.UpdateMerchantRequest"
+                            "$ref": "#/definitions/requests.UpdateProfileRequest"
                         }
                     }
                 ],
@@ -1186,7 +1814,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/responses.ProfileResponse"
                         }
                     },
                     "400": {
@@ -1195,23 +1823,39 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/me/default-location": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Efface le lieu de recherche par défaut de l'utilisateur connecté",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Effacer le lieu de recherche par défaut",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -1220,14 +1864,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/me/email": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Permet à un utilisateur de soumettre une demande pour devenir marchand",
+                "description": "Envoie un code de vérification, valable 10 minutes, à la nouvelle adresse après vérification du mot de passe. L'email du compte n'est remplacé qu'à la confirmation du code ; une nouvelle demande remplace la précédente.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1237,7 +1883,7 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Créer une demande de marchand",
+                "summary": "Changer d'adresse email",
                 "parameters": [
                     {
                         "type": "string",
@@ -1247,20 +1893,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Données de la demande",
+                        "description": "Nouvelle adresse et mot de passe actuel",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.CreateMerchantRequest"
+                            "$ref": "#/definitions/requests.ChangeEmailRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/responses.PendingEmailResponse"
                         }
                     },
                     "400": {
@@ -1269,8 +1915,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1295,17 +1941,11 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Permet à un marchand de supprimer son compte",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Annule le changement d'email en attente de l'utilisateur connecté",
                 "tags": [
-                    "Merchants"
+                    "Users"
                 ],
-                "summary": "Suppression d'un marchand",
+                "summary": "Annuler le changement d'email",
                 "parameters": [
                     {
                         "type": "string",
@@ -1316,26 +1956,69 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    }
+                }
+            }
+        },
+        "/api/me/email/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remplace l'email du compte par la nouvelle adresse avec le code qui lui a été envoyé ; l'ancienne adresse en est avertie. Le code est bloqué après 5 saisies erronées. Le prochain rafraîchissement des jetons reflète la nouvelle adresse.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Confirmer le changement d'email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Code reçu à la nouvelle adresse",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/requests.ConfirmEmailChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProfileResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1349,21 +2032,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/merchants/request-status": {
+        "/api/me/favorites": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Récupère les détails de la demande de marchand de l'utilisateur actuel",
+                "description": "Retourne les magasins favoris de l'utilisateur connecté avec leurs paniers actuellement réservables",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Favorites"
                 ],
-                "summary": "Récupérer la demande du marchand",
+                "summary": "Magasins favoris",
                 "parameters": [
                     {
                         "type": "string",
@@ -1375,31 +2058,28 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Détails de la demande",
-                        "schema": {
-                            "$ref": "#/definitions/models.MerchantRequest"
-                        }
-                    },
-                    "401": {
-                        "description": "Non authentifié",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Non autorisé",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Demande non trouvée",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/responses.FavoriteStoreResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
-                        "description": "Erreur serveur",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1407,22 +2087,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/merchants/stores": {
+        "/api/me/invitations": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retourne les invitations en attente adressées à l'email de l'utilisateur connecté",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "invitations"
                 ],
+                "summary": "Mes invitations",
                 "parameters": [
                     {
                         "type": "string",
@@ -1436,13 +2115,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/responses.InvitationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
@@ -1452,22 +2140,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/me/invitations/{id}/accept": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Permet à l'utilisateur invité d'accepter une invitation de sa liste",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "invitations"
                 ],
+                "summary": "Accepter une invitation reçue",
                 "parameters": [
                     {
                         "type": "string",
@@ -1477,18 +2166,16 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Données de la demande",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.CreateStoreRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID de l'invitation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -1499,14 +2186,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Invitation adressée à un autre email",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "Conflict",
+                        "description": "Invitation expirée ou déjà traitée",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1520,22 +2213,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/merchants/stores/{id}": {
-            "put": {
+        "/api/me/invitations/{id}/decline": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Permet à l'utilisateur invité de refuser une invitation qui lui est adressée",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "invitations"
                 ],
+                "summary": "Refuser une invitation",
                 "parameters": [
                     {
                         "type": "string",
@@ -1546,30 +2238,36 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Magasin ID",
+                        "description": "ID de l'invitation",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Données de la demande",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.UpdateStoreRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Invitation adressée à un autre email",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation expirée ou déjà traitée",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1581,22 +2279,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/me/logout-all": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Révoque toutes les sessions de l'utilisateur, y compris la session courante",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "Sessions"
                 ],
+                "summary": "Se déconnecter de tous les appareils",
                 "parameters": [
                     {
                         "type": "string",
@@ -1604,26 +2303,16 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Magasin ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "500": {
@@ -1635,22 +2324,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/stores": {
+        "/api/me/notification-preferences": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retourne les préférences de notification de l'utilisateur connecté",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "Notifications"
                 ],
+                "summary": "Préférences de notification",
                 "parameters": [
                     {
                         "type": "string",
@@ -1664,13 +2352,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/responses.NotificationPreferencesResponse"
                         }
                     },
                     "500": {
@@ -1680,15 +2362,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/stores/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Active ou désactive les alertes de nouveaux paniers et les canaux de diffusion ; les champs absents sont conservés",
                 "consumes": [
                     "application/json"
                 ],
@@ -1696,8 +2377,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "Notifications"
                 ],
+                "summary": "Modifier les préférences de notification",
                 "parameters": [
                     {
                         "type": "string",
@@ -1707,28 +2389,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID du magasin",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Préférences",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UpdateNotificationPreferencesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/responses.NotificationPreferencesResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1742,22 +2420,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/stores/{id}/basket-config": {
+        "/api/me/notifications": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retourne les notifications de l'utilisateur connecté, de la plus récente à la plus ancienne, avec le nombre de notifications non lues",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "Notifications"
                 ],
+                "summary": "Boîte de réception",
                 "parameters": [
                     {
                         "type": "string",
@@ -1766,32 +2443,77 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "boolean",
+                        "description": "Uniquement les notifications non lues",
+                        "name": "unread",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID du magasin",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Numéro de page (à partir de 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de notifications par page (20 par défaut, 100 au maximum)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/responses.NotificationListResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/me/notifications/read-all": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Marque comme lues toutes les notifications de l'utilisateur connecté",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Tout marquer comme lu",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MarkAllReadResponse"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -1800,22 +2522,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/me/notifications/{id}/read": {
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Marque comme lue une notification de l'utilisateur connecté",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "Notifications"
                 ],
+                "summary": "Marquer une notification comme lue",
                 "parameters": [
                     {
                         "type": "string",
@@ -1826,26 +2549,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "ID du magasin",
+                        "description": "ID de la notification",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Données de la demande",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.UpdateBasketConfigurationRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/responses.NotificationResponse"
                         }
                     },
                     "400": {
@@ -1854,12 +2568,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1873,13 +2581,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/me/password": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Remplace le mot de passe de l'utilisateur connecté après vérification du mot de passe actuel ; ses autres sessions sont déconnectées",
                 "consumes": [
                     "application/json"
                 ],
@@ -1887,8 +2598,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "Users"
                 ],
+                "summary": "Changer de mot de passe",
                 "parameters": [
                     {
                         "type": "string",
@@ -1898,70 +2610,70 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID du magasin",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Données de la demande",
+                        "description": "Mot de passe actuel et nouveau mot de passe",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.CreateBasketConfigurationRequest"
+                            "$ref": "#/definitions/requests.ChangePasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/me/sessions": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retourne les appareils sur lesquels l'utilisateur est connecté, le plus récemment utilisé en premier",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stores"
+                    "Sessions"
                 ],
+                "summary": "Sessions actives",
                 "parameters": [
                     {
                         "type": "string",
@@ -1969,32 +2681,28 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID du magasin",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/responses.SessionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
@@ -2006,22 +2714,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/stores/{id}/staffs": {
-            "get": {
+        "/api/me/sessions/{id}": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Révoque une session de l'utilisateur : son refresh token ne peut plus servir",
                 "tags": [
-                    "Stores"
+                    "Sessions"
                 ],
+                "summary": "Déconnecter un appareil",
                 "parameters": [
                     {
                         "type": "string",
@@ -2032,21 +2736,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "ID du magasin",
+                        "description": "ID de la session",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -2066,21 +2767,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/stores/{storeId}/invitations": {
+        "/api/merchants": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Permet à un marchand de voir toutes les invitations en attente pour son magasin",
+                "description": "Récupère les informations du marchand actuel",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "invitations"
+                    "Merchants"
                 ],
-                "summary": "Récupérer les invitations en attente pour un magasin",
+                "summary": "Récupérer information d'un marchand",
                 "parameters": [
                     {
                         "type": "string",
@@ -2088,69 +2789,48 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID du store",
-                        "name": "storeId",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Liste des invitations en attente",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Invitation"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Erreur dans la requête",
+                        "description": "Informations du marchand",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.Merchant"
                         }
                     },
                     "401": {
                         "description": "Non authentifié",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Accès non autorisé",
+                        "description": "Non autorisé",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Marchand non trouvé",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur serveur",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/stores/{store_id}/baskets": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all baskets for a specific store",
+                "description": "Permet à un marchand de mettre à jour ses informations",
                 "consumes": [
                     "application/json"
                 ],
@@ -2158,9 +2838,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Baskets"
+                    "Merchants"
                 ],
-                "summary": "Get baskets by store",
+                "summary": "Update un marchand",
                 "parameters": [
                     {
                         "type": "string",
@@ -2170,164 +2850,3805 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Store ID",
-                        "name": "store_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Données du marchand",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UpdateMerchantRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/responses.BasketResponse"
-                            }
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid store ID",
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Store not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permet à un utilisateur de soumettre une demande pour devenir marchand",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Créer une demande de marchand",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de la demande",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CreateMerchantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permet à un marchand de supprimer son compte",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Merchants"
+                ],
+                "summary": "Suppression d'un marchand",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/request-status": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Récupère les détails de la demande de marchand de l'utilisateur actuel",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Récupérer la demande du marchand",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Détails de la demande",
+                        "schema": {
+                            "$ref": "#/definitions/models.MerchantRequest"
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Non autorisé",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Demande non trouvée",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erreur serveur",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de la demande",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CreateStoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Magasin ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de la demande",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UpdateStoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Magasin d'un autre commerçant",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Magasin ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Magasin d'un autre commerçant",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}/basket-configurations": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.BasketConfigurationResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de la demande",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CreateBasketConfigurationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.BasketConfigurationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}/basket-configurations/{configId}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la configuration",
+                        "name": "configId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Données de la demande",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UpdateBasketConfigurationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.BasketConfigurationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la configuration",
+                        "name": "configId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}/closures": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.StoreClosureResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fermeture exceptionnelle",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.StoreClosureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.StoreClosureResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}/closures/{closureId}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la fermeture",
+                        "name": "closureId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fermeture exceptionnelle",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.StoreClosureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.StoreClosureResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la fermeture",
+                        "name": "closureId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}/opening-hours": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Planning hebdomadaire",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UpdateOpeningHoursRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.OpeningHourResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}/reviews/{reviewId}/reply": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permet au commerçant ou à un responsable du magasin de répondre à un avis ; une nouvelle réponse remplace la précédente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Répondre à un avis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'avis",
+                        "name": "reviewId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Réponse",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ReplyReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}/staff": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retourne les membres du staff du magasin avec leur rôle ; réservé au commerçant propriétaire et aux responsables",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "Obtenir les membres d'un magasin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/responses.StaffMemberResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/merchants/stores/{id}/staff/{userId}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permet au commerçant propriétaire de nommer un membre du staff responsable (manager) ou employé (clerk)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "Changer le rôle d'un membre du staff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur membre du staff",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rôle",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UpdateStaffRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.StaffMemberResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retire l'utilisateur de l'équipe du magasin ; ses accès au magasin sont révoqués immédiatement, sans attendre l'expiration de son JWT. Un responsable ne peut retirer que des employés.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "Retirer un membre du staff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur membre du staff",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Réserve un exemplaire d'un panier pour l'utilisateur connecté et génère un code de retrait unique",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Réserver un panier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Panier à réserver",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Panier introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Panier plus disponible ou plage de retrait terminée",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retourne les commandes de l'utilisateur connecté, de la plus récente à la plus ancienne",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Historique des commandes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.OrderResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/stream": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Server-Sent Events pour l'écran des retraits : un événement (order.reserved, order.paid, order.cancelled, order.picked_up) est envoyé à chaque étape du cycle de vie d'une commande des magasins dont l'utilisateur est propriétaire ou membre du staff.\nLes droits sont vérifiés en base à la connexion puis régulièrement : le flux est fermé dès que l'utilisateur est retiré du staff.\nÀ la connexion, un événement orders.snapshot contient les commandes en attente de paiement ou de retrait. Un client qui se reconnecte avec l'en-tête Last-Event-ID (ou le paramètre last_event_id) reçoit les événements manqués à la place, ou un nouveau snapshot s'ils ne sont plus disponibles.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Flux temps réel des commandes du magasin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limite le flux à un magasin",
+                        "name": "store_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID du dernier événement reçu",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du dernier événement reçu (clients ne pouvant pas définir d'en-tête)",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Contenu (data) de chaque événement order.* ; orders.snapshot contient responses.OrderSnapshotEvent",
+                        "schema": {
+                            "$ref": "#/definitions/responses.OrderStreamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/pay": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Règle une commande en attente ; elle passe à l'état \"confirmed\" une fois le paiement réussi",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Payer une commande",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la commande",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Moyen de paiement",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.PayOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Paiement refusé",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Commande d'un autre utilisateur",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Commande introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Commande déjà payée, annulée ou expirée",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/review": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permet au client de noter (1 à 5) une de ses commandes remises, avec un commentaire facultatif. La note du magasin est recalculée.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Noter une commande",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de la commande",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Avis",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Commande d'un autre utilisateur",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Commande introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Commande non remise ou déjà notée",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.StoreDetailResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}/baskets": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve all baskets for a specific store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Baskets"
+                ],
+                "summary": "Get baskets by store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.BasketByStoreResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid store ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Store not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}/baskets/stream": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Server-Sent Events : un événement (basket.created, basket.updated, basket.reserved, basket.sold_out, basket.restocked, basket.deleted, basket.expired) est envoyé à chaque changement de quantité ou de statut d'un panier du magasin",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Baskets"
+                ],
+                "summary": "Flux temps réel des paniers d'un magasin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Contenu (data) de chaque événement",
+                        "schema": {
+                            "$ref": "#/definitions/responses.BasketStreamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}/favorite": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Ajoute le magasin aux favoris de l'utilisateur connecté ; sans effet s'il y est déjà",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favorites"
+                ],
+                "summary": "Ajouter un magasin aux favoris",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Magasin introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retire le magasin des favoris de l'utilisateur connecté ; sans effet s'il n'y est pas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favorites"
+                ],
+                "summary": "Retirer un magasin des favoris",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Permet au commerçant ou à un responsable de voir toutes les invitations en attente pour son magasin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "Récupérer les invitations en attente pour un magasin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du store",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des invitations en attente",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/responses.InvitationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Erreur dans la requête",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès non autorisé",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Magasin introuvable",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}/orders/verify": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Vérifie au comptoir le code de retrait (ou le QR code) d'une commande du magasin et la marque comme remise",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Valider un code de retrait",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Code de retrait ou contenu du QR code",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.VerifyOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Code manquant ou QR code invalide",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Commande d'un autre magasin",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Commande introuvable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Commande non payée, déjà remise, annulée ou hors de la plage de retrait",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}/request-status-statustions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Ancienne adresse de GET /api/stores/{id}/invitations, conservée pour les clients existants : même contrôle d'accès, liste renvoyée sans enveloppe data. Les en-têtes Deprecation et Link indiquent la route qui la remplace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "Récupérer les invitations en attente pour un magasin (ancienne route)",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du store",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liste des invitations en attente",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.InvitationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Erreur dans la requête",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Non authentifié",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Accès non autorisé",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Magasin introuvable",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores/{id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retourne les avis visibles du magasin, du plus récent au plus ancien",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Avis d'un magasin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID du magasin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Numéro de page (à partir de 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre d'avis par page (20 par défaut, 100 au maximum)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ReviewListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/payments": {
+            "post": {
+                "description": "Reçoit les événements du prestataire de paiement (payment_succeeded, payment_failed, payment_refunded), signés par HMAC dans l'en-tête X-Payment-Signature. Chaque événement n'est appliqué qu'une seule fois.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Recevoir un événement de paiement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signature t=\u003ctimestamp\u003e,v1=\u003chmac\u003e",
+                        "name": "X-Payment-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Événement invalide",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Signature invalide",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ProcessRequestInput": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "description": "Status de la demande (approved/rejected)",
+                    "type": "string",
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ],
+                    "example": "approved"
+                }
+            }
+        },
+        "models.Basket": {
+            "type": "object",
+            "required": [
+                "discount_percentage",
+                "name",
+                "original_price",
+                "quantity",
+                "status_id",
+                "store_id"
+            ],
+            "properties": {
+                "configuration": {
+                    "$ref": "#/definitions/models.BasketConfiguration"
+                },
+                "configuration_id": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_percentage": {
+                    "type": "number"
+                },
+                "expiration_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "original_price": {
+                    "type": "number"
+                },
+                "pickup_end": {
+                    "description": "Fin de la plage de retrait",
+                    "type": "string"
+                },
+                "pickup_start": {
+                    "description": "Début de la plage de retrait",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.BasketStatus"
+                },
+                "status_id": {
+                    "type": "integer"
+                },
+                "store": {
+                    "$ref": "#/definitions/models.Store"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BasketConfiguration": {
+            "type": "object",
+            "required": [
+                "discount_percentage",
+                "name",
+                "quantity",
+                "store_id"
+            ],
+            "properties": {
+                "active": {
+                    "description": "La publication automatique est activée",
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_percentage": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_published_on": {
+                    "description": "Dernier jour (YYYY-MM-DD) pour lequel un panier a été publié",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "original_price": {
+                    "description": "Prix original des paniers publiés",
+                    "type": "number"
+                },
+                "pickup_end_time": {
+                    "description": "Heure de fin de retrait (HH:MM, fuseau du magasin)",
+                    "type": "string"
+                },
+                "pickup_start_time": {
+                    "description": "Heure de début de retrait (HH:MM, fuseau du magasin)",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "store": {
+                    "$ref": "#/definitions/models.Store"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "weekdays": {
+                    "description": "Jours de publication (1 = lundi ... 7 = dimanche), séparés par des virgules",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BasketStatus": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Message d'erreur"
+                }
+            }
+        },
+        "models.Merchant": {
+            "type": "object",
+            "required": [
+                "business_name",
+                "email_pro",
+                "siren"
+            ],
+            "properties": {
+                "business_name": {
+                    "description": "Nom de l'entreprise",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "email_pro": {
+                    "description": "Email valide requis",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "phone_number": {
+                    "description": "Numéro de téléphone (optionnel, max 15 caractères)",
+                    "type": "string"
+                },
+                "siren": {
+                    "description": "Numéro SIREN",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "Relation vers User (clé étrangère avec cascade)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "Relation 1 à 1 vers User",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MerchantRequest": {
+            "type": "object",
+            "required": [
+                "business_name",
+                "email_pro",
+                "siren"
+            ],
+            "properties": {
+                "business_name": {
+                    "type": "string"
+                },
+                "comment": {
+                    "description": "Commentaire de l'administrateur",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "email_pro": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "siren": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, approved, rejected",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "user_id": {
+                    "description": "Relation avec l'utilisateur qui fait la demande",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Opération réussie"
+                }
+            }
+        },
+        "models.Store": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Adresse complète",
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Relation avec Category (clé étrangère)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    ]
+                },
+                "category_id": {
+                    "description": "ID de la catégorie (clé étrangère)",
+                    "type": "integer"
+                },
+                "city": {
+                    "description": "Ville",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_favorite": {
+                    "description": "Favori de l'utilisateur connecté (calculé, non stocké)",
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "description": "Latitude (format décimal)",
+                    "type": "number"
+                },
+                "longitude": {
+                    "description": "Longitude (format décimal)",
+                    "type": "number"
+                },
+                "merchant": {
+                    "description": "Relation avec Merchant (clé étrangère)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Merchant"
+                        }
+                    ]
+                },
+                "merchant_id": {
+                    "description": "ID du commerçant (clé étrangère)",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Nom du magasin (obligatoire)",
+                    "type": "string"
+                },
+                "phone_number": {
+                    "description": "Numéro de téléphone (optionnel, max 15 caractères)",
+                    "type": "string"
+                },
+                "postal_code": {
+                    "description": "Code postal (limité à 10 caractères pour compatibilité internationale)",
+                    "type": "string"
+                },
+                "rating": {
+                    "description": "Note moyenne (sur 5), recalculée à chaque avis",
+                    "type": "number"
+                },
+                "review_count": {
+                    "description": "Nombre d'avis visibles",
+                    "type": "integer"
+                },
+                "time_zone": {
+                    "description": "Fuseau horaire IANA du magasin (horaires de retrait)",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "required": [
+                "email",
+                "password_hash"
+            ],
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "default_latitude": {
+                    "description": "Lieu de recherche par défaut, nil si non défini",
+                    "type": "number"
+                },
+                "default_location_label": {
+                    "description": "Libellé du lieu de recherche par défaut",
+                    "type": "string"
+                },
+                "default_longitude": {
+                    "type": "number"
+                },
+                "default_radius_km": {
+                    "description": "Rayon de recherche par défaut, nil pour celui de l'API",
+                    "type": "number"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "display_name": {
+                    "description": "Nom affiché dans l'application",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Validation d'email",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isEmailConfirmed": {
+                    "description": "L'email a-t-il été confirmé ?",
+                    "type": "boolean"
+                },
+                "is_admin": {
+                    "description": "Est-ce un administrateur ?",
+                    "type": "boolean"
+                },
+                "password_hash": {
+                    "description": "Hash du mot de passe",
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "Téléphone au format international (+32470123456)",
+                    "type": "string"
+                },
+                "preferred_language": {
+                    "description": "Langue préférée de l'application",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.ChangeEmailRequest": {
+            "type": "object",
+            "required": [
+                "new_email",
+                "password"
+            ],
+            "properties": {
+                "new_email": {
+                    "type": "string",
+                    "example": "new@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "requests.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "example": "newpassword123"
+                }
+            }
+        },
+        "requests.CodeValidationRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "email"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "requests.ConfirmEmailChangeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "requests.CreateBasketConfigurationRequest": {
+            "type": "object",
+            "required": [
+                "discount_percentage",
+                "name",
+                "original_price",
+                "quantity"
+            ],
+            "properties": {
+                "active": {
+                    "description": "Publication automatique activée (true par défaut)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_percentage": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "original_price": {
+                    "description": "Prix original des paniers publiés",
+                    "type": "number",
+                    "example": 22
+                },
+                "pickup_end_time": {
+                    "description": "Heure de fin de retrait (HH:MM, fuseau du magasin)",
+                    "type": "string",
+                    "example": "20:00"
+                },
+                "pickup_start_time": {
+                    "description": "Heure de début de retrait (HH:MM, fuseau du magasin)",
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "weekdays": {
+                    "description": "Jours de publication automatique (1 = lundi ... 7 = dimanche)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5,
+                        6
+                    ]
+                }
+            }
+        },
+        "requests.CreateBasketRequest": {
+            "type": "object",
+            "required": [
+                "discount_percentage",
+                "name",
+                "original_price",
+                "quantity",
+                "store_id"
+            ],
+            "properties": {
+                "configuration_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Ceci est un panier suprise"
+                },
+                "discount_percentage": {
+                    "description": "20% de réduction",
+                    "type": "number",
+                    "example": 0.2
+                },
+                "expiration_date": {
+                    "description": "Date d'expiration du panier, au format YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2022-12-31"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "panier surprise"
+                },
+                "original_price": {
+                    "description": "Prix original avant réduction",
+                    "type": "number",
+                    "example": 22
+                },
+                "pickup_end": {
+                    "description": "Fin de la plage de retrait (RFC 3339, avec fuseau)",
+                    "type": "string",
+                    "example": "2022-12-31T20:00:00+01:00"
+                },
+                "pickup_start": {
+                    "description": "Début de la plage de retrait (RFC 3339, avec fuseau)",
+                    "type": "string",
+                    "example": "2022-12-31T18:00:00+01:00"
+                },
+                "quantity": {
+                    "description": "Quantité disponible",
+                    "type": "integer",
+                    "example": 2
+                },
+                "store_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "requests.CreateInvitationRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "store_id"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.CreateMerchantRequest": {
+            "type": "object",
+            "required": [
+                "business_name",
+                "email_pro",
+                "siren"
+            ],
+            "properties": {
+                "business_name": {
+                    "type": "string",
+                    "example": "petit bateau"
+                },
+                "email_pro": {
+                    "type": "string",
+                    "example": "merchant@example.com"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "+32452101010"
+                },
+                "siren": {
+                    "type": "string",
+                    "example": "784671691"
+                }
+            }
+        },
+        "requests.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "basket_id"
+            ],
+            "properties": {
+                "basket_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "requests.CreateReviewRequest": {
+            "type": "object",
+            "required": [
+                "rating"
+            ],
+            "properties": {
+                "comment": {
+                    "description": "Commentaire (optionnel)",
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Panier très généreux"
+                },
+                "rating": {
+                    "description": "Note de 1 à 5",
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 5
+                }
+            }
+        },
+        "requests.CreateStoreRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Adresse complète",
+                    "type": "string",
+                    "example": "route de baduel 11"
+                },
+                "category_id": {
+                    "description": "ID de la catégorie (obligatoire)",
+                    "type": "integer",
+                    "example": 1
+                },
+                "city": {
+                    "description": "Ville",
+                    "type": "string",
+                    "example": "cayenne"
+                },
+                "name": {
+                    "description": "Nom du magasin (obligatoire)",
+                    "type": "string",
+                    "example": "petit bateau 1"
+                },
+                "phone_number": {
+                    "description": "Numéro de téléphone (optionnel, max 15 caractères)",
+                    "type": "string",
+                    "example": "+32470542125"
+                },
+                "postal_code": {
+                    "description": "Code postal (limité à 10 caractères pour compatibilité internationale)",
+                    "type": "string",
+                    "example": "97300"
+                },
+                "time_zone": {
+                    "description": "Fuseau horaire IANA (optionnel, Europe/Brussels par défaut)",
+                    "type": "string",
+                    "example": "Europe/Brussels"
+                }
             }
-        }
-    },
-    "definitions": {
-        "gorm.DeletedAt": {
+        },
+        "requests.DefaultLocationRequest": {
             "type": "object",
+            "required": [
+                "latitude",
+                "longitude"
+            ],
             "properties": {
-                "time": {
+                "label": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Grand-Place, Bruxelles"
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 50.8467
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 4.3525
+                },
+                "radius_km": {
+                    "description": "Absent pour le rayon par défaut de la recherche",
+                    "type": "number",
+                    "maximum": 100,
+                    "example": 5
+                }
+            }
+        },
+        "requests.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "requests.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "requests.ModerateReviewRequest": {
+            "type": "object",
+            "required": [
+                "hidden"
+            ],
+            "properties": {
+                "hidden": {
+                    "description": "true pour masquer l'avis, false pour le réafficher",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "requests.OpeningHourRequest": {
+            "type": "object",
+            "required": [
+                "closes_at",
+                "opens_at",
+                "weekday"
+            ],
+            "properties": {
+                "closes_at": {
+                    "description": "Heure de fermeture (HH:MM, fuseau du magasin)",
+                    "type": "string",
+                    "example": "19:30"
+                },
+                "opens_at": {
+                    "description": "Heure d'ouverture (HH:MM, fuseau du magasin)",
+                    "type": "string",
+                    "example": "08:00"
+                },
+                "weekday": {
+                    "description": "Jour de la semaine (1 = lundi ... 7 = dimanche)",
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "requests.PayOrderRequest": {
+            "type": "object",
+            "required": [
+                "payment_method_id"
+            ],
+            "properties": {
+                "payment_method_id": {
+                    "type": "string",
+                    "example": "pm_card_visa"
+                }
+            }
+        },
+        "requests.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
                     "type": "string"
+                }
+            }
+        },
+        "requests.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
                 },
-                "valid": {
-                    "description": "Valid is true if Time is not NULL",
-                    "type": "boolean"
+                "password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "example": "password123"
+                }
+            }
+        },
+        "requests.ReplyReviewRequest": {
+            "type": "object",
+            "required": [
+                "reply"
+            ],
+            "properties": {
+                "reply": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Merci pour votre visite !"
+                }
+            }
+        },
+        "requests.ResendCodeRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "example": "newpassword123"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.StoreClosureRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "description": "Dernier jour de fermeture, inclus (YYYY-MM-DD)",
+                    "type": "string",
+                    "example": "2025-12-26"
+                },
+                "reason": {
+                    "description": "Motif affiché aux clients (optionnel)",
+                    "type": "string",
+                    "example": "Fêtes de fin d'année"
+                },
+                "start_date": {
+                    "description": "Premier jour de fermeture (YYYY-MM-DD)",
+                    "type": "string",
+                    "example": "2025-12-24"
+                }
+            }
+        },
+        "requests.UpdateBasketConfigurationRequest": {
+            "type": "object",
+            "required": [
+                "discount_percentage",
+                "name",
+                "original_price",
+                "quantity"
+            ],
+            "properties": {
+                "active": {
+                    "description": "Publication automatique activée (inchangée si absente)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_percentage": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "original_price": {
+                    "description": "Prix original des paniers publiés",
+                    "type": "number",
+                    "example": 22
+                },
+                "pickup_end_time": {
+                    "description": "Heure de fin de retrait (HH:MM, fuseau du magasin)",
+                    "type": "string",
+                    "example": "20:00"
+                },
+                "pickup_start_time": {
+                    "description": "Heure de début de retrait (HH:MM, fuseau du magasin)",
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "weekdays": {
+                    "description": "Jours de publication automatique (1 = lundi ... 7 = dimanche)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5,
+                        6
+                    ]
+                }
+            }
+        },
+        "requests.UpdateMerchantRequest": {
+            "type": "object",
+            "required": [
+                "business_name",
+                "email_pro",
+                "siren"
+            ],
+            "properties": {
+                "business_name": {
+                    "description": "Nom de l'entreprise",
+                    "type": "string",
+                    "example": "petit bateau update"
+                },
+                "email_pro": {
+                    "description": "Email valide requis",
+                    "type": "string",
+                    "example": "merchantupdate@example.com"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "+32452101010"
+                },
+                "siren": {
+                    "description": "Numéro SIREN",
+                    "type": "string",
+                    "example": "784671695"
+                }
+            }
+        },
+        "requests.UpdateNotificationPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "Diffusion par email",
+                    "type": "boolean",
+                    "example": false
+                },
+                "in_app": {
+                    "description": "Diffusion dans la boîte de réception in-app",
+                    "type": "boolean",
+                    "example": true
+                },
+                "new_baskets": {
+                    "description": "Alertes de nouveaux paniers des magasins favoris",
+                    "type": "boolean",
+                    "example": true
+                },
+                "push": {
+                    "description": "Diffusion par notification push",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "requests.UpdateOpeningHoursRequest": {
+            "type": "object",
+            "properties": {
+                "opening_hours": {
+                    "description": "Planning hebdomadaire complet ; remplace le planning existant",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/requests.OpeningHourRequest"
+                    }
+                }
+            }
+        },
+        "requests.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "default_location": {
+                    "description": "Lieu de recherche par défaut, effacé par DELETE /api/me/default-location",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/requests.DefaultLocationRequest"
+                        }
+                    ]
+                },
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 80,
+                    "example": "Marie"
+                },
+                "notification_preferences": {
+                    "description": "Préférences de notification",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/requests.UpdateNotificationPreferencesRequest"
+                        }
+                    ]
+                },
+                "phone": {
+                    "description": "Format international ; vide pour l'effacer",
+                    "type": "string",
+                    "example": "+32470123456"
+                },
+                "preferred_language": {
+                    "type": "string",
+                    "enum": [
+                        "fr",
+                        "nl",
+                        "en"
+                    ],
+                    "example": "fr"
                 }
             }
         },
-        "handlers.ProcessRequestInput": {
+        "requests.UpdateStaffRoleRequest": {
             "type": "object",
             "required": [
-                "status"
+                "role"
             ],
             "properties": {
-                "status": {
-                    "description": "Status de la demande (approved/rejected)",
+                "role": {
+                    "description": "clerk ou manager",
                     "type": "string",
                     "enum": [
-                        "approved",
-                        "rejected"
+                        "clerk",
+                        "manager"
                     ],
-                    "example": "approved"
+                    "example": "manager"
                 }
             }
         },
-        "models.Basket": {
+        "requests.UpdateStoreRequest": {
             "type": "object",
-            "required": [
-                "discount_percentage",
-                "name",
-                "original_price",
-                "quantity",
-                "status_id",
-                "store_id"
-            ],
             "properties": {
-                "configuration": {
-                    "$ref": "#/definitions/models.BasketConfiguration"
-                },
-                "configuration_id": {
-                    "type": "integer"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "discount_percentage": {
-                    "type": "number"
+                "address": {
+                    "description": "Adresse complète",
+                    "type": "string",
+                    "example": "route de baduel 12"
                 },
-                "expiration_date": {
-                    "type": "string"
+                "category_id": {
+                    "description": "ID de la catégorie (obligatoire)",
+                    "type": "integer",
+                    "example": 1
                 },
-                "id": {
-                    "type": "integer"
+                "city": {
+                    "description": "Ville",
+                    "type": "string",
+                    "example": "remire"
                 },
                 "name": {
-                    "type": "string"
-                },
-                "original_price": {
-                    "type": "number"
-                },
-                "quantity": {
-                    "type": "integer"
-                },
-                "status": {
-                    "$ref": "#/definitions/models.BasketStatus"
+                    "description": "Nom du magasin (obligatoire)",
+                    "type": "string",
+                    "example": "petit bateau 2"
                 },
-                "status_id": {
-                    "type": "integer"
+                "phone_number": {
+                    "description": "Numéro de téléphone (optionnel, max 15 caractères)",
+                    "type": "string",
+                    "example": "+32470542125"
                 },
-                "store": {
-                    "$ref": "#/definitions/models.Store"
+                "postal_code": {
+                    "description": "Code postal (limité à 10 caractères pour compatibilité internationale)",
+                    "type": "string",
+                    "example": "97301"
                 },
-                "store_id": {
-                    "type": "integer"
+                "time_zone": {
+                    "description": "Fuseau horaire IANA (optionnel, inchangé s'il est absent)",
+                    "type": "string",
+                    "example": "Europe/Brussels"
+                }
+            }
+        },
+        "requests.VerifyOrderRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "K7M2Q9XA"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "qr_payload": {
+                    "type": "string",
+                    "example": "sovemanje://orders/K7M2Q9XA"
                 }
             }
         },
-        "models.BasketConfiguration": {
+        "responses.BasketByStoreResponse": {
             "type": "object",
-            "required": [
-                "discount_percentage",
-                "name",
-                "quantity",
-                "store_id"
-            ],
             "properties": {
-                "createdAt": {
+                "category": {
                     "type": "string"
                 },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
-                },
                 "description": {
                     "type": "string"
                 },
-                "discount_percentage": {
+                "discountPercentage": {
                     "type": "number"
                 },
                 "id": {
@@ -2336,229 +6657,195 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "originalPrice": {
+                    "type": "number"
                 },
-                "store": {
-                    "$ref": "#/definitions/models.Store"
+                "pickupEnd": {
+                    "type": "string"
                 },
-                "store_id": {
+                "pickupStart": {
+                    "type": "string"
+                },
+                "quantity": {
                     "type": "integer"
                 },
-                "updatedAt": {
+                "timeZone": {
                     "type": "string"
                 }
             }
         },
-        "models.BasketStatus": {
+        "responses.BasketConfigurationResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "active": {
+                    "type": "boolean"
                 },
-                "name": {
+                "description": {
                     "type": "string"
-                }
-            }
-        },
-        "models.Category": {
-            "type": "object",
-            "properties": {
+                },
+                "discountPercentage": {
+                    "type": "number"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Message d'erreur"
-                }
-            }
-        },
-        "models.Invitation": {
-            "type": "object",
-            "properties": {
-                "acceptedAt": {
-                    "type": "string"
-                },
-                "code": {
+                "lastPublishedOn": {
                     "type": "string"
                 },
-                "createdAt": {
+                "name": {
                     "type": "string"
                 },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "originalPrice": {
+                    "type": "number"
                 },
-                "email": {
+                "pickupEndTime": {
                     "type": "string"
                 },
-                "expiresAt": {
+                "pickupStartTime": {
                     "type": "string"
                 },
-                "id": {
+                "quantity": {
                     "type": "integer"
                 },
-                "sender": {
-                    "$ref": "#/definitions/models.User"
-                },
-                "senderID": {
+                "storeId": {
                     "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.InvitationStatus"
-                },
-                "store": {
-                    "$ref": "#/definitions/models.Store"
-                },
-                "storeID": {
-                    "type": "integer"
+                "weekdays": {
+                    "description": "1 = lundi ... 7 = dimanche",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "responses.BasketListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BasketResponse"
+                    }
                 },
-                "token": {
+                "nextCursor": {
+                    "description": "À renvoyer dans le paramètre cursor pour obtenir la page suivante",
                     "type": "string"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.InvitationStatus": {
-            "type": "string",
-            "enum": [
-                "PENDING",
-                "ACCEPTED",
-                "REJECTED",
-                "EXPIRED"
-            ],
-            "x-enum-varnames": [
-                "InvitationPending",
-                "InvitationAccepted",
-                "InvitationRejected",
-                "InvitationExpired"
-            ]
-        },
-        "models.Merchant": {
+        "responses.BasketResponse": {
             "type": "object",
-            "required": [
-                "business_name",
-                "email_pro",
-                "siren"
-            ],
             "properties": {
-                "business_name": {
-                    "description": "Nom de l'entreprise",
+                "address": {
                     "type": "string"
                 },
-                "createdAt": {
+                "category": {
                     "type": "string"
                 },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
-                },
-                "email_pro": {
-                    "description": "Email valide requis",
+                "description": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "phone_number": {
-                    "description": "Numéro de téléphone (optionnel, max 15 caractères)",
-                    "type": "string"
+                "discountPercentage": {
+                    "type": "number"
                 },
-                "siren": {
-                    "description": "Numéro SIREN",
-                    "type": "string"
+                "distance": {
+                    "description": "Distance en km depuis le point de recherche",
+                    "type": "number"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "user": {
-                    "description": "Relation vers User (clé étrangère avec cascade)",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    ]
+                "isFavorite": {
+                    "description": "Magasin dans les favoris de l'utilisateur",
+                    "type": "boolean"
                 },
-                "user_id": {
-                    "description": "Relation 1 à 1 vers User",
-                    "type": "integer"
-                }
-            }
-        },
-        "models.MerchantRequest": {
-            "type": "object",
-            "required": [
-                "business_name",
-                "email_pro",
-                "siren"
-            ],
-            "properties": {
-                "business_name": {
-                    "type": "string"
+                "latitude": {
+                    "type": "number"
                 },
-                "comment": {
-                    "description": "Commentaire de l'administrateur",
-                    "type": "string"
+                "longitude": {
+                    "type": "number"
                 },
-                "createdAt": {
+                "name": {
                     "type": "string"
                 },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "originalPrice": {
+                    "type": "number"
                 },
-                "email_pro": {
+                "pickupEnd": {
+                    "description": "Fin de la plage de retrait, dans le fuseau du magasin",
                     "type": "string"
                 },
-                "id": {
+                "pickupStart": {
+                    "description": "Début de la plage de retrait, dans le fuseau du magasin",
+                    "type": "string"
+                },
+                "quantity": {
                     "type": "integer"
                 },
-                "phone_number": {
-                    "type": "string"
+                "rating": {
+                    "type": "number"
                 },
-                "siren": {
+                "timeZone": {
+                    "description": "Fuseau horaire IANA du magasin",
                     "type": "string"
+                }
+            }
+        },
+        "responses.BasketStreamEvent": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Réservable : en stock, au statut Disponible et plage de retrait non terminée",
+                    "type": "boolean"
                 },
-                "status": {
-                    "description": "pending, approved, rejected",
-                    "type": "string"
+                "basketId": {
+                    "type": "integer"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "status": {
+                    "type": "string"
                 },
-                "user_id": {
-                    "description": "Relation avec l'utilisateur qui fait la demande",
+                "storeId": {
                     "type": "integer"
                 }
             }
         },
-        "models.Response": {
+        "responses.DefaultLocationResponse": {
             "type": "object",
             "properties": {
-                "data": {},
-                "message": {
-                    "type": "string",
-                    "example": "Opération réussie"
+                "label": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "radiusKm": {
+                    "type": "number"
                 }
             }
         },
-        "models.Store": {
+        "responses.FavoriteStoreResponse": {
             "type": "object",
             "properties": {
                 "address": {
                     "description": "Adresse complète",
                     "type": "string"
                 },
+                "baskets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BasketByStoreResponse"
+                    }
+                },
                 "category": {
                     "description": "Relation avec Category (clé étrangère)",
                     "allOf": [
@@ -2584,6 +6871,10 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "is_favorite": {
+                    "description": "Favori de l'utilisateur connecté (calculé, non stocké)",
+                    "type": "boolean"
+                },
                 "latitude": {
                     "description": "Latitude (format décimal)",
                     "type": "number"
@@ -2617,390 +6908,529 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "rating": {
-                    "description": "Note moyenne (sur 5)",
+                    "description": "Note moyenne (sur 5), recalculée à chaque avis",
                     "type": "number"
                 },
+                "review_count": {
+                    "description": "Nombre d'avis visibles",
+                    "type": "integer"
+                },
+                "time_zone": {
+                    "description": "Fuseau horaire IANA du magasin (horaires de retrait)",
+                    "type": "string"
+                },
                 "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "models.User": {
+        "responses.InvitationResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password_hash"
-            ],
             "properties": {
                 "createdAt": {
                     "type": "string"
                 },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
-                },
                 "email": {
-                    "description": "Validation d'email",
                     "type": "string"
                 },
-                "expiry_time": {
-                    "description": "Temps d'expiration du token de rafraîchissement",
+                "expiresAt": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "isEmailConfirmed": {
-                    "description": "L'email a-t-il été confirmé ?",
-                    "type": "boolean"
-                },
-                "is_admin": {
-                    "description": "Est-ce un administrateur ?",
-                    "type": "boolean"
-                },
-                "password_hash": {
-                    "description": "Hash du mot de passe",
-                    "type": "string"
-                },
-                "refresh_token": {
-                    "description": "Token de rafraîchissement",
+                "status": {
+                    "description": "PENDING, ACCEPTED, DECLINED, CANCELLED ou EXPIRED",
                     "type": "string"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "storeId": {
+                    "type": "integer"
                 },
-                "validationCode": {
-                    "description": "Code de validation pour l'inscription",
+                "storeName": {
                     "type": "string"
                 }
             }
         },
-        "requests.CodeValidationRequest": {
+        "responses.LoginResponse": {
             "type": "object",
             "properties": {
-                "code": {
+                "refresh_token": {
                     "type": "string"
                 },
-                "email": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "requests.CreateBasketConfigurationRequest": {
+        "responses.MarkAllReadResponse": {
             "type": "object",
-            "required": [
-                "discount_percentage",
-                "name",
-                "quantity"
-            ],
             "properties": {
-                "description": {
-                    "type": "string"
+                "updated": {
+                    "description": "Nombre de notifications marquées comme lues",
+                    "type": "integer"
+                }
+            }
+        },
+        "responses.NotificationListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.NotificationResponse"
+                    }
                 },
-                "discount_percentage": {
-                    "type": "number"
+                "page": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "pageSize": {
+                    "type": "integer"
                 },
-                "quantity": {
+                "total": {
+                    "type": "integer"
+                },
+                "unreadCount": {
+                    "description": "Nombre total de notifications non lues",
                     "type": "integer"
                 }
             }
         },
-        "requests.CreateBasketRequest": {
+        "responses.NotificationPreferencesResponse": {
             "type": "object",
-            "required": [
-                "discount_percentage",
-                "name",
-                "original_price",
-                "quantity",
-                "store_id"
-            ],
             "properties": {
-                "configuration_id": {
-                    "type": "integer",
-                    "example": 1
+                "email": {
+                    "type": "boolean"
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Ceci est un panier suprise"
+                "inApp": {
+                    "type": "boolean"
                 },
-                "discount_percentage": {
-                    "description": "20% de réduction",
-                    "type": "number",
-                    "example": 0.2
+                "newBaskets": {
+                    "type": "boolean"
                 },
-                "expiration_date": {
-                    "description": "Date d'expiration du panier, au format YYYY-MM-DD",
-                    "type": "string",
-                    "example": "2022-12-31"
+                "push": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "responses.NotificationResponse": {
+            "type": "object",
+            "properties": {
+                "basketId": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "panier surprise"
+                "body": {
+                    "type": "string"
                 },
-                "original_price": {
-                    "description": "Prix original avant réduction",
-                    "type": "number",
-                    "example": 22
+                "createdAt": {
+                    "type": "string"
                 },
-                "quantity": {
-                    "description": "Quantité disponible",
-                    "type": "integer",
-                    "example": 2
+                "id": {
+                    "type": "integer"
                 },
-                "store_id": {
-                    "type": "integer",
-                    "example": 1
+                "orderId": {
+                    "type": "integer"
+                },
+                "readAt": {
+                    "type": "string"
+                },
+                "storeId": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "requests.CreateMerchantRequest": {
+        "responses.OpeningHourResponse": {
             "type": "object",
-            "required": [
-                "business_name",
-                "email_pro",
-                "siren"
-            ],
             "properties": {
-                "business_name": {
-                    "type": "string",
-                    "example": "petit bateau"
-                },
-                "email_pro": {
-                    "type": "string",
-                    "example": "merchant@example.com"
+                "closes_at": {
+                    "type": "string"
                 },
-                "phone_number": {
-                    "type": "string",
-                    "example": "+32452101010"
+                "opens_at": {
+                    "type": "string"
                 },
-                "siren": {
-                    "type": "string",
-                    "example": "784671691"
+                "weekday": {
+                    "description": "1 = lundi ... 7 = dimanche",
+                    "type": "integer"
                 }
             }
         },
-        "requests.CreateStoreRequest": {
+        "responses.OrderResponse": {
             "type": "object",
             "properties": {
                 "address": {
-                    "description": "Adresse complète",
-                    "type": "string",
-                    "example": "route de baduel 11"
+                    "type": "string"
                 },
-                "category_id": {
-                    "description": "ID de la catégorie (obligatoire)",
-                    "type": "integer",
-                    "example": 1
+                "basketId": {
+                    "type": "integer"
                 },
-                "city": {
-                    "description": "Ville",
-                    "type": "string",
-                    "example": "cayenne"
+                "basketName": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "expiredAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pickupEnd": {
+                    "type": "string"
+                },
+                "pickupStart": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "qrPayload": {
+                    "type": "string"
+                },
+                "reservedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "storeId": {
+                    "type": "integer"
+                },
+                "storeName": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.OrderStreamEvent": {
+            "type": "object",
+            "properties": {
+                "basketId": {
+                    "type": "integer"
+                },
+                "basketName": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "customerEmail": {
+                    "type": "string"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "expiredAt": {
+                    "description": "Fin du délai de paiement d'une commande en attente",
+                    "type": "string"
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "pickupEnd": {
+                    "type": "string"
+                },
+                "pickupStart": {
+                    "type": "string"
                 },
-                "name": {
-                    "description": "Nom du magasin (obligatoire)",
-                    "type": "string",
-                    "example": "petit bateau 1"
+                "price": {
+                    "type": "number"
                 },
-                "phone_number": {
-                    "description": "Numéro de téléphone (optionnel, max 15 caractères)",
-                    "type": "string",
-                    "example": "+32470542125"
+                "reservedAt": {
+                    "type": "string"
                 },
-                "postal_code": {
-                    "description": "Code postal (limité à 10 caractères pour compatibilité internationale)",
-                    "type": "string",
-                    "example": "97300"
+                "status": {
+                    "type": "string"
+                },
+                "storeId": {
+                    "type": "integer"
                 }
             }
         },
-        "requests.LoginRequest": {
+        "responses.PendingEmailResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
                 "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "password123"
+                "expiresAt": {
+                    "type": "string"
                 }
             }
         },
-        "requests.RefreshTokenRequest": {
+        "responses.ProfileResponse": {
             "type": "object",
-            "required": [
-                "refresh_token"
-            ],
             "properties": {
-                "refresh_token": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "defaultLocation": {
+                    "$ref": "#/definitions/responses.DefaultLocationResponse"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "emailConfirmed": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isAdmin": {
+                    "type": "boolean"
+                },
+                "isMerchant": {
+                    "type": "boolean"
+                },
+                "notificationPreferences": {
+                    "$ref": "#/definitions/responses.NotificationPreferencesResponse"
+                },
+                "pendingEmail": {
+                    "description": "Changement d'email en attente de confirmation",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/responses.PendingEmailResponse"
+                        }
+                    ]
+                },
+                "phone": {
                     "type": "string"
+                },
+                "preferredLanguage": {
+                    "type": "string"
+                },
+                "staffStoreIds": {
+                    "description": "Magasins dont l'utilisateur fait partie de l'équipe",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "requests.RegisterRequest": {
+        "responses.ReviewListResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.ReviewResponse"
+                    }
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8,
-                    "example": "password123"
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "requests.ResendCodeRequest": {
+        "responses.ReviewResponse": {
             "type": "object",
-            "required": [
-                "email"
-            ],
             "properties": {
-                "email": {
+                "comment": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "repliedAt": {
+                    "type": "string"
+                },
+                "reply": {
                     "type": "string"
+                },
+                "storeId": {
+                    "type": "integer"
                 }
             }
         },
-        "requests.UpdateBasketConfigurationRequest": {
+        "responses.SessionResponse": {
             "type": "object",
-            "required": [
-                "discount_percentage",
-                "name",
-                "quantity"
-            ],
             "properties": {
-                "description": {
+                "createdAt": {
                     "type": "string"
                 },
-                "discount_percentage": {
-                    "type": "number"
+                "current": {
+                    "description": "Session du jeton d'accès de la requête",
+                    "type": "boolean"
                 },
-                "name": {
+                "expiresAt": {
                     "type": "string"
                 },
-                "quantity": {
+                "id": {
                     "type": "integer"
+                },
+                "ipAddress": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
                 }
             }
         },
-        "requests.UpdateMerchantRequest": {
+        "responses.StaffMemberResponse": {
             "type": "object",
-            "required": [
-                "business_name",
-                "email_pro",
-                "siren"
-            ],
             "properties": {
-                "business_name": {
-                    "description": "Nom de l'entreprise",
-                    "type": "string",
-                    "example": "petit bateau update"
-                },
-                "email_pro": {
-                    "description": "Email valide requis",
-                    "type": "string",
-                    "example": "merchantupdate@example.com"
+                "email": {
+                    "type": "string"
                 },
-                "phone_number": {
-                    "type": "string",
-                    "example": "+32452101010"
+                "role": {
+                    "description": "clerk ou manager",
+                    "type": "string"
                 },
-                "siren": {
-                    "description": "Numéro SIREN",
-                    "type": "string",
-                    "example": "784671695"
+                "userId": {
+                    "type": "integer"
                 }
             }
         },
-        "requests.UpdateStoreRequest": {
+        "responses.StoreClosureResponse": {
             "type": "object",
             "properties": {
-                "address": {
-                    "description": "Adresse complète",
-                    "type": "string",
-                    "example": "route de baduel 12"
-                },
-                "category_id": {
-                    "description": "ID de la catégorie (obligatoire)",
-                    "type": "integer",
-                    "example": 1
-                },
-                "city": {
-                    "description": "Ville",
-                    "type": "string",
-                    "example": "remire"
+                "end_date": {
+                    "type": "string"
                 },
-                "name": {
-                    "description": "Nom du magasin (obligatoire)",
-                    "type": "string",
-                    "example": "petit bateau 2"
+                "id": {
+                    "type": "integer"
                 },
-                "phone_number": {
-                    "description": "Numéro de téléphone (optionnel, max 15 caractères)",
-                    "type": "string",
-                    "example": "+32470542125"
+                "reason": {
+                    "type": "string"
                 },
-                "postal_code": {
-                    "description": "Code postal (limité à 10 caractères pour compatibilité internationale)",
-                    "type": "string",
-                    "example": "97301"
+                "start_date": {
+                    "type": "string"
                 }
             }
         },
-        "responses.BasketResponse": {
+        "responses.StoreDetailResponse": {
             "type": "object",
             "properties": {
                 "address": {
+                    "description": "Adresse complète",
                     "type": "string"
                 },
                 "category": {
+                    "description": "Relation avec Category (clé étrangère)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    ]
+                },
+                "category_id": {
+                    "description": "ID de la catégorie (clé étrangère)",
+                    "type": "integer"
+                },
+                "city": {
+                    "description": "Ville",
                     "type": "string"
                 },
-                "discountPercentage": {
-                    "type": "number"
+                "closes_at": {
+                    "description": "Fin de la plage d'ouverture en cours, si le magasin est ouvert",
+                    "type": "string"
                 },
-                "discountPrice": {
-                    "type": "number"
+                "closures": {
+                    "description": "Fermetures exceptionnelles à venir",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.StoreClosureResponse"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "is_favorite": {
+                    "description": "Favori de l'utilisateur connecté (calculé, non stocké)",
+                    "type": "boolean"
+                },
                 "latitude": {
+                    "description": "Latitude (format décimal)",
                     "type": "number"
                 },
                 "longitude": {
+                    "description": "Longitude (format décimal)",
                     "type": "number"
                 },
+                "merchant": {
+                    "description": "Relation avec Merchant (clé étrangère)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Merchant"
+                        }
+                    ]
+                },
+                "merchant_id": {
+                    "description": "ID du commerçant (clé étrangère)",
+                    "type": "integer"
+                },
                 "name": {
+                    "description": "Nom du magasin (obligatoire)",
                     "type": "string"
                 },
-                "originalPrice": {
-                    "type": "number"
+                "next_opening_at": {
+                    "description": "Prochaine ouverture, si le magasin est fermé",
+                    "type": "string"
+                },
+                "open_now": {
+                    "type": "boolean"
+                },
+                "opening_hours": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.OpeningHourResponse"
+                    }
+                },
+                "phone_number": {
+                    "description": "Numéro de téléphone (optionnel, max 15 caractères)",
+                    "type": "string"
+                },
+                "postal_code": {
+                    "description": "Code postal (limité à 10 caractères pour compatibilité internationale)",
+                    "type": "string"
                 },
                 "rating": {
+                    "description": "Note moyenne (sur 5), recalculée à chaque avis",
                     "type": "number"
-                }
-            }
-        },
-        "responses.LoginResponse": {
-            "type": "object",
-            "properties": {
-                "refresh_token": {
+                },
+                "review_count": {
+                    "description": "Nombre d'avis visibles",
+                    "type": "integer"
+                },
+                "time_zone": {
+                    "description": "Fuseau horaire IANA du magasin (horaires de retrait)",
                     "type": "string"
                 },
-                "token": {
+                "updatedAt": {
                     "type": "string"
                 }
             }
@@ -0,0 +1,165 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event est un message diffusé par un Broker
+type Event struct {
+	ID   uint64      // Identifiant croissant, unique pour un Broker, y compris d'un démarrage du processus à l'autre
+	Type string      // Nom de l'événement (ex. "basket.reserved")
+	Data interface{} // Contenu, sérialisé en JSON pour les clients
+	At   time.Time
+}
+
+// Broker diffuse des événements en mémoire aux abonnés du processus.
+// Chaque abonné dispose de son propre tampon : un abonné trop lent est déconnecté
+// plutôt que de bloquer la publication ou les autres abonnés.
+// Les historySize derniers événements sont conservés pour qu'un client reconnecté
+// puisse recevoir ceux qu'il a manqués (SubscribeAfter).
+type Broker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	bufferSize  int
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// Subscription est l'abonnement d'un client à un Broker
+type Subscription struct {
+	events  chan Event
+	match   func(Event) bool
+	broker  *Broker
+	startID uint64
+	once    sync.Once
+}
+
+// NewBroker crée un Broker dont les IDs d'événements partent de l'heure de démarrage (en millisecondes,
+// décalée de 16 bits) : ils restent supérieurs à ceux du processus précédent, si bien qu'un client
+// qui se reconnecte avec un ID d'avant le redémarrage est détecté comme ayant perdu des événements.
+func NewBroker(bufferSize, historySize int) *Broker {
+	return &Broker{
+		lastID:      uint64(time.Now().UnixMilli()) << 16,
+		bufferSize:  bufferSize,
+		historySize: historySize,
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Publish diffuse l'événement aux abonnés dont le filtre l'accepte et le retourne avec son ID
+func (b *Broker) Publish(eventType string, data interface{}) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := Event{ID: b.lastID, Type: eventType, Data: data, At: time.Now()}
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			b.history = append(b.history[:0], b.history[1:]...)
+		}
+		b.history = append(b.history, event)
+	}
+	if b.closed {
+		return event
+	}
+
+	for sub := range b.subscribers {
+		if sub.match != nil && !sub.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// Tampon plein : le client ne suit plus, il devra se reconnecter
+			b.remove(sub)
+		}
+	}
+	return event
+}
+
+// Subscribe abonne un client aux événements acceptés par match (tous si match est nil)
+// publiés à partir de maintenant. L'abonnement doit être libéré avec Close.
+func (b *Broker) Subscribe(match func(Event) bool) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subscribe(match, nil)
+}
+
+// SubscribeAfter abonne un client qui a déjà reçu les événements jusqu'à lastID : les événements
+// suivants encore en historique lui sont renvoyés avant les nouveaux. Retourne false si des événements
+// ont pu être perdus (historique dépassé ou broker redémarré) : le client doit alors se resynchroniser.
+func (b *Broker) SubscribeAfter(lastID uint64, match func(Event) bool) (*Subscription, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete := lastID <= b.lastID
+	if complete && lastID < b.lastID {
+		complete = len(b.history) > 0 && b.history[0].ID <= lastID+1
+	}
+
+	var missed []Event
+	if complete {
+		for _, event := range b.history {
+			if event.ID > lastID && (match == nil || match(event)) {
+				missed = append(missed, event)
+			}
+		}
+	}
+	return b.subscribe(match, missed), complete
+}
+
+// subscribe enregistre un abonné et lui transmet d'abord les événements replay ; b.mu doit être verrouillé
+func (b *Broker) subscribe(match func(Event) bool, replay []Event) *Subscription {
+	sub := &Subscription{
+		events:  make(chan Event, b.bufferSize+len(replay)),
+		match:   match,
+		broker:  b,
+		startID: b.lastID,
+	}
+	for _, event := range replay {
+		sub.events <- event
+	}
+
+	if b.closed {
+		b.remove(sub)
+		return sub
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Close ferme le Broker et tous les abonnements en cours, par exemple à l'arrêt du serveur
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// remove retire l'abonné et ferme son canal ; b.mu doit être verrouillé
+func (b *Broker) remove(sub *Subscription) {
+	delete(b.subscribers, sub)
+	sub.once.Do(func() { close(sub.events) })
+}
+
+// Events retourne le canal des événements de l'abonnement ; il est fermé quand l'abonnement prend fin
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// StartID retourne l'ID du dernier événement publié avant l'abonnement
+func (s *Subscription) StartID() uint64 {
+	return s.startID
+}
+
+// Close met fin à l'abonnement ; peut être appelé plusieurs fois
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
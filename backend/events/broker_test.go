@@ -0,0 +1,31 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeAfterRestart(t *testing.T) {
+	before := NewBroker(8, 8)
+	lastID := before.Publish("order.created", nil).ID
+
+	// Le processus redémarre : le nouveau broker n'a pas les événements de l'ancien
+	time.Sleep(time.Millisecond)
+	after := NewBroker(8, 8)
+	first := after.Publish("order.created", nil)
+	if first.ID <= lastID {
+		t.Fatalf("event ID %d after restart, want above %d", first.ID, lastID)
+	}
+
+	sub, complete := after.SubscribeAfter(lastID, nil)
+	defer sub.Close()
+	if complete {
+		t.Error("SubscribeAfter with an ID from before the restart reported no missed events")
+	}
+
+	sub, complete = after.SubscribeAfter(first.ID, nil)
+	defer sub.Close()
+	if !complete {
+		t.Error("SubscribeAfter with the last event ID reported missed events")
+	}
+}
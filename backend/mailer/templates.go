@@ -0,0 +1,137 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Modèles d'email disponibles. Chaque modèle a une version texte templates/<nom>.txt, qui définit
+// aussi l'objet (bloc "subject"), et une version HTML templates/<nom>.html insérée dans templates/layout.html.
+const (
+	TemplateValidationCode    = "validation_code"    // Code de validation du compte, données ValidationCodeData
+	TemplatePasswordReset     = "password_reset"     // Lien de réinitialisation du mot de passe, données PasswordResetData
+	TemplateEmailChange       = "email_change"       // Code de vérification de la nouvelle adresse, données ValidationCodeData
+	TemplateEmailChanged      = "email_changed"      // Avis envoyé à l'ancienne adresse, données EmailChangedData
+	TemplateInvitation        = "invitation"         // Invitation à rejoindre l'équipe d'un magasin, données InvitationData
+	TemplateOrderConfirmation = "order_confirmation" // Réservation d'un panier, données OrderData
+	TemplateOrderReady        = "order_ready"        // Commande payée, prête à être retirée, données OrderData
+	TemplateMerchantApproval  = "merchant_approval"  // Demande de marchand traitée, données MerchantApprovalData
+	TemplateNotification      = "notification"       // Notification générique, données NotificationData
+)
+
+var templateNames = []string{
+	TemplateValidationCode,
+	TemplatePasswordReset,
+	TemplateEmailChange,
+	TemplateEmailChanged,
+	TemplateInvitation,
+	TemplateOrderConfirmation,
+	TemplateOrderReady,
+	TemplateMerchantApproval,
+	TemplateNotification,
+}
+
+//go:embed templates
+var templateFS embed.FS
+
+// ValidationCodeData alimente les modèles TemplateValidationCode et TemplateEmailChange
+type ValidationCodeData struct {
+	Code     string
+	ValidFor string // Durée de validité lisible, par exemple "10 minutes"
+}
+
+// PasswordResetData alimente le modèle TemplatePasswordReset
+type PasswordResetData struct {
+	URL      string // Lien de réinitialisation, à usage unique
+	ValidFor string // Durée de validité lisible, par exemple "1 heure"
+}
+
+// EmailChangedData alimente le modèle TemplateEmailChanged
+type EmailChangedData struct {
+	NewEmail string
+}
+
+// InvitationData alimente le modèle TemplateInvitation
+type InvitationData struct {
+	StoreName string
+	URL       string // Lien signé d'acceptation
+	Code      string // Code à saisir dans l'application, à défaut du lien
+	ExpiresOn string // Date d'expiration lisible
+}
+
+// OrderData alimente les modèles TemplateOrderConfirmation et TemplateOrderReady
+type OrderData struct {
+	Code         string
+	BasketName   string
+	StoreName    string
+	StoreAddress string
+	Price        string // Montant formaté, par exemple "4.50 €"
+	ExpiresAt    string // Heure limite de paiement de la réservation, vide si sans objet
+	Pickup       string // Plage de retrait lisible, vide si inconnue
+}
+
+// MerchantApprovalData alimente le modèle TemplateMerchantApproval
+type MerchantApprovalData struct {
+	BusinessName string
+	Approved     bool
+}
+
+// NotificationData alimente le modèle TemplateNotification
+type NotificationData struct {
+	Title string
+	Body  string
+}
+
+// Renderer produit l'objet et les corps HTML et texte des emails à partir des modèles embarqués
+type Renderer struct {
+	html map[string]*htmltemplate.Template
+	text map[string]*texttemplate.Template
+}
+
+// NewRenderer analyse les modèles embarqués ; une erreur dans un modèle est une erreur de programmation
+// et provoque un panic au démarrage plutôt qu'au premier envoi
+func NewRenderer() *Renderer {
+	r := &Renderer{
+		html: make(map[string]*htmltemplate.Template, len(templateNames)),
+		text: make(map[string]*texttemplate.Template, len(templateNames)),
+	}
+	for _, name := range templateNames {
+		r.html[name] = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html"))
+		r.text[name] = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/"+name+".txt"))
+	}
+	return r
+}
+
+// Render rend le modèle name pour le destinataire to ; l'expéditeur est renseigné à l'envoi
+func (r *Renderer) Render(name, to string, data interface{}) (*Message, error) {
+	html, ok := r.html[name]
+	if !ok {
+		return nil, fmt.Errorf("mailer: unknown template %q", name)
+	}
+	text := r.text[name]
+
+	var subject, textBody, htmlBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := text.ExecuteTemplate(&textBody, name+".txt", data); err != nil {
+		return nil, err
+	}
+	if err := html.ExecuteTemplate(&htmlBody, "layout", struct {
+		Subject string
+		Data    interface{}
+	}{strings.TrimSpace(subject.String()), data}); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    htmlBody.String(),
+		Text:    strings.TrimSpace(textBody.String()) + "\n",
+	}, nil
+}
@@ -0,0 +1,180 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport remet un message à son destinataire
+type Transport interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// smtpTimeout borne la durée d'un envoi SMTP quand le contexte n'a pas d'échéance
+const smtpTimeout = 30 * time.Second
+
+// SMTPTransport envoie les messages par un serveur SMTP, avec STARTTLS si le serveur le propose
+type SMTPTransport struct {
+	host     string
+	port     string
+	username string
+	password string
+}
+
+// NewSMTPTransport construit un transport SMTP ; l'authentification n'est utilisée que si username est renseigné
+func NewSMTPTransport(host, port, username, password string) *SMTPTransport {
+	return &SMTPTransport{host: host, port: port, username: username, password: password}
+}
+
+// Send remet le message au serveur SMTP. L'échange entier respecte l'échéance du contexte,
+// ou smtpTimeout s'il n'en a pas, et est interrompu si le contexte est annulé.
+func (t *SMTPTransport) Send(ctx context.Context, message *Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, smtpTimeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(t.host, t.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := t.exchange(conn, message, data); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// exchange déroule la session SMTP sur la connexion ouverte, comme smtp.SendMail
+func (t *SMTPTransport) exchange(conn net.Conn, message *Message, data []byte) error {
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: t.host}); err != nil {
+			return err
+		}
+	}
+	if t.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", t.username, t.password, t.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(message.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// FileTransport écrit chaque message dans un fichier .eml du répertoire dir au lieu de l'envoyer ;
+// utile en développement pour lire les emails avec un client de messagerie
+type FileTransport struct {
+	dir string
+}
+
+func NewFileTransport(dir string) *FileTransport {
+	return &FileTransport{dir: dir}
+}
+
+func (t *FileTransport) Send(ctx context.Context, message *Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+
+	recipient := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, message.To)
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), recipient)
+	return os.WriteFile(filepath.Join(t.dir, name), data, 0o644)
+}
+
+// Mailbox conserve en mémoire les messages envoyés au lieu de les remettre ; utile dans les tests
+type Mailbox struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMailbox() *Mailbox {
+	return &Mailbox{}
+}
+
+func (m *Mailbox) Send(ctx context.Context, message *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, *message)
+	return nil
+}
+
+// Messages retourne les messages reçus, du plus ancien au plus récent
+func (m *Mailbox) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// To retourne les messages reçus par l'adresse donnée
+func (m *Mailbox) To(address string) []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var messages []Message
+	for _, message := range m.messages {
+		if strings.EqualFold(message.To, address) {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// Reset vide la boîte
+func (m *Mailbox) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
@@ -0,0 +1,118 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listen ouvre un serveur TCP local dont chaque connexion est confiée à handle
+func listen(t *testing.T, handle func(conn net.Conn)) (host, port string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	host, port, _ = net.SplitHostPort(ln.Addr().String())
+	return host, port
+}
+
+// serveSMTP répond aux commandes d'un client SMTP sans extension et envoie sur received les données reçues
+func serveSMTP(received chan<- string) func(conn net.Conn) {
+	return func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}
+}
+
+func TestSMTPTransportSend(t *testing.T) {
+	received := make(chan string, 1)
+	host, port := listen(t, serveSMTP(received))
+
+	message := &Message{From: "no-reply@example.com", To: "client@example.com", Subject: "Bonjour", Text: "Votre commande est disponible"}
+	if err := NewSMTPTransport(host, port, "", "").Send(context.Background(), message); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case data := <-received:
+		if !strings.Contains(data, "To: client@example.com") || !strings.Contains(data, "Votre commande est disponible") {
+			t.Errorf("server received %q", data)
+		}
+	default:
+		t.Fatal("server received no message")
+	}
+}
+
+func TestSMTPTransportSendRespectsContext(t *testing.T) {
+	// Le serveur accepte la connexion mais ne répond jamais
+	host, port := listen(t, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+	})
+	message := &Message{From: "no-reply@example.com", To: "client@example.com", Subject: "Bonjour", Text: "Bonjour"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := NewSMTPTransport(host, port, "", "").Send(ctx, message)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Send returned after %v, want it bounded by the context deadline", elapsed)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	if err := NewSMTPTransport(host, port, "", "").Send(ctx, message); !errors.Is(err, context.Canceled) {
+		t.Errorf("after cancel: err = %v, want %v", err, context.Canceled)
+	}
+}
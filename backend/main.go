@@ -1,28 +1,72 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/handlers"
 	"github.com/Sebiche09/app-anti-gaspillage.git/db"
 	_ "github.com/Sebiche09/app-anti-gaspillage.git/docs"
+	"github.com/Sebiche09/app-anti-gaspillage.git/events"
+	"github.com/Sebiche09/app-anti-gaspillage.git/ratelimit"
 	"github.com/Sebiche09/app-anti-gaspillage.git/routes"
+	"github.com/Sebiche09/app-anti-gaspillage.git/scheduler"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
 	"github.com/joho/godotenv"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// basketStreamBuffer est le nombre d'événements mis en attente pour chaque client du flux des paniers
+const basketStreamBuffer = 64
+
+// orderStreamBuffer est le nombre d'événements mis en attente pour chaque client du flux des commandes,
+// orderStreamHistory le nombre d'événements conservés pour les clients qui se reconnectent
+const (
+	orderStreamBuffer  = 64
+	orderStreamHistory = 1000
+)
+
+// mailDispatchInterval est l'intervalle entre deux envois des emails en attente dans l'outbox
+const mailDispatchInterval = 15 * time.Second
+
+// mailPurgeInterval est l'intervalle entre deux purges des emails envoyés de l'outbox
+const mailPurgeInterval = time.Hour
+
+// refundInterval est l'intervalle entre deux traitements des remboursements en attente
+const refundInterval = time.Minute
+
 func main() {
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("⚠️ Fichier .env non trouvé. Les variables d'environnement ne seront pas chargées.")
 	}
 	db := db.Init()
-	h := handlers.NewHandlers(db)
+	basketBroker := events.NewBroker(basketStreamBuffer, 0)
+	orderBroker := events.NewBroker(orderStreamBuffer, orderStreamHistory)
+	h, jobs := handlers.NewHandlers(db, basketBroker, orderBroker)
 	server := gin.Default()
 
+	// TRUSTED_PROXIES (adresses ou plages séparées par des virgules) liste les proxys dont l'en-tête
+	// X-Forwarded-For est cru pour déterminer l'IP du client, utilisée par les limites de débit.
+	// Sans elle, aucun proxy n'est cru : l'IP du client est l'adresse de la connexion.
+	var trustedProxies []string
+	if proxies := utils.GetEnvDefault("TRUSTED_PROXIES", ""); proxies != "" {
+		trustedProxies = strings.Split(proxies, ",")
+	}
+	if err := server.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES invalide : %v", err)
+	}
+
 	server.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
@@ -32,6 +76,45 @@ func main() {
 		MaxAge:           12 * time.Hour,
 	}))
 
-	routes.RegisterRoutes(server, db, h)
-	server.Run(":8080")
+	routes.RegisterRoutes(server, db, h, ratelimit.NewMemoryStore())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Tâches périodiques (expiration des réservations, paniers et invitations, publication des paniers,
+	// envoi et purge des emails, remboursements)
+	sched := scheduler.New()
+	sched.Every("expiry", time.Minute, jobs.Expiry.Sweep)
+	sched.Every("basket-publication", 5*time.Minute, jobs.Publication.PublishDue)
+	sched.Every("mail-outbox", mailDispatchInterval, jobs.Mail.Dispatch)
+	sched.Every("mail-outbox-purge", mailPurgeInterval, jobs.Mail.Purge)
+	sched.Every("payment-refunds", refundInterval, jobs.Refunds.Process)
+	// done est fermé quand tous les jobs sont sortis : l'arrêt attend qu'un envoi ou un remboursement en cours se termine
+	done := make(chan struct{})
+	go func() {
+		sched.Run(ctx)
+		close(done)
+	}()
+
+	srv := &http.Server{Addr: ":8080", Handler: server}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Erreur du serveur HTTP : %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	// Ferme les flux temps réel pour que les connexions ouvertes n'empêchent pas l'arrêt du serveur
+	basketBroker.Close()
+	orderBroker.Close()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Arrêt du serveur HTTP : %v", err)
+	}
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		log.Printf("Arrêt des tâches périodiques : %v", shutdownCtx.Err())
+	}
 }
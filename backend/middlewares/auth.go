@@ -2,10 +2,12 @@ package middlewares
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
 
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
 	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
 	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
 	"github.com/gin-gonic/gin"
@@ -24,7 +26,7 @@ func Authenticate(c *gin.Context) {
 		token = token[len(bearerPrefix):]
 	}
 
-	userId, isAdmin, isMerchant, staffStoreIDs, err := utils.VerifyToken(token)
+	userId, isAdmin, isMerchant, staffStoreIDs, sessionID, err := utils.VerifyToken(token)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 		return
@@ -33,6 +35,7 @@ func Authenticate(c *gin.Context) {
 	c.Set("isAdmin", isAdmin)
 	c.Set("isMerchant", isMerchant)
 	c.Set("staffStoreIDs", staffStoreIDs)
+	c.Set("sessionId", sessionID)
 	c.Next()
 }
 
@@ -48,7 +51,27 @@ func RequireAdmin() gin.HandlerFunc {
 	}
 }
 
-func RequireStoreStaff() gin.HandlerFunc {
+// RequireStorePermission vérifie que l'utilisateur a la permission sur le magasin identifié
+// par le paramètre de route :id. Le rôle est évalué depuis la base de données.
+func RequireStorePermission(db *gorm.DB, permission authz.Permission) gin.HandlerFunc {
+	policy := newStorePolicy(db)
+
+	return func(c *gin.Context) {
+		parsedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+			return
+		}
+
+		authorizeStore(c, policy, uint(parsedID), permission)
+	}
+}
+
+// RequireRequestStorePermission vérifie que l'utilisateur a la permission sur le magasin indiqué
+// par le paramètre de requête store_id (DELETE) ou le champ store_id du corps JSON (POST/PUT)
+func RequireRequestStorePermission(db *gorm.DB, permission authz.Permission) gin.HandlerFunc {
+	policy := newStorePolicy(db)
+
 	return func(c *gin.Context) {
 		var storeID uint
 
@@ -88,46 +111,40 @@ func RequireStoreStaff() gin.HandlerFunc {
 			return
 		}
 
-		if !IsStaffOfStore(c, storeID) {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": "You are not authorized to manage this store",
-			})
-			return
-		}
-
-		c.Set("storeId", storeID)
-		c.Next()
+		authorizeStore(c, policy, storeID, permission)
 	}
 }
 
-func IsStaffOfStore(c *gin.Context, storeID uint) bool {
-	staffStoreIDsAny, exists := c.Get("staffStoreIDs")
-	if !exists {
-		return false
-	}
-
-	staffStoreIDs, ok := staffStoreIDsAny.([]uint)
-	if !ok {
-		return false
-	}
+// authorizeStore poursuit la requête si l'utilisateur a la permission sur le magasin,
+// en exposant "storeId" et "storeRole" aux handlers
+func authorizeStore(c *gin.Context, policy *authz.Policy, storeID uint, permission authz.Permission) {
+	userID := c.MustGet("userId").(uint)
 
-	for _, id := range staffStoreIDs {
-		if id == storeID {
-			return true
+	role, err := policy.Authorize(userID, storeID, permission)
+	if err != nil {
+		switch {
+		case errors.Is(err, authz.ErrStoreNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, authz.ErrPermissionDenied):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Error checking store permissions"})
 		}
+		return
 	}
 
-	isAdmin, adminExists := c.Get("isAdmin")
-	if adminExists && isAdmin.(bool) {
-		return true
-	}
-
-	isMerchant, merchantExists := c.Get("isMerchant")
-	if merchantExists && isMerchant.(bool) {
-		return true
-	}
+	c.Set("storeId", storeID)
+	c.Set("storeRole", role)
+	c.Next()
+}
 
-	return false
+func newStorePolicy(db *gorm.DB) *authz.Policy {
+	return authz.NewPolicy(
+		repositories.NewUserRepository(db),
+		repositories.NewStoreRepository(db),
+		repositories.NewMerchantRepository(db),
+		repositories.NewStoreStaffRepository(db),
+	)
 }
 
 // RequireMerchant middleware checks if the user is a merchant.
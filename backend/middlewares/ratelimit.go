@@ -0,0 +1,96 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// maxRateLimitBodySize borne la taille du corps lu par ByEmail, avant toute authentification
+const maxRateLimitBodySize = 64 << 10
+
+// RateLimitKey extrait de la requête la clé dont les requêtes sont comptées ensemble ;
+// une clé vide n'est pas limitée
+type RateLimitKey func(c *gin.Context) string
+
+// ByIP compte les requêtes par adresse IP du client
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser compte les requêtes par utilisateur connecté ; à placer après Authenticate
+func ByUser(c *gin.Context) string {
+	userID, ok := c.Get("userId")
+	if !ok {
+		return ""
+	}
+	return "user:" + strconv.FormatUint(uint64(userID.(uint)), 10)
+}
+
+// ByEmail compte les requêtes par adresse email visée, lue dans le champ "email" du corps JSON.
+// Le corps est restauré pour le handler ; au-delà de maxRateLimitBodySize, la requête est refusée avec 413.
+func ByEmail(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxRateLimitBodySize))
+	c.Request.Body.Close()
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Requête trop volumineuse"})
+		}
+		return ""
+	}
+
+	var request struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(body, &request) != nil {
+		return ""
+	}
+	email := strings.ToLower(strings.TrimSpace(request.Email))
+	if email == "" {
+		return ""
+	}
+	return "email:" + email
+}
+
+// RateLimit limite les requêtes de la route name à limit par clé. Au-delà, la requête est refusée
+// avec 429 et un en-tête Retry-After. Si le Store est indisponible, la requête passe.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit, key RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k := key(c)
+		if c.IsAborted() {
+			return
+		}
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		allowed, retryAfter, err := store.Take(c.Request.Context(), name+":"+k, limit, time.Now())
+		if err != nil {
+			log.Printf("ratelimit: %s: %v", name, err)
+			c.Next()
+			return
+		}
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Trop de requêtes, réessayez plus tard"})
+			return
+		}
+		c.Next()
+	}
+}
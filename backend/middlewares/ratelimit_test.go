@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimitByEmail(t *testing.T) {
+	r := newTestRouter()
+	r.POST("/login", RateLimit(ratelimit.NewMemoryStore(), "login", ratelimit.PerHour(2), ByEmail), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "%s", body)
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"première tentative", `{"email":"a@example.com"}`, http.StatusOK},
+		{"même adresse, autre casse", `{"email":" A@Example.com "}`, http.StatusOK},
+		{"limite atteinte", `{"email":"a@example.com"}`, http.StatusTooManyRequests},
+		{"autre adresse", `{"email":"b@example.com"}`, http.StatusOK},
+		{"sans email, non limité", `{}`, http.StatusOK},
+		{"corps trop volumineux", `{"email":"c@example.com","padding":"` + strings.Repeat("x", maxRateLimitBodySize) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		w := post(tt.body)
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.want)
+			continue
+		}
+		// Le handler reçoit le corps complet, lu une première fois pour la clé
+		if w.Code == http.StatusOK && w.Body.String() != tt.body {
+			t.Errorf("%s: handler read %q, want %q", tt.name, w.Body.String(), tt.body)
+		}
+	}
+}
@@ -1,22 +1,52 @@
 package models
 
 import (
+	"sync"
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// DefaultTimeZone est le fuseau horaire des magasins qui n'en précisent pas
+const DefaultTimeZone = "Europe/Brussels"
+
 type Store struct {
 	gorm.Model
-	MerchantID  uint    `json:"merchant_id" gorm:"not null;index"`            // ID du commerçant (clé étrangère)
-	Name        string  `json:"name" gorm:"type:varchar(255);not null"`       // Nom du magasin (obligatoire)
-	Latitude    float64 `json:"latitude" gorm:"type:decimal(10,8);not null"`  // Latitude (format décimal)
-	Longitude   float64 `json:"longitude" gorm:"type:decimal(11,8);not null"` // Longitude (format décimal)
-	Address     string  `json:"address" gorm:"type:text;not null"`            // Adresse complète
-	City        string  `json:"city" gorm:"type:varchar(100);not null"`       // Ville
-	PostalCode  string  `json:"postal_code" gorm:"type:varchar(10);not null"` // Code postal (limité à 10 caractères pour compatibilité internationale)
-	PhoneNumber string  `json:"phone_number" gorm:"type:varchar(15)"`         // Numéro de téléphone (optionnel, max 15 caractères)
-	Rating      float64 `json:"rating" gorm:"default:0.00"`                   // Note moyenne (sur 5)
-	CategoryID  uint    `json:"category_id" gorm:"not null;index"`            // ID de la catégorie (clé étrangère)
+	MerchantID  uint    `json:"merchant_id" gorm:"not null;index"`                                                 // ID du commerçant (clé étrangère)
+	Name        string  `json:"name" gorm:"type:varchar(255);not null"`                                            // Nom du magasin (obligatoire)
+	Latitude    float64 `json:"latitude" gorm:"type:decimal(10,8);not null;index:idx_stores_location,priority:1"`  // Latitude (format décimal)
+	Longitude   float64 `json:"longitude" gorm:"type:decimal(11,8);not null;index:idx_stores_location,priority:2"` // Longitude (format décimal)
+	Address     string  `json:"address" gorm:"type:text;not null"`                                                 // Adresse complète
+	City        string  `json:"city" gorm:"type:varchar(100);not null"`                                            // Ville
+	PostalCode  string  `json:"postal_code" gorm:"type:varchar(10);not null"`                                      // Code postal (limité à 10 caractères pour compatibilité internationale)
+	PhoneNumber string  `json:"phone_number" gorm:"type:varchar(15)"`                                              // Numéro de téléphone (optionnel, max 15 caractères)
+	Rating      float64 `json:"rating" gorm:"default:0.00"`                                                        // Note moyenne (sur 5), recalculée à chaque avis
+	ReviewCount int     `json:"review_count" gorm:"not null;default:0"`                                            // Nombre d'avis visibles
+	CategoryID  uint    `json:"category_id" gorm:"not null;index"`                                                 // ID de la catégorie (clé étrangère)
+	TimeZone    string  `json:"time_zone" gorm:"type:varchar(64);not null;default:'Europe/Brussels'"`              // Fuseau horaire IANA du magasin (horaires de retrait)
+	IsFavorite  bool    `json:"is_favorite" gorm:"-"`                                                              // Favori de l'utilisateur connecté (calculé, non stocké)
 
 	Merchant Merchant `json:"merchant" gorm:"foreignKey:MerchantID;constraint:OnDelete:CASCADE"` // Relation avec Merchant (clé étrangère)
 	Category Category `json:"category" gorm:"foreignKey:CategoryID"`                             // Relation avec Category (clé étrangère)
 }
+
+// locations garde les fuseaux déjà chargés par Location, par nom : time.LoadLocation relit
+// la base des fuseaux à chaque appel
+var locations sync.Map
+
+// Location retourne le fuseau horaire du magasin, ou celui par défaut s'il est inconnu
+func (s *Store) Location() *time.Location {
+	name := s.TimeZone
+	if name == "" {
+		name = DefaultTimeZone
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc = time.UTC
+	}
+	locations.Store(name, loc)
+	return loc
+}
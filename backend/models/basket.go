@@ -1,34 +1,94 @@
 package models
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
 )
 
 type Basket struct {
 	gorm.Model
 	ConfigurationID    *int                `json:"configuration_id" gorm:"default:null"`
-	StoreID            int                 `json:"store_id" binding:"required" gorm:"not null"`
+	StoreID            int                 `json:"store_id" binding:"required" gorm:"not null;index"`
 	Name               string              `json:"name" binding:"required" gorm:"unique;not null"`
 	Description        string              `json:"description" gorm:"type:text"`
 	DiscountPercentage float64             `json:"discount_percentage" binding:"required" gorm:"not null;default:0"`
 	OriginalPrice      float64             `json:"original_price" binding:"required" gorm:"not null"`
 	Quantity           int                 `json:"quantity" binding:"required" gorm:"default:0"`
 	ExpirationDate     *string             `json:"expiration_date" gorm:"type:date"`
+	PickupStart        *time.Time          `json:"pickup_start" gorm:"type:timestamptz;index"` // Début de la plage de retrait
+	PickupEnd          *time.Time          `json:"pickup_end" gorm:"type:timestamptz;index"`   // Fin de la plage de retrait
 	StatusID           int                 `json:"status_id" binding:"required" gorm:"not null;default:1"`
 	Status             BasketStatus        `json:"status" gorm:"foreignKey:StatusID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	Configuration      BasketConfiguration `json:"configuration" gorm:"foreignKey:ConfigurationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	Store              Store               `json:"store" gorm:"foreignKey:StoreID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 }
+
+// FinalPrice retourne le prix du panier après application de la réduction
+func (b *Basket) FinalPrice() float64 {
+	return b.OriginalPrice * (1 - b.DiscountPercentage)
+}
+
 type BasketStatus struct {
 	ID   int    `json:"id" gorm:"primaryKey"`
 	Name string `json:"name" gorm:"unique;not null"`
 }
+
+const (
+	BasketStatusAvailable = "Disponible"
+	BasketStatusReserved  = "Réservé"
+	BasketStatusSold      = "Vendu"
+	BasketStatusCancelled = "Annulé"
+)
+
 type BasketConfiguration struct {
 	gorm.Model
-	Name               string  `json:"name" binding:"required" gorm:"unique;not null"`
+	Name               string  `json:"name" binding:"required" gorm:"not null"`
 	Description        string  `json:"description" gorm:"type:text"`
 	DiscountPercentage float64 `json:"discount_percentage" binding:"required" gorm:"not null;default:0"`
+	OriginalPrice      float64 `json:"original_price" gorm:"not null;default:0"` // Prix original des paniers publiés
 	Quantity           int     `json:"quantity" binding:"required" gorm:"default:0"`
-	StoreID            uint    `json:"store_id" binding:"required" gorm:"not null"`
+	PickupStartTime    string  `json:"pickup_start_time" gorm:"type:varchar(5)"` // Heure de début de retrait (HH:MM, fuseau du magasin)
+	PickupEndTime      string  `json:"pickup_end_time" gorm:"type:varchar(5)"`   // Heure de fin de retrait (HH:MM, fuseau du magasin)
+	Weekdays           string  `json:"weekdays" gorm:"type:varchar(13)"`         // Jours de publication (1 = lundi ... 7 = dimanche), séparés par des virgules
+	Active             bool    `json:"active" gorm:"not null;default:true"`      // La publication automatique est activée
+	LastPublishedOn    *string `json:"last_published_on" gorm:"type:date"`       // Dernier jour (YYYY-MM-DD) pour lequel un panier a été publié
+	StoreID            uint    `json:"store_id" binding:"required" gorm:"not null;index"`
 	Store              Store   `json:"store" gorm:"foreignKey:StoreID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 }
+
+// WeekdayList retourne les jours de publication (1 = lundi ... 7 = dimanche)
+func (c *BasketConfiguration) WeekdayList() []int {
+	days := []int{}
+	for _, part := range strings.Split(c.Weekdays, ",") {
+		if day, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// PublishesOn indique si un panier doit être publié ce jour de la semaine
+func (c *BasketConfiguration) PublishesOn(day time.Weekday) bool {
+	iso := int(day)
+	if day == time.Sunday {
+		iso = 7
+	}
+	for _, d := range c.WeekdayList() {
+		if d == iso {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatWeekdays convertit une liste de jours (1 = lundi ... 7 = dimanche) au format stocké
+func FormatWeekdays(days []int) string {
+	parts := make([]string, len(days))
+	for i, day := range days {
+		parts[i] = strconv.Itoa(day)
+	}
+	return strings.Join(parts, ",")
+}
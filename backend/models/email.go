@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Statuts d'un email de l'outbox
+const (
+	EmailPending = "pending" // En attente d'envoi ou d'une nouvelle tentative
+	EmailSent    = "sent"    // Remis au serveur d'envoi
+	EmailFailed  = "failed"  // Abandonné après le nombre maximal de tentatives
+)
+
+// OutboxEmail est un email transactionnel rendu et mis en file d'attente, puis envoyé en arrière-plan avec
+// de nouvelles tentatives. Les emails sans lesquels l'opération n'a pas de sens (code de validation du compte,
+// changement d'email, invitation) sont enregistrés dans la même transaction qu'elle. Les autres (commande prête,
+// décision sur une demande de marchand, réinitialisation du mot de passe) sont mis en file une fois l'opération validée.
+// Les corps rendus, qui peuvent contenir un lien de réinitialisation ou un code, ne sont conservés que jusqu'à l'envoi.
+type OutboxEmail struct {
+	gorm.Model
+	Recipient     string     `gorm:"size:255;not null"`                                                         // Adresse du destinataire
+	Template      string     `gorm:"size:50;not null"`                                                          // Modèle ayant servi au rendu, un des mailer.Template*
+	Subject       string     `gorm:"size:255;not null"`                                                         // Objet rendu
+	HTMLBody      string     `gorm:"type:text"`                                                                 // Corps HTML rendu, effacé une fois l'email envoyé ou abandonné
+	TextBody      string     `gorm:"type:text"`                                                                 // Corps texte rendu, effacé une fois l'email envoyé ou abandonné
+	Status        string     `gorm:"size:20;not null;default:'pending';index:idx_outbox_emails_due,priority:1"` // Un des Email*
+	Attempts      int        `gorm:"not null;default:0"`                                                        // Nombre de tentatives d'envoi
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_emails_due,priority:2"`                           // Date à partir de laquelle l'email peut être (re)tenté
+	LastError     string     `gorm:"type:text"`                                                                 // Erreur de la dernière tentative
+	SentAt        *time.Time // Date d'envoi
+}
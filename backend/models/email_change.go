@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmailChangeRequest est une demande de changement d'adresse email, appliquée quand l'utilisateur
+// saisit le code envoyé à la nouvelle adresse. Seule l'empreinte SHA-256 du code est conservée.
+type EmailChangeRequest struct {
+	gorm.Model
+	UserID      uint       `gorm:"not null;index"`     // ID de l'utilisateur
+	NewEmail    string     `gorm:"not null"`           // Adresse à vérifier
+	CodeHash    string     `gorm:"size:64;not null"`   // Empreinte SHA-256 du code
+	ExpiresAt   time.Time  `gorm:"not null"`           // Date d'expiration du code
+	Attempts    int        `gorm:"not null;default:0"` // Saisies erronées du code
+	ConfirmedAt *time.Time // Date de confirmation, nil tant que la demande est en attente
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Relation avec User (clé étrangère)
+}
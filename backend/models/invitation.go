@@ -27,4 +27,6 @@ const (
 	InvitationAccepted InvitationStatus = "ACCEPTED"
 	InvitationRejected InvitationStatus = "REJECTED"
 	InvitationExpired  InvitationStatus = "EXPIRED"
+	InvitationDeclined InvitationStatus = "DECLINED"  // Refusée par l'invité
+	InvitationCanceled InvitationStatus = "CANCELLED" // Annulée par le magasin
 )
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Types de notification
+const (
+	NotificationTypeNewBasket       = "new_basket"       // Nouveau panier publié par un magasin favori
+	NotificationTypeMerchantRequest = "merchant_request" // Demande de marchand traitée
+	NotificationTypeInvitation      = "invitation"       // Invitation à rejoindre l'équipe d'un magasin
+	NotificationTypeOrderReady      = "order_ready"      // Commande payée, prête à être retirée
+	NotificationTypeOrderCancelled  = "order_cancelled"  // Commande annulée
+)
+
+// Canaux de diffusion des notifications
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelPush  = "push"
+	NotificationChannelInApp = "in_app"
+)
+
+// Notification est un message laissé dans la boîte de réception in-app d'un utilisateur
+type Notification struct {
+	gorm.Model
+	UserID   uint       `json:"user_id" gorm:"not null;index"`  // ID du destinataire
+	Type     string     `json:"type" gorm:"size:50;not null"`   // Un des NotificationType*
+	Title    string     `json:"title" gorm:"size:255;not null"` // Titre court
+	Body     string     `json:"body" gorm:"type:text"`          // Texte du message
+	StoreID  *uint      `json:"store_id"`                       // Magasin concerné, le cas échéant
+	BasketID *uint      `json:"basket_id"`                      // Panier concerné, le cas échéant
+	OrderID  *uint      `json:"order_id"`                       // Commande concernée, le cas échéant
+	ReadAt   *time.Time `json:"read_at" gorm:"index"`           // Date de lecture, nil si non lue
+
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Relation avec User (clé étrangère)
+}
+
+// NotificationPreference regroupe les choix de notification d'un utilisateur.
+// Sans ligne en base, DefaultNotificationPreference s'applique.
+type NotificationPreference struct {
+	UserID            uint       `json:"user_id" gorm:"primaryKey;autoIncrement:false"` // ID de l'utilisateur
+	NewBaskets        bool       `json:"new_baskets" gorm:"not null"`                   // Alertes de nouveaux paniers des magasins favoris
+	Email             bool       `json:"email" gorm:"not null"`                         // Diffusion par email
+	Push              bool       `json:"push" gorm:"not null"`                          // Diffusion par notification push
+	InApp             bool       `json:"in_app" gorm:"not null"`                        // Diffusion dans la boîte de réception in-app
+	LastBasketAlertAt *time.Time `json:"-"`                                             // Dernière alerte de nouveau panier (limitation de fréquence)
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Relation avec User (clé étrangère)
+}
+
+// DefaultNotificationPreference retourne les préférences d'un utilisateur qui n'a rien configuré
+func DefaultNotificationPreference(userID uint) NotificationPreference {
+	return NotificationPreference{UserID: userID, NewBaskets: true, Email: true, Push: true, InApp: true}
+}
+
+// Allows indique si le canal de diffusion est activé
+func (p *NotificationPreference) Allows(channel string) bool {
+	switch channel {
+	case NotificationChannelEmail:
+		return p.Email
+	case NotificationChannelPush:
+		return p.Push
+	case NotificationChannelInApp:
+		return p.InApp
+	}
+	return false
+}
@@ -1,19 +1,36 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
 type Order struct {
 	gorm.Model
-	BasketID              uint    `json:"basket_id" binding:"required" gorm:"not null"`             // ID du panier associé
-	UserID                uint    `json:"user_id" binding:"required" gorm:"not null"`               // ID de l'utilisateur qui a passé la commande
-	Code                  string  `json:"code" gorm:"type:varchar(20);unique;not null"`             // Code unique de la commande
-	Status                string  `json:"status" gorm:"type:varchar(20);default:'pending'"`         // Statut de la commande (pending, confirmed, delivered, cancelled)
-	StripePaymentIntentID string  `json:"stripe_payment_intent_id" gorm:"type:varchar(255);unique"` // ID de l'intention de paiement Stripe
-	ReservedAt            *string `json:"reserved_at" gorm:"type:datetime"`                         // Date et heure de la réservation (optionnel)
-	ExpiredAt             *string `json:"expired_at" gorm:"type:datetime"`                          // Date et heure d'expiration de la commande (optionnel)
+	BasketID              uint       `json:"basket_id" binding:"required" gorm:"not null;index"`       // ID du panier associé
+	StoreID               uint       `json:"store_id" gorm:"not null;index"`                           // ID du magasin (dénormalisé depuis le panier)
+	UserID                uint       `json:"user_id" binding:"required" gorm:"not null;index"`         // ID de l'utilisateur qui a passé la commande
+	Code                  string     `json:"code" gorm:"type:varchar(20);unique;not null"`             // Code unique de retrait de la commande
+	Status                string     `json:"status" gorm:"type:varchar(20);default:'pending';index"`   // Statut de la commande (pending, confirmed, delivered, cancelled)
+	Price                 float64    `json:"price" gorm:"not null;default:0"`                          // Prix payé (après réduction) au moment de la réservation
+	StripePaymentIntentID *string    `json:"stripe_payment_intent_id" gorm:"type:varchar(255);unique"` // ID de l'intention de paiement Stripe
+	ReservedAt            *time.Time `json:"reserved_at"`                                              // Date et heure de la réservation
+	ExpiredAt             *time.Time `json:"expired_at" gorm:"index"`                                  // Date et heure d'expiration de la réservation
+	PickupStart           *time.Time `json:"pickup_start" gorm:"type:timestamptz"`                     // Début de la plage de retrait (copiée depuis le panier)
+	PickupEnd             *time.Time `json:"pickup_end" gorm:"type:timestamptz"`                       // Fin de la plage de retrait (copiée depuis le panier)
+	DeliveredAt           *time.Time `json:"delivered_at"`                                             // Date et heure de remise du panier au client
+	DeliveredByID         *uint      `json:"delivered_by_id"`                                          // ID du membre du staff ayant remis le panier
 
 	Basket Basket `json:"basket" gorm:"foreignKey:BasketID;constraint:OnDelete:CASCADE"` // Relation avec Basket (clé étrangère)
 	User   User   `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`     // Relation avec User (clé étrangère)
+
+	DeliveredBy *User `json:"-" gorm:"foreignKey:DeliveredByID;constraint:OnDelete:SET NULL"` // Relation avec le membre du staff (clé étrangère)
 }
+
+const (
+	OrderPending   = "pending"
+	OrderConfirmed = "confirmed"
+	OrderDelivered = "delivered"
+	OrderCancelled = "cancelled"
+)
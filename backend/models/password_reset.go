@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetToken est une demande de réinitialisation du mot de passe, utilisable une seule fois.
+// Seule l'empreinte SHA-256 du jeton envoyé par email est conservée.
+type PasswordResetToken struct {
+	gorm.Model
+	UserID    uint       `gorm:"not null;index"`               // ID de l'utilisateur
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex"` // Empreinte SHA-256 du jeton
+	ExpiresAt time.Time  `gorm:"not null"`                     // Date d'expiration du jeton
+	UsedAt    *time.Time // Date d'utilisation, nil tant que le jeton n'a pas servi
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Relation avec User (clé étrangère)
+}
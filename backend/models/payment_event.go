@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// ProcessedPaymentEvent enregistre les événements de paiement déjà appliqués
+// pour que les renvois du prestataire ne soient traités qu'une seule fois
+type ProcessedPaymentEvent struct {
+	EventID     string    `json:"event_id" gorm:"type:varchar(255);primaryKey"` // ID de l'événement chez le prestataire
+	Type        string    `json:"type" gorm:"type:varchar(50);not null"`        // Type d'événement (payment_succeeded, ...)
+	ProcessedAt time.Time `json:"processed_at" gorm:"not null"`                 // Date de traitement
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Statuts d'un remboursement
+const (
+	RefundPending  = "pending"  // À effectuer ou à retenter
+	RefundRefunded = "refunded" // Effectué chez le prestataire de paiement
+)
+
+// PaymentRefund est le remboursement d'un paiement reçu pour une commande qui ne peut plus être servie.
+// Il est enregistré dans la même transaction que la décision de rembourser, puis effectué en arrière-plan
+// chez le prestataire avec de nouvelles tentatives jusqu'à ce qu'il réussisse.
+type PaymentRefund struct {
+	gorm.Model
+	OrderID         uint       `gorm:"not null;index"`                                                              // Commande concernée
+	PaymentIntentID string     `gorm:"type:varchar(255);not null;uniqueIndex"`                                      // Intention de paiement à rembourser
+	Status          string     `gorm:"size:20;not null;default:'pending';index:idx_payment_refunds_due,priority:1"` // Un des Refund*
+	Attempts        int        `gorm:"not null;default:0"`                                                          // Nombre de tentatives
+	NextAttemptAt   time.Time  `gorm:"not null;index:idx_payment_refunds_due,priority:2"`                           // Date à partir de laquelle le remboursement peut être (re)tenté
+	LastError       string     `gorm:"type:text"`                                                                   // Erreur de la dernière tentative
+	RefundedAt      *time.Time // Date du remboursement
+}
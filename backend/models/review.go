@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Review est l'avis laissé par un client sur une commande remise
+type Review struct {
+	gorm.Model
+	OrderID    uint       `json:"order_id" gorm:"not null;uniqueIndex"`       // ID de la commande notée (un avis par commande)
+	StoreID    uint       `json:"store_id" gorm:"not null;index"`             // ID du magasin noté (dénormalisé depuis la commande)
+	UserID     uint       `json:"user_id" gorm:"not null;index"`              // ID du client auteur de l'avis
+	Rating     int        `json:"rating" gorm:"not null"`                     // Note de 1 à 5
+	Comment    string     `json:"comment" gorm:"type:text"`                   // Commentaire (optionnel)
+	Reply      string     `json:"reply" gorm:"type:text"`                     // Réponse du commerçant
+	RepliedAt  *time.Time `json:"replied_at"`                                 // Date de la réponse du commerçant
+	Hidden     bool       `json:"hidden" gorm:"not null;default:false;index"` // Masqué par la modération
+	HiddenAt   *time.Time `json:"hidden_at"`                                  // Date du masquage
+	HiddenByID *uint      `json:"hidden_by_id"`                               // ID de l'administrateur ayant masqué l'avis
+
+	Order Order `json:"-" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"` // Relation avec Order (clé étrangère)
+	Store Store `json:"-" gorm:"foreignKey:StoreID;constraint:OnDelete:CASCADE"` // Relation avec Store (clé étrangère)
+	User  User  `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`  // Relation avec User (clé étrangère)
+}
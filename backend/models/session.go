@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session est une connexion d'un utilisateur sur un appareil. Chaque rafraîchissement remplace son refresh
+// token par un nouveau ; les jetons remplacés sont conservés pour détecter leur réutilisation.
+type Session struct {
+	gorm.Model
+	UserID     uint       `gorm:"not null;index"` // ID de l'utilisateur
+	UserAgent  string     `gorm:"size:512"`       // User-Agent de l'appareil lors de la dernière utilisation
+	IPAddress  string     `gorm:"size:45"`        // Adresse IP lors de la dernière utilisation
+	LastUsedAt time.Time  `gorm:"not null"`       // Date de la connexion ou du dernier rafraîchissement
+	ExpiresAt  time.Time  `gorm:"not null"`       // Date d'expiration du refresh token courant
+	RevokedAt  *time.Time `gorm:"index"`          // Date de déconnexion ou de révocation, nil si active
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Relation avec User (clé étrangère)
+}
+
+// RefreshToken est un refresh token émis pour une session. Seule son empreinte SHA-256 est conservée.
+type RefreshToken struct {
+	ID        uint       `gorm:"primarykey"`
+	SessionID uint       `gorm:"not null;index"`               // ID de la session
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex"` // Empreinte SHA-256 du jeton
+	CreatedAt time.Time  // Date d'émission
+	RotatedAt *time.Time // Date de remplacement par un nouveau jeton, nil pour le jeton courant
+
+	Session Session `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE"` // Relation avec Session (clé étrangère)
+}
@@ -3,9 +3,9 @@ package models
 import "time"
 
 type StoreFavorite struct {
-	UserID    uint      `json:"user_id" gorm:"not null;index"`                  // ID de l'utilisateur (clé étrangère)
-	StoreID   uint      `json:"store_id" gorm:"not null;index"`                 // ID du magasin (clé étrangère)
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`               // Date de création (automatique)
-	Store     Store     `gorm:"foreignKey:StoreID;constraint:OnDelete:CASCADE"` // Relation avec Store (clé étrangère)
-	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`  // Relation avec User (clé étrangère)
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_store_favorites_user_store,priority:1"`        // ID de l'utilisateur (clé étrangère)
+	StoreID   uint      `json:"store_id" gorm:"not null;index;uniqueIndex:idx_store_favorites_user_store,priority:2"` // ID du magasin (clé étrangère)
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`                                                     // Date de création (automatique)
+	Store     Store     `gorm:"foreignKey:StoreID;constraint:OnDelete:CASCADE"`                                       // Relation avec Store (clé étrangère)
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`                                        // Relation avec User (clé étrangère)
 }
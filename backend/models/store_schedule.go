@@ -0,0 +1,26 @@
+package models
+
+import "gorm.io/gorm"
+
+// StoreOpeningHour est une plage d'ouverture hebdomadaire d'un magasin.
+// Un magasin peut avoir plusieurs plages le même jour (pause de midi).
+type StoreOpeningHour struct {
+	gorm.Model
+	StoreID  uint   `json:"store_id" gorm:"not null;index"`            // ID du magasin (clé étrangère)
+	Weekday  int    `json:"weekday" gorm:"not null"`                   // Jour de la semaine (1 = lundi ... 7 = dimanche)
+	OpensAt  string `json:"opens_at" gorm:"type:varchar(5);not null"`  // Heure d'ouverture (HH:MM, fuseau du magasin)
+	ClosesAt string `json:"closes_at" gorm:"type:varchar(5);not null"` // Heure de fermeture (HH:MM, fuseau du magasin)
+
+	Store Store `json:"-" gorm:"foreignKey:StoreID;constraint:OnDelete:CASCADE"` // Relation avec Store (clé étrangère)
+}
+
+// StoreClosure est une fermeture exceptionnelle d'un magasin (jours fériés, congés...)
+type StoreClosure struct {
+	gorm.Model
+	StoreID   uint   `json:"store_id" gorm:"not null;index"`       // ID du magasin (clé étrangère)
+	StartDate string `json:"start_date" gorm:"type:date;not null"` // Premier jour de fermeture (YYYY-MM-DD)
+	EndDate   string `json:"end_date" gorm:"type:date;not null"`   // Dernier jour de fermeture, inclus (YYYY-MM-DD)
+	Reason    string `json:"reason" gorm:"type:varchar(255)"`      // Motif affiché aux clients (optionnel)
+
+	Store Store `json:"-" gorm:"foreignKey:StoreID;constraint:OnDelete:CASCADE"` // Relation avec Store (clé étrangère)
+}
@@ -1,8 +1,15 @@
 package models
 
+// Rôles d'un membre du staff dans son magasin
+const (
+	StaffRoleClerk   = "clerk"   // Employé : gère les paniers et remet les commandes
+	StaffRoleManager = "manager" // Responsable : gère aussi l'équipe, les horaires et répond aux avis
+)
+
 type StoreStaff struct {
-	StoreID uint  `json:"store_id"`
-	UserID  uint  `json:"user_id"`
-	Store   Store `gorm:"foreignKey:StoreID"`
-	User    User  `gorm:"foreignKey:UserID"`
+	StoreID uint   `json:"store_id"`
+	UserID  uint   `json:"user_id"`
+	Role    string `json:"role" gorm:"type:varchar(20);not null;default:'clerk'"` // Un des StaffRole*
+	Store   Store  `gorm:"foreignKey:StoreID"`
+	User    User   `gorm:"foreignKey:UserID"`
 }
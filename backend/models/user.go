@@ -8,11 +8,19 @@ import (
 
 type User struct {
 	gorm.Model
-	Email            string    `json:"email" binding:"required,email" gorm:"unique;not null"` // Validation d'email
-	PasswordHash     string    `json:"password_hash" binding:"required" gorm:"not null"`      // Hash du mot de passe
-	IsAdmin          bool      `json:"is_admin" gorm:"default:false"`                         // Est-ce un administrateur ?
-	RefreshToken     string    `json:"refresh_token" gorm:"size:255"`                         // Token de rafraîchissement
-	ExpiryTime       time.Time `json:"expiry_time"`                                           // Temps d'expiration du token de rafraîchissement
-	ValidationCode   string    `gorm:"size:6"`                                                // Code de validation pour l'inscription
-	IsEmailConfirmed bool      `gorm:"default:false"`                                         // L'email a-t-il été confirmé ?
+	Email                string     `json:"email" binding:"required,email" gorm:"unique;not null"` // Validation d'email
+	PasswordHash         string     `json:"password_hash" binding:"required" gorm:"not null"`      // Hash du mot de passe
+	IsAdmin              bool       `json:"is_admin" gorm:"default:false"`                         // Est-ce un administrateur ?
+	ValidationCode       string     `json:"-" gorm:"size:6"`                                       // Code de validation pour l'inscription
+	IsEmailConfirmed     bool       `gorm:"default:false"`                                         // L'email a-t-il été confirmé ?
+	ValidationCodeSentAt *time.Time `json:"-"`                                                     // Date d'envoi du code de validation, qui expire après quelques minutes
+	ValidationAttempts   int        `json:"-" gorm:"not null;default:0"`                           // Saisies erronées du code de validation courant
+
+	DisplayName          string   `json:"display_name" gorm:"size:80"`                          // Nom affiché dans l'application
+	Phone                string   `json:"phone" gorm:"size:16"`                                 // Téléphone au format international (+32470123456)
+	PreferredLanguage    string   `json:"preferred_language" gorm:"size:2;not null;default:fr"` // Langue préférée de l'application
+	DefaultLocationLabel string   `json:"default_location_label" gorm:"size:255"`               // Libellé du lieu de recherche par défaut
+	DefaultLatitude      *float64 `json:"default_latitude"`                                     // Lieu de recherche par défaut, nil si non défini
+	DefaultLongitude     *float64 `json:"default_longitude"`
+	DefaultRadiusKm      *float64 `json:"default_radius_km"` // Rayon de recherche par défaut, nil pour celui de l'API
 }
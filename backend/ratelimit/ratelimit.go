@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limit décrit un seau à jetons : Burst requêtes peuvent passer d'affilée, puis un jeton
+// est rendu toutes les Every
+type Limit struct {
+	Burst int
+	Every time.Duration
+}
+
+// PerMinute autorise n requêtes par minute, dont n d'affilée
+func PerMinute(n int) Limit {
+	return Limit{Burst: n, Every: time.Minute / time.Duration(n)}
+}
+
+// PerHour autorise n requêtes par heure, dont n d'affilée
+func PerHour(n int) Limit {
+	return Limit{Burst: n, Every: time.Hour / time.Duration(n)}
+}
+
+// Store conserve l'état des seaux. MemoryStore convient à une seule instance ; plusieurs instances
+// derrière un répartiteur de charge doivent partager un Store (Redis, base de données…).
+type Store interface {
+	// Take consomme un jeton du seau key. Si le seau est vide, la requête est refusée et
+	// retryAfter indique quand un jeton sera disponible.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// sweepInterval est l'intervalle minimal entre deux purges des seaux pleins d'un MemoryStore
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // Date à laquelle le seau sera de nouveau plein, s'il n'est plus sollicité
+}
+
+// MemoryStore conserve les seaux en mémoire, dans le processus
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	burst := float64(limit.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		s.buckets[key] = b
+	}
+
+	// Remplit le seau du temps écoulé depuis la dernière requête
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(limit.Every)
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		retryAfter := time.Duration((1 - b.tokens) * float64(limit.Every))
+		return false, retryAfter, nil
+	}
+
+	b.tokens--
+	b.full = now.Add(time.Duration((burst - b.tokens) * float64(limit.Every)))
+	return true, 0, nil
+}
+
+// sweep supprime les seaux redevenus pleins : ils se comportent comme des seaux neufs
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -2,9 +2,16 @@ package repositories
 
 import (
 	"errors"
+	"time"
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrBasketNotFound              = errors.New("basket not found")
+	ErrBasketConfigurationNotFound = errors.New("basket configuration not found")
 )
 
 type BasketRepository struct {
@@ -15,23 +22,76 @@ func NewBasketRepository(db *gorm.DB) *BasketRepository {
 	return &BasketRepository{DB: db}
 }
 
-func (r *BasketRepository) GetAll() ([]models.Basket, error) {
-	var baskets []models.Basket
-	if err := r.DB.Preload("Store").Preload("Store.Category").Find(&baskets).Error; err != nil {
+func (r *BasketRepository) GetByID(id int) (*models.Basket, error) {
+	var basket models.Basket
+	if err := r.DB.Preload("Store").Preload("Store.Category").Preload("Status").First(&basket, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBasketNotFound
+		}
 		return nil, err
 	}
-	return baskets, nil
+	return &basket, nil
 }
 
-func (r *BasketRepository) GetByID(id int) (*models.Basket, error) {
-	var basket models.Basket
-	if err := r.DB.Preload("Store").Preload("Store.Category").First(&basket, id).Error; err != nil {
+// GetConfiguration retourne la configuration de panier avec son magasin
+func (r *BasketRepository) GetConfiguration(id int) (*models.BasketConfiguration, error) {
+	var config models.BasketConfiguration
+	if err := r.DB.Preload("Store").First(&config, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("basket not found")
+			return nil, ErrBasketConfigurationNotFound
 		}
 		return nil, err
 	}
-	return &basket, nil
+	return &config, nil
+}
+
+// FindScheduledConfigurations retourne les configurations actives ayant des jours de publication,
+// avec leur magasin
+func (r *BasketRepository) FindScheduledConfigurations() ([]models.BasketConfiguration, error) {
+	var configs []models.BasketConfiguration
+	err := r.DB.Preload("Store").
+		Where("active = ? AND weekdays IS NOT NULL AND weekdays <> ''", true).
+		Find(&configs).Error
+	return configs, err
+}
+
+// PublishConfiguration crée le panier du jour day (YYYY-MM-DD) pour la configuration, au plus une fois par jour.
+// La configuration est verrouillée pour que deux publications concurrentes ne créent pas deux paniers.
+// Retourne false si le panier de ce jour a déjà été publié ou si la configuration a été désactivée.
+func (r *BasketRepository) PublishConfiguration(configID uint, day string, build func(config *models.BasketConfiguration) (*models.Basket, error)) (bool, error) {
+	published := false
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var config models.BasketConfiguration
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Store").
+			First(&config, configID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBasketConfigurationNotFound
+			}
+			return err
+		}
+		if !config.Active || (config.LastPublishedOn != nil && *config.LastPublishedOn >= day) {
+			return nil
+		}
+
+		basket, err := build(&config)
+		if err != nil {
+			return err
+		}
+		statusID, err := basketStatusID(tx, models.BasketStatusAvailable)
+		if err != nil {
+			return err
+		}
+		basket.StatusID = statusID
+		if err := tx.Omit(clause.Associations).Create(basket).Error; err != nil {
+			return err
+		}
+
+		published = true
+		return tx.Model(&config).Update("last_published_on", day).Error
+	})
+	return published, err
 }
 
 func (r *BasketRepository) Create(basket *models.Basket) error {
@@ -48,6 +108,57 @@ func (r *BasketRepository) Delete(basket *models.Basket) error {
 	return r.DB.Delete(basket).Error
 }
 
+// CancelPastExpiration passe au statut annulé les paniers disponibles ou réservés
+// dont la date d'expiration (au format YYYY-MM-DD) est antérieure à today et retourne leurs IDs
+func (r *BasketRepository) CancelPastExpiration(today string) ([]uint, error) {
+	activeStatuses := r.DB.Model(&models.BasketStatus{}).
+		Select("id").
+		Where("name IN ?", []string{models.BasketStatusAvailable, models.BasketStatusReserved})
+
+	return r.cancelWhere(func(query *gorm.DB) *gorm.DB {
+		return query.Where("expiration_date IS NOT NULL AND expiration_date < ?", today).
+			Where("status_id IN (?)", activeStatuses)
+	})
+}
+
+// CancelPastPickup passe au statut annulé les paniers disponibles dont la plage de retrait est terminée
+// et retourne leurs IDs. Les paniers réservés restent en l'état pour que leurs commandes puissent encore être clôturées.
+func (r *BasketRepository) CancelPastPickup(now time.Time) ([]uint, error) {
+	availableID, err := basketStatusID(r.DB, models.BasketStatusAvailable)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.cancelWhere(func(query *gorm.DB) *gorm.DB {
+		return query.Where("pickup_end IS NOT NULL AND pickup_end < ?", now).
+			Where("status_id = ?", availableID)
+	})
+}
+
+// cancelWhere passe au statut annulé les paniers retenus par filter, verrouillés jusqu'à la mise à jour,
+// et retourne leurs IDs pour que le changement puisse être diffusé
+func (r *BasketRepository) cancelWhere(filter func(query *gorm.DB) *gorm.DB) ([]uint, error) {
+	var ids []uint
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		cancelledID, err := basketStatusID(tx, models.BasketStatusCancelled)
+		if err != nil {
+			return err
+		}
+
+		err = filter(tx.Model(&models.Basket{})).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		return tx.Model(&models.Basket{}).Where("id IN ?", ids).Update("status_id", cancelledID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 func (r *BasketRepository) Update(basket *models.Basket, updates models.Basket) error {
 	return r.DB.Model(basket).Updates(updates).Error
 }
@@ -0,0 +1,321 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrInvalidSort          = errors.New("invalid sort")
+	ErrSortRequiresLocation = errors.New("sorting by distance requires lat and lng")
+)
+
+const (
+	defaultBasketSearchLimit = 50
+	maxBasketSearchLimit     = 100
+)
+
+// Tris possibles pour la recherche de paniers
+const (
+	BasketSortNewest   = "newest"
+	BasketSortPrice    = "price"
+	BasketSortDiscount = "discount"
+	BasketSortDistance = "distance"
+	BasketSortRating   = "rating"
+)
+
+// kmPerDegreeLatitude est la distance couverte par un degré de latitude
+const kmPerDegreeLatitude = 111.045
+
+// distanceSQL calcule en SQL la distance orthodromique (haversine), en km, entre le magasin
+// et le point de recherche. Paramètres : rayon terrestre, latitude, latitude, longitude.
+const distanceSQL = `CAST(? * 2 * ASIN(SQRT(
+	POWER(SIN(RADIANS(stores.latitude - ?) / 2), 2) +
+	COS(RADIANS(?)) * COS(RADIANS(stores.latitude)) * POWER(SIN(RADIANS(stores.longitude - ?) / 2), 2)
+)) AS DOUBLE PRECISION)`
+
+// finalPriceSQL calcule le prix après réduction
+const finalPriceSQL = `CAST(baskets.original_price * (1 - baskets.discount_percentage) AS DOUBLE PRECISION)`
+
+// BasketSearch décrit les critères de recherche des paniers
+type BasketSearch struct {
+	// Recherche géographique : les trois champs doivent être renseignés ensemble
+	Latitude  *float64
+	Longitude *float64
+	RadiusKm  float64
+
+	CategoryID    *uint
+	MinPrice      *float64 // Prix après réduction minimum
+	MaxPrice      *float64 // Prix après réduction maximum
+	MinDiscount   *float64 // Réduction minimale (0.2 pour 20 %)
+	AvailableOnly bool     // Uniquement les paniers en stock et au statut Disponible
+	PickupDate    *string  // Paniers dont la plage de retrait couvre ce jour, dans le fuseau du magasin (YYYY-MM-DD)
+
+	// Plage horaire souhaitée pour le retrait : seuls les paniers dont la plage de retrait
+	// la chevauche sont retournés
+	PickupFrom *time.Time
+	PickupTo   *time.Time
+
+	Sort   string // Un des BasketSort* ; par défaut distance si la recherche est géographique, sinon newest
+	Cursor string // Curseur opaque retourné par la page précédente
+	Limit  int
+}
+
+// HasLocation indique si la recherche est restreinte autour d'un point
+func (s BasketSearch) HasLocation() bool {
+	return s.Latitude != nil && s.Longitude != nil && s.RadiusKm > 0
+}
+
+// BasketResult est un panier trouvé par Search, avec sa distance au point de recherche (en km)
+type BasketResult struct {
+	Basket   models.Basket
+	Distance *float64
+}
+
+// BasketPage est une page de résultats de Search
+type BasketPage struct {
+	Results    []BasketResult
+	NextCursor string // Vide s'il n'y a pas de page suivante
+	Total      int64  // Nombre total de paniers correspondant aux filtres
+}
+
+// basketCursor est la position (valeur de tri, ID) du dernier panier d'une page
+type basketCursor struct {
+	Value float64 `json:"v"`
+	ID    uint    `json:"id"`
+}
+
+func encodeBasketCursor(cursor basketCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeBasketCursor(encoded string) (*basketCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor basketCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+type basketSearchRow struct {
+	ID        uint
+	SortValue float64
+	Distance  *float64
+}
+
+// basketSort décrit l'expression SQL et le sens d'un tri
+type basketSort struct {
+	expr interface{}
+	desc bool
+}
+
+// Search retourne une page de paniers correspondant aux critères, avec pagination par curseur.
+// Avec une recherche géographique, les magasins sont d'abord préfiltrés par une boîte englobante
+// (qui utilise l'index idx_stores_location) puis filtrés par distance réelle.
+func (r *BasketRepository) Search(search BasketSearch) (*BasketPage, error) {
+	query := r.DB.Model(&models.Basket{}).
+		Joins("JOIN stores ON stores.id = baskets.store_id AND stores.deleted_at IS NULL")
+
+	var distance interface{}
+	if search.HasLocation() {
+		lat, lng := *search.Latitude, *search.Longitude
+		distance = gorm.Expr(distanceSQL, utils.EarthRadiusKm, lat, lat, lng)
+
+		minLat, maxLat, minLng, maxLng := boundingBox(lat, lng, search.RadiusKm)
+		query = query.
+			Where("stores.latitude BETWEEN ? AND ?", minLat, maxLat).
+			Where("stores.longitude BETWEEN ? AND ?", minLng, maxLng).
+			Where("? <= ?", distance, search.RadiusKm)
+	}
+
+	query = applyBasketFilters(query, search)
+
+	sort, err := resolveBasketSort(search, distance)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if search.Cursor != "" {
+		cursor, err := decodeBasketCursor(search.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		op := ">"
+		if sort.desc {
+			op = "<"
+		}
+		query = query.Where(
+			fmt.Sprintf("((? %s ?) OR (? = ? AND baskets.id %s ?))", op, op),
+			sort.expr, cursor.Value, sort.expr, cursor.Value, cursor.ID,
+		)
+	}
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = defaultBasketSearchLimit
+	}
+	if limit > maxBasketSearchLimit {
+		limit = maxBasketSearchLimit
+	}
+
+	direction := "ASC"
+	if sort.desc {
+		direction = "DESC"
+	}
+
+	selectDistance := interface{}(gorm.Expr("NULL"))
+	if distance != nil {
+		selectDistance = distance
+	}
+
+	var rows []basketSearchRow
+	err = query.
+		Select("baskets.id, ? AS sort_value, ? AS distance", sort.expr, selectDistance).
+		Order("sort_value " + direction + ", baskets.id " + direction).
+		Limit(limit + 1).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	page := &BasketPage{Total: total}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeBasketCursor(basketCursor{Value: last.SortValue, ID: last.ID})
+	}
+
+	page.Results, err = r.loadResults(rows)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// applyBasketFilters ajoute à la requête les filtres de la recherche
+func applyBasketFilters(query *gorm.DB, search BasketSearch) *gorm.DB {
+	if search.CategoryID != nil {
+		query = query.Where("stores.category_id = ?", *search.CategoryID)
+	}
+	if search.MinPrice != nil {
+		query = query.Where(finalPriceSQL+" >= ?", *search.MinPrice)
+	}
+	if search.MaxPrice != nil {
+		query = query.Where(finalPriceSQL+" <= ?", *search.MaxPrice)
+	}
+	if search.MinDiscount != nil {
+		query = query.Where("baskets.discount_percentage >= ?", *search.MinDiscount)
+	}
+	if search.AvailableOnly {
+		query = query.
+			Where("baskets.quantity > 0").
+			Where("baskets.status_id IN (SELECT id FROM basket_statuses WHERE name = ?)", models.BasketStatusAvailable).
+			Where("(baskets.pickup_end IS NULL OR baskets.pickup_end > CURRENT_TIMESTAMP)")
+	}
+	if search.PickupDate != nil {
+		// Les jours de début et de fin de la plage sont pris dans le fuseau du magasin ;
+		// les paniers sans plage de retrait sont exclus
+		query = query.
+			Where("CAST(baskets.pickup_start AT TIME ZONE stores.time_zone AS DATE) <= ?", *search.PickupDate).
+			Where("CAST(baskets.pickup_end AT TIME ZONE stores.time_zone AS DATE) >= ?", *search.PickupDate)
+	}
+	if search.PickupFrom != nil {
+		query = query.Where("baskets.pickup_end >= ?", *search.PickupFrom)
+	}
+	if search.PickupTo != nil {
+		query = query.Where("baskets.pickup_start <= ?", *search.PickupTo)
+	}
+	return query
+}
+
+// resolveBasketSort retourne l'expression de tri correspondant à search.Sort
+func resolveBasketSort(search BasketSearch, distance interface{}) (basketSort, error) {
+	sort := search.Sort
+	if sort == "" {
+		sort = BasketSortNewest
+		if distance != nil {
+			sort = BasketSortDistance
+		}
+	}
+
+	switch sort {
+	case BasketSortNewest:
+		return basketSort{expr: gorm.Expr("CAST(baskets.id AS DOUBLE PRECISION)"), desc: true}, nil
+	case BasketSortPrice:
+		return basketSort{expr: gorm.Expr(finalPriceSQL)}, nil
+	case BasketSortDiscount:
+		return basketSort{expr: gorm.Expr("CAST(baskets.discount_percentage AS DOUBLE PRECISION)"), desc: true}, nil
+	case BasketSortRating:
+		return basketSort{expr: gorm.Expr("CAST(stores.rating AS DOUBLE PRECISION)"), desc: true}, nil
+	case BasketSortDistance:
+		if distance == nil {
+			return basketSort{}, ErrSortRequiresLocation
+		}
+		return basketSort{expr: distance}, nil
+	default:
+		return basketSort{}, ErrInvalidSort
+	}
+}
+
+// loadResults charge les paniers (avec magasin et catégorie) dans l'ordre des lignes trouvées
+func (r *BasketRepository) loadResults(rows []basketSearchRow) ([]BasketResult, error) {
+	if len(rows) == 0 {
+		return []BasketResult{}, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	var baskets []models.Basket
+	if err := r.DB.Preload("Store").Preload("Store.Category").Find(&baskets, ids).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Basket, len(baskets))
+	for _, basket := range baskets {
+		byID[basket.ID] = basket
+	}
+
+	results := make([]BasketResult, 0, len(rows))
+	for _, row := range rows {
+		basket, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, BasketResult{Basket: basket, Distance: row.Distance})
+	}
+	return results, nil
+}
+
+// boundingBox retourne les bornes en latitude et longitude d'un carré contenant le cercle
+// de rayon radiusKm autour du point. Les recherches traversant l'antiméridien ne sont pas gérées.
+func boundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	latDelta := radiusKm / kmPerDegreeLatitude
+
+	lngDelta := 180.0
+	if cos := math.Cos(lat * math.Pi / 180); cos > 0.01 {
+		lngDelta = math.Min(radiusKm/(kmPerDegreeLatitude*cos), 180)
+	}
+
+	return lat - latDelta, lat + latDelta, lng - lngDelta, lng + lngDelta
+}
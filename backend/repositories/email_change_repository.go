@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEmailChangeInvalid = errors.New("invalid or expired email change code")
+	ErrEmailAlreadyUsed   = errors.New("email is already used by another account")
+)
+
+type EmailChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeRepository(db *gorm.DB) *EmailChangeRepository {
+	return &EmailChangeRepository{db: db}
+}
+
+// Create enregistre une demande de changement d'email ; les demandes précédentes de l'utilisateur
+// encore en attente sont supprimées, seul le dernier code envoyé reste valable
+func (r *EmailChangeRepository) Create(request *models.EmailChangeRequest) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND confirmed_at IS NULL", request.UserID).
+			Delete(&models.EmailChangeRequest{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(request).Error
+	})
+}
+
+// FindPending retourne la demande en attente de l'utilisateur, encore valable
+func (r *EmailChangeRepository) FindPending(userID uint, now time.Time) (*models.EmailChangeRequest, error) {
+	var request models.EmailChangeRequest
+	err := r.db.Where("user_id = ? AND confirmed_at IS NULL AND expires_at > ?", userID, now).
+		Order("created_at DESC").
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Confirm applique la demande en attente de l'utilisateur si codeHash est l'empreinte de son code :
+// l'email du compte est remplacé et la demande marquée confirmée dans une même transaction.
+// La ligne de la demande est verrouillée pour sérialiser les saisies. Une saisie erronée est comptée
+// et, après maxAttempts, la demande est bloquée.
+// Retourne l'ancien email et la demande, ErrEmailChangeInvalid si aucune demande valable ne correspond
+// ou ErrEmailAlreadyUsed si la nouvelle adresse a été prise entre-temps.
+func (r *EmailChangeRepository) Confirm(userID uint, codeHash string, maxAttempts int, now time.Time) (string, *models.EmailChangeRequest, error) {
+	var request models.EmailChangeRequest
+	var oldEmail string
+	wrongCode := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Order("created_at DESC").
+			First(&request).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEmailChangeInvalid
+			}
+			return err
+		}
+		if request.Attempts >= maxAttempts || !now.Before(request.ExpiresAt) {
+			return ErrEmailChangeInvalid
+		}
+
+		if subtle.ConstantTimeCompare([]byte(request.CodeHash), []byte(codeHash)) != 1 {
+			// Le compteur est validé avec la transaction ; l'erreur est retournée ensuite
+			wrongCode = true
+			return tx.Model(&request).Update("attempts", request.Attempts+1).Error
+		}
+
+		var user models.User
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error
+		if err != nil {
+			return err
+		}
+
+		var taken int64
+		err = tx.Model(&models.User{}).
+			Where("email = ? AND id <> ?", request.NewEmail, userID).
+			Count(&taken).Error
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return ErrEmailAlreadyUsed
+		}
+
+		oldEmail = user.Email
+		err = tx.Model(&user).Updates(map[string]interface{}{
+			"email":              request.NewEmail,
+			"is_email_confirmed": true,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&request).Update("confirmed_at", now).Error
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if wrongCode {
+		return "", nil, ErrEmailChangeInvalid
+	}
+	return oldEmail, &request, nil
+}
+
+// DeletePending annule la demande en attente de l'utilisateur
+func (r *EmailChangeRepository) DeletePending(userID uint) error {
+	return r.db.Where("user_id = ? AND confirmed_at IS NULL", userID).
+		Delete(&models.EmailChangeRequest{}).Error
+}
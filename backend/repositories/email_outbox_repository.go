@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EmailOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailOutboxRepository(db *gorm.DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: db}
+}
+
+// WithTx retourne un EmailOutboxRepository travaillant dans la transaction tx
+func (r *EmailOutboxRepository) WithTx(tx *gorm.DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: tx}
+}
+
+// Enqueue met un email en file d'attente
+func (r *EmailOutboxRepository) Enqueue(email *models.OutboxEmail) error {
+	return r.db.Omit(clause.Associations).Create(email).Error
+}
+
+// ClaimDue réserve au plus limit emails en attente dont la prochaine tentative est due ; voir claimDue
+func (r *EmailOutboxRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.OutboxEmail, error) {
+	return claimDue[models.OutboxEmail](r.db, models.EmailPending, now, limit, lease)
+}
+
+// MarkSent enregistre l'envoi réussi d'un email et efface ses corps rendus, qui peuvent contenir
+// un lien de réinitialisation ou un code de vérification
+func (r *EmailOutboxRepository) MarkSent(id uint, sentAt time.Time) error {
+	return r.db.Model(&models.OutboxEmail{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.EmailSent,
+			"sent_at":    sentAt,
+			"last_error": "",
+			"html_body":  "",
+			"text_body":  "",
+		}).Error
+}
+
+// MarkRetry enregistre l'échec d'une tentative et programme la suivante à nextAttemptAt
+func (r *EmailOutboxRepository) MarkRetry(id uint, nextAttemptAt time.Time, lastError string) error {
+	return markRetry[models.OutboxEmail](r.db, id, nextAttemptAt, lastError)
+}
+
+// DeleteSentBefore supprime définitivement les emails envoyés avant before et retourne leur nombre
+func (r *EmailOutboxRepository) DeleteSentBefore(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("status = ? AND sent_at < ?", models.EmailSent, before).
+		Delete(&models.OutboxEmail{})
+	return result.RowsAffected, result.Error
+}
+
+// MarkFailed abandonne l'envoi d'un email après le nombre maximal de tentatives ; comme pour MarkSent,
+// ses corps rendus sont effacés
+func (r *EmailOutboxRepository) MarkFailed(id uint, lastError string) error {
+	return r.db.Model(&models.OutboxEmail{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.EmailFailed,
+			"last_error": lastError,
+			"html_body":  "",
+			"text_body":  "",
+		}).Error
+}
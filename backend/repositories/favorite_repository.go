@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FavoriteRepository struct {
+	db *gorm.DB
+}
+
+func NewFavoriteRepository(db *gorm.DB) *FavoriteRepository {
+	return &FavoriteRepository{db: db}
+}
+
+// Add ajoute le magasin aux favoris de l'utilisateur ; sans effet s'il y est déjà
+func (r *FavoriteRepository) Add(userID, storeID uint) error {
+	favorite := models.StoreFavorite{UserID: userID, StoreID: storeID}
+	return r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "store_id"}},
+			DoNothing: true,
+		}).
+		Create(&favorite).Error
+}
+
+// Remove retire le magasin des favoris de l'utilisateur ; sans effet s'il n'y est pas
+func (r *FavoriteRepository) Remove(userID, storeID uint) error {
+	return r.db.Where("user_id = ? AND store_id = ?", userID, storeID).Delete(&models.StoreFavorite{}).Error
+}
+
+// StoreIDs retourne les IDs des magasins favoris de l'utilisateur
+func (r *FavoriteRepository) StoreIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.StoreFavorite{}).Where("user_id = ?", userID).Pluck("store_id", &ids).Error
+	return ids, err
+}
+
+// GetStores retourne les magasins favoris de l'utilisateur, du plus récemment ajouté au plus ancien
+func (r *FavoriteRepository) GetStores(userID uint) ([]models.Store, error) {
+	var stores []models.Store
+	err := r.db.Preload("Category").
+		Joins("JOIN store_favorites ON store_favorites.store_id = stores.id").
+		Where("store_favorites.user_id = ?", userID).
+		Order("store_favorites.created_at DESC").
+		Find(&stores).Error
+	return stores, err
+}
+
+// GetAvailableBaskets retourne les paniers actuellement réservables des magasins donnés
+func (r *FavoriteRepository) GetAvailableBaskets(storeIDs []uint) ([]models.Basket, error) {
+	var baskets []models.Basket
+	if len(storeIDs) == 0 {
+		return baskets, nil
+	}
+	err := r.db.Preload("Store").Preload("Store.Category").
+		Where("store_id IN ?", storeIDs).
+		Where("quantity > 0").
+		Where("status_id IN (SELECT id FROM basket_statuses WHERE name = ?)", models.BasketStatusAvailable).
+		Where("(pickup_end IS NULL OR pickup_end > CURRENT_TIMESTAMP)").
+		Order("created_at DESC").
+		Find(&baskets).Error
+	return baskets, err
+}
+
+// GetFollowers retourne les utilisateurs ayant mis le magasin en favori
+func (r *FavoriteRepository) GetFollowers(storeID uint) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Joins("JOIN store_favorites ON store_favorites.user_id = users.id").
+		Where("store_favorites.store_id = ?", storeID).
+		Find(&users).Error
+	return users, err
+}
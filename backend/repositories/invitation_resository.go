@@ -2,8 +2,17 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrInvitationNotPending = errors.New("invitation is no longer valid")
 )
 
 type InvitationRepository struct {
@@ -14,14 +23,27 @@ func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
 	return &InvitationRepository{db: db}
 }
 
+// WithTx retourne un InvitationRepository travaillant dans la transaction tx
+func (r *InvitationRepository) WithTx(tx *gorm.DB) *InvitationRepository {
+	return &InvitationRepository{db: tx}
+}
+
+// Transaction exécute fn dans une transaction, à laquelle participent les repositories construits avec WithTx(tx)
+func (r *InvitationRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 func (r *InvitationRepository) CreateInvitation(invitation *models.Invitation) error {
-	return r.db.Create(invitation).Error
+	return r.db.Omit(clause.Associations).Create(invitation).Error
 }
 
 func (r *InvitationRepository) GetInvitationByCode(code string) (*models.Invitation, error) {
 	var invitation models.Invitation
-	err := r.db.Where("code = ?", code).First(&invitation).Error
+	err := r.db.Preload("Store").Where("code = ?", code).First(&invitation).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
 		return nil, err
 	}
 	return &invitation, nil
@@ -29,8 +51,11 @@ func (r *InvitationRepository) GetInvitationByCode(code string) (*models.Invitat
 
 func (r *InvitationRepository) GetInvitationByID(id uint) (*models.Invitation, error) {
 	var invitation models.Invitation
-	err := r.db.Where("id = ?", id).First(&invitation).Error
+	err := r.db.Preload("Store").Where("id = ?", id).First(&invitation).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
 		return nil, err
 	}
 	return &invitation, nil
@@ -38,12 +63,65 @@ func (r *InvitationRepository) GetInvitationByID(id uint) (*models.Invitation, e
 
 func (r *InvitationRepository) GetPendingInvitationsByStore(storeID uint) ([]models.Invitation, error) {
 	var invitations []models.Invitation
-	err := r.db.Where("store_id = ? AND status = ?", storeID, models.InvitationPending).Find(&invitations).Error
+	err := r.db.Preload("Store").Where("store_id = ? AND status = ?", storeID, models.InvitationPending).
+		Order("created_at DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+// GetPendingInvitationsByEmail retourne les invitations en cours de validité adressées à l'email
+func (r *InvitationRepository) GetPendingInvitationsByEmail(email string, now time.Time) ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	err := r.db.Preload("Store").
+		Where("LOWER(email) = LOWER(?) AND status = ? AND expires_at > ?", email, models.InvitationPending, now).
+		Order("created_at DESC").Find(&invitations).Error
 	return invitations, err
 }
 
+// HasPendingInvitation indique si une invitation en attente existe déjà pour cet email et ce magasin
+func (r *InvitationRepository) HasPendingInvitation(storeID uint, email string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Invitation{}).
+		Where("store_id = ? AND LOWER(email) = LOWER(?) AND status = ?", storeID, email, models.InvitationPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Accept ajoute le membre au staff et marque l'invitation acceptée dans une même transaction ;
+// l'invitation est verrouillée pour qu'elle ne puisse être acceptée qu'une fois
+func (r *InvitationRepository) Accept(invitationID uint, member *models.StoreStaff, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var invitation models.Invitation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invitation, invitationID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvitationNotFound
+			}
+			return err
+		}
+		if invitation.Status != models.InvitationPending {
+			return ErrInvitationNotPending
+		}
+
+		if err := tx.Omit(clause.Associations).Create(member).Error; err != nil {
+			return err
+		}
+		return tx.Model(&invitation).Updates(map[string]interface{}{
+			"status":      models.InvitationAccepted,
+			"accepted_at": now,
+		}).Error
+	})
+}
+
 func (r *InvitationRepository) UpdateInvitation(invitation *models.Invitation) error {
-	return r.db.Save(invitation).Error
+	return r.db.Omit(clause.Associations).Save(invitation).Error
+}
+
+// ExpirePending passe au statut expiré les invitations en attente dont la date d'expiration est dépassée
+func (r *InvitationRepository) ExpirePending(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Invitation{}).
+		Where("status = ? AND expires_at < ?", models.InvitationPending, now).
+		Update("status", models.InvitationExpired)
+	return result.RowsAffected, result.Error
 }
 
 func (r *InvitationRepository) DeleteInvitation(id uint) error {
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create enregistre une notification dans la boîte de réception de son destinataire
+func (r *NotificationRepository) Create(notification *models.Notification) error {
+	return r.db.Omit(clause.Associations).Create(notification).Error
+}
+
+// GetPreference retourne les préférences de l'utilisateur, ou les préférences par défaut s'il n'en a pas
+func (r *NotificationRepository) GetPreference(userID uint) (*models.NotificationPreference, error) {
+	preferences, err := r.GetPreferences([]uint{userID})
+	if err != nil {
+		return nil, err
+	}
+	preference := preferences[userID]
+	return &preference, nil
+}
+
+// GetPreferences retourne les préférences des utilisateurs donnés, indexées par ID.
+// Les utilisateurs sans préférences enregistrées reçoivent les préférences par défaut.
+func (r *NotificationRepository) GetPreferences(userIDs []uint) (map[uint]models.NotificationPreference, error) {
+	preferences := make(map[uint]models.NotificationPreference, len(userIDs))
+	if len(userIDs) == 0 {
+		return preferences, nil
+	}
+
+	var stored []models.NotificationPreference
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range userIDs {
+		preferences[id] = models.DefaultNotificationPreference(id)
+	}
+	for _, preference := range stored {
+		preferences[preference.UserID] = preference
+	}
+	return preferences, nil
+}
+
+// SavePreference enregistre les préférences de l'utilisateur, sans toucher à la limitation de fréquence
+func (r *NotificationRepository) SavePreference(preference *models.NotificationPreference) error {
+	return r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"new_baskets", "email", "push", "in_app", "updated_at"}),
+		}).
+		Create(preference).Error
+}
+
+// ReserveBasketAlert réserve l'envoi d'une alerte de nouveau panier à l'utilisateur si la précédente
+// date d'au moins interval. La réservation est atomique : deux publications simultanées
+// ne peuvent pas alerter deux fois le même utilisateur dans l'intervalle.
+func (r *NotificationRepository) ReserveBasketAlert(userID uint, now time.Time, interval time.Duration) (bool, error) {
+	defaults := models.DefaultNotificationPreference(userID)
+	err := r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).
+		Create(&defaults).Error
+	if err != nil {
+		return false, err
+	}
+
+	result := r.db.Model(&models.NotificationPreference{}).
+		Where("user_id = ?", userID).
+		Where("(last_basket_alert_at IS NULL OR last_basket_alert_at <= ?)", now.Add(-interval)).
+		UpdateColumn("last_basket_alert_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ListByUser retourne une page des notifications de l'utilisateur, de la plus récente à la plus ancienne,
+// ainsi que le nombre total de notifications correspondantes
+func (r *NotificationRepository) ListByUser(userID uint, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error) {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []models.Notification
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread retourne le nombre de notifications non lues de l'utilisateur
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead marque comme lue la notification de l'utilisateur ; une notification déjà lue garde sa date de lecture
+func (r *NotificationRepository) MarkRead(userID, id uint, now time.Time) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", id, userID).
+			First(&notification).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotificationNotFound
+			}
+			return err
+		}
+		if notification.ReadAt != nil {
+			return nil
+		}
+		notification.ReadAt = &now
+		return tx.Model(&notification).UpdateColumn("read_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAllRead marque comme lues toutes les notifications non lues de l'utilisateur et retourne leur nombre
+func (r *NotificationRepository) MarkAllRead(userID uint, now time.Time) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		UpdateColumn("read_at", now)
+	return result.RowsAffected, result.Error
+}
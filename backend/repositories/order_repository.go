@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrBasketUnavailable = errors.New("basket is no longer available")
+	ErrOrderNotFound     = errors.New("order not found")
+)
+
+type OrderRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// WithTx retourne un OrderRepository travaillant dans la transaction tx
+func (r *OrderRepository) WithTx(tx *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: tx}
+}
+
+// ReserveBasket décrémente la quantité du panier et crée la commande dans une même transaction.
+// La ligne du panier est verrouillée (SELECT ... FOR UPDATE) pour que deux réservations
+// concurrentes ne puissent pas vendre le dernier panier deux fois.
+// La fonction check est appelée sur le panier verrouillé avant toute modification.
+func (r *OrderRepository) ReserveBasket(order *models.Order, check func(basket *models.Basket) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var basket models.Basket
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Status").
+			First(&basket, order.BasketID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBasketNotFound
+			}
+			return err
+		}
+
+		if basket.Quantity <= 0 || basket.Status.Name != models.BasketStatusAvailable {
+			return ErrBasketUnavailable
+		}
+
+		if check != nil {
+			if err := check(&basket); err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{"quantity": basket.Quantity - 1}
+		if basket.Quantity == 1 {
+			statusID, err := basketStatusID(tx, models.BasketStatusReserved)
+			if err != nil {
+				return err
+			}
+			updates["status_id"] = statusID
+		}
+		if err := tx.Model(&basket).Omit(clause.Associations).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		order.StoreID = uint(basket.StoreID)
+		order.Price = basket.FinalPrice()
+		order.PickupStart = basket.PickupStart
+		order.PickupEnd = basket.PickupEnd
+		return tx.Omit(clause.Associations).Create(order).Error
+	})
+}
+
+// UpdateByCode verrouille la commande portant ce code, applique fn puis enregistre la commande.
+// Si fn retourne une erreur, rien n'est enregistré.
+func (r *OrderRepository) UpdateByCode(code string, fn func(order *models.Order) error) (*models.Order, error) {
+	return r.updateLocked(fn, "code = ?", code)
+}
+
+// UpdateByID verrouille la commande, applique fn puis enregistre la commande.
+// Si fn retourne une erreur, rien n'est enregistré.
+func (r *OrderRepository) UpdateByID(id uint, fn func(order *models.Order) error) (*models.Order, error) {
+	return r.updateLocked(fn, "id = ?", id)
+}
+
+func (r *OrderRepository) updateLocked(fn func(order *models.Order) error, query string, args ...interface{}) (*models.Order, error) {
+	var order models.Order
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(query, args...).
+			First(&order).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+
+		if err := fn(&order); err != nil {
+			return err
+		}
+
+		return tx.Omit(clause.Associations).Save(&order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(order.ID)
+}
+
+// CancelOrder annule la commande et remet le panier réservé en stock dans une même transaction.
+// La fonction check est appelée sur la commande verrouillée ; si elle retourne une erreur, rien n'est modifié.
+func (r *OrderRepository) CancelOrder(orderID uint, check func(order *models.Order) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+
+		if check != nil {
+			if err := check(&order); err != nil {
+				return err
+			}
+		}
+
+		order.Status = models.OrderCancelled
+		if err := tx.Omit(clause.Associations).Save(&order).Error; err != nil {
+			return err
+		}
+
+		return restockBasket(tx, order.BasketID)
+	})
+}
+
+// FindExpiredPendingIDs retourne les commandes en attente dont la réservation a expiré
+func (r *OrderRepository) FindExpiredPendingIDs(now time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Order{}).
+		Where("status = ? AND expired_at < ?", models.OrderPending, now).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindIDByPaymentIntent retourne l'ID de la commande associée à l'intention de paiement
+func (r *OrderRepository) FindIDByPaymentIntent(intentID string) (uint, error) {
+	var order models.Order
+	err := r.db.Select("id").Where("stripe_payment_intent_id = ?", intentID).First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrOrderNotFound
+		}
+		return 0, err
+	}
+	return order.ID, nil
+}
+
+func (r *OrderRepository) CodeExists(code string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Order{}).Where("code = ?", code).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *OrderRepository) GetByID(id uint) (*models.Order, error) {
+	var order models.Order
+	err := r.db.Preload("Basket").Preload("Basket.Store").First(&order, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) GetByUser(userID uint) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Preload("Basket").Preload("Basket.Store").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// GetWithCustomer retourne la commande avec son panier, son magasin et l'email du client,
+// pour le suivi des commandes par le staff ; seuls l'ID et l'email du client sont chargés
+func (r *OrderRepository) GetWithCustomer(id uint) (*models.Order, error) {
+	var order models.Order
+	err := r.withCustomer().First(&order, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindOpenByStores retourne les commandes en attente de paiement ou de retrait des magasins donnés,
+// dans l'ordre des plages de retrait
+func (r *OrderRepository) FindOpenByStores(storeIDs []uint) ([]models.Order, error) {
+	var orders []models.Order
+	if len(storeIDs) == 0 {
+		return orders, nil
+	}
+	err := r.withCustomer().
+		Where("store_id IN ? AND status IN ?", storeIDs, []string{models.OrderPending, models.OrderConfirmed}).
+		Order("pickup_start ASC NULLS LAST, reserved_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *OrderRepository) withCustomer() *gorm.DB {
+	return r.db.Preload("Basket").Preload("Basket.Store").
+		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id", "email") })
+}
+
+// restockBasket remet un exemplaire du panier en stock et le rend de nouveau disponible
+// s'il avait été entièrement réservé
+func restockBasket(tx *gorm.DB, basketID uint) error {
+	var basket models.Basket
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Status").First(&basket, basketID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	updates := map[string]interface{}{"quantity": basket.Quantity + 1}
+	if basket.Status.Name == models.BasketStatusReserved {
+		statusID, err := basketStatusID(tx, models.BasketStatusAvailable)
+		if err != nil {
+			return err
+		}
+		updates["status_id"] = statusID
+	}
+	return tx.Model(&basket).Omit(clause.Associations).Updates(updates).Error
+}
+
+// basketStatusID retourne l'ID du statut de panier portant ce nom
+func basketStatusID(db *gorm.DB, name string) (int, error) {
+	var status models.BasketStatus
+	if err := db.Where("name = ?", name).First(&status).Error; err != nil {
+		return 0, err
+	}
+	return status.ID, nil
+}
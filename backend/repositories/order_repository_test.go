@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+	"gorm.io/gorm/clause"
+)
+
+func TestReserveBasket(t *testing.T) {
+	db := testutil.OpenDB(t)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	customer := testutil.CreateUser(t, db, "customer@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+
+	availableID, err := basketStatusID(db, models.BasketStatusAvailable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	basket := &models.Basket{StoreID: int(store.ID), Name: "Panier", OriginalPrice: 10, Quantity: 2, StatusID: availableID}
+	if err := db.Omit(clause.Associations).Create(basket).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewOrderRepository(db)
+	reserve := func(code string) error {
+		order := &models.Order{BasketID: basket.ID, UserID: customer.ID, Code: code, Status: models.OrderPending}
+		return repo.ReserveBasket(order, nil)
+	}
+	reload := func() models.Basket {
+		t.Helper()
+		var reloaded models.Basket
+		if err := db.Preload("Status").First(&reloaded, basket.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return reloaded
+	}
+
+	tests := []struct {
+		code         string
+		wantErr      error
+		wantQuantity int
+		wantStatus   string
+	}{
+		{"ORDER1", nil, 1, models.BasketStatusAvailable},
+		// Le dernier panier réservé passe le panier au statut Réservé
+		{"ORDER2", nil, 0, models.BasketStatusReserved},
+		{"ORDER3", ErrBasketUnavailable, 0, models.BasketStatusReserved},
+	}
+	for _, tt := range tests {
+		if err := reserve(tt.code); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("reserve %s: err = %v, want %v", tt.code, err, tt.wantErr)
+		}
+		got := reload()
+		if got.Quantity != tt.wantQuantity || got.Status.Name != tt.wantStatus {
+			t.Errorf("after %s: quantity %d, status %q; want %d, %q", tt.code, got.Quantity, got.Status.Name, tt.wantQuantity, tt.wantStatus)
+		}
+	}
+
+	var orders int64
+	if err := db.Model(&models.Order{}).Where("basket_id = ?", basket.ID).Count(&orders).Error; err != nil {
+		t.Fatal(err)
+	}
+	if orders != 2 {
+		t.Errorf("orders = %d, want 2", orders)
+	}
+}
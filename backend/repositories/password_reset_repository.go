@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrPasswordResetTokenInvalid = errors.New("invalid or expired password reset token")
+
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create enregistre une demande de réinitialisation ; les demandes précédentes de l'utilisateur
+// encore inutilisées sont supprimées, seul le dernier lien envoyé reste valable
+func (r *PasswordResetRepository) Create(token *models.PasswordResetToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Delete(&models.PasswordResetToken{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(token).Error
+	})
+}
+
+// ResetPassword consomme le jeton d'empreinte tokenHash et remplace le mot de passe de son utilisateur
+// dans une même transaction. La ligne du jeton est verrouillée pour qu'il ne serve qu'une fois.
+// Retourne l'ID de l'utilisateur, ou ErrPasswordResetTokenInvalid si le jeton est inconnu, utilisé ou expiré.
+func (r *PasswordResetRepository) ResetPassword(tokenHash, passwordHash string, now time.Time) (uint, error) {
+	var userID uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var token models.PasswordResetToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", tokenHash).
+			First(&token).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPasswordResetTokenInvalid
+			}
+			return err
+		}
+		if token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+			return ErrPasswordResetTokenInvalid
+		}
+
+		if err := tx.Model(&token).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		userID = token.UserID
+		return NewUserRepository(tx).UpdatePassword(token.UserID, passwordHash, 0)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentEventRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentEventRepository(db *gorm.DB) *PaymentEventRepository {
+	return &PaymentEventRepository{db: db}
+}
+
+// ProcessOnce enregistre l'événement et appelle apply dans la même transaction.
+// Si l'événement a déjà été enregistré, apply n'est pas appelée et processed vaut false.
+func (r *PaymentEventRepository) ProcessOnce(eventID, eventType string, apply func(tx *gorm.DB) error) (bool, error) {
+	processed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ProcessedPaymentEvent{
+			EventID:     eventID,
+			Type:        eventType,
+			ProcessedAt: time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		processed = true
+		return apply(tx)
+	})
+	return processed, err
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRefundRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRefundRepository(db *gorm.DB) *PaymentRefundRepository {
+	return &PaymentRefundRepository{db: db}
+}
+
+// WithTx retourne un PaymentRefundRepository travaillant dans la transaction tx
+func (r *PaymentRefundRepository) WithTx(tx *gorm.DB) *PaymentRefundRepository {
+	return &PaymentRefundRepository{db: tx}
+}
+
+// Schedule enregistre un remboursement à effectuer. Une intention de paiement n'est remboursée
+// qu'une fois : si un remboursement existe déjà pour elle, rien n'est fait.
+func (r *PaymentRefundRepository) Schedule(refund *models.PaymentRefund) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "payment_intent_id"}},
+		DoNothing: true,
+	}).Omit(clause.Associations).Create(refund).Error
+}
+
+// ClaimDue réserve au plus limit remboursements en attente dont la prochaine tentative est due ; voir claimDue
+func (r *PaymentRefundRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.PaymentRefund, error) {
+	return claimDue[models.PaymentRefund](r.db, models.RefundPending, now, limit, lease)
+}
+
+// MarkRefunded enregistre le remboursement effectué
+func (r *PaymentRefundRepository) MarkRefunded(id uint, refundedAt time.Time) error {
+	return r.db.Model(&models.PaymentRefund{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      models.RefundRefunded,
+			"refunded_at": refundedAt,
+			"last_error":  "",
+		}).Error
+}
+
+// MarkRetry enregistre l'échec d'une tentative et programme la suivante à nextAttemptAt
+func (r *PaymentRefundRepository) MarkRetry(id uint, nextAttemptAt time.Time, lastError string) error {
+	return markRetry[models.PaymentRefund](r.db, id, nextAttemptAt, lastError)
+}
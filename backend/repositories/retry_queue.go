@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// claimDue réserve au plus limit lignes de T au statut pending dont la prochaine tentative (next_attempt_at)
+// est due. Chaque ligne réservée compte une tentative de plus (attempts) et n'est plus due avant now+lease :
+// une autre instance ne la traitera pas en même temps, et elle sera retentée si le traitement est interrompu.
+// Les lignes déjà verrouillées par une autre instance sont ignorées (FOR UPDATE SKIP LOCKED).
+func claimDue[T any](db *gorm.DB, pending string, now time.Time, limit int, lease time.Duration) ([]T, error) {
+	var rows []T
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Model(new(T)).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", pending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = tx.Model(new(T)).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"next_attempt_at": now.Add(lease),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Order("id").Find(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// markRetry enregistre l'échec d'une tentative sur la ligne id de T et programme la suivante à nextAttemptAt
+func markRetry[T any](db *gorm.DB, id uint, nextAttemptAt time.Time, lastError string) error {
+	return db.Model(new(T)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}).Error
+}
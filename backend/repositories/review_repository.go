@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrReviewExists   = errors.New("this order has already been reviewed")
+)
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// Create enregistre l'avis et recalcule la note du magasin dans une même transaction
+func (r *ReviewRepository) Create(review *models.Review) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "order_id"}}, DoNothing: true}).
+			Create(review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReviewExists
+		}
+		return recomputeStoreRating(tx, review.StoreID)
+	})
+}
+
+func (r *ReviewRepository) GetByID(id uint) (*models.Review, error) {
+	var review models.Review
+	if err := r.db.First(&review, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListVisibleByStore retourne une page des avis visibles du magasin, du plus récent au plus ancien,
+// ainsi que le nombre total d'avis visibles
+func (r *ReviewRepository) ListVisibleByStore(storeID uint, offset, limit int) ([]models.Review, int64, error) {
+	query := r.db.Model(&models.Review{}).Where("store_id = ? AND hidden = ?", storeID, false)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []models.Review
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// Update verrouille l'avis, applique fn, puis enregistre l'avis et recalcule la note du magasin.
+// Si fn retourne une erreur, rien n'est enregistré.
+func (r *ReviewRepository) Update(id uint, fn func(review *models.Review) error) (*models.Review, error) {
+	var review models.Review
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReviewNotFound
+			}
+			return err
+		}
+
+		if err := fn(&review); err != nil {
+			return err
+		}
+
+		if err := tx.Omit(clause.Associations).Save(&review).Error; err != nil {
+			return err
+		}
+		return recomputeStoreRating(tx, review.StoreID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// Delete supprime l'avis et recalcule la note du magasin dans une même transaction
+func (r *ReviewRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var review models.Review
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReviewNotFound
+			}
+			return err
+		}
+
+		if err := tx.Delete(&review).Error; err != nil {
+			return err
+		}
+		return recomputeStoreRating(tx, review.StoreID)
+	})
+}
+
+// recomputeStoreRating recalcule la note moyenne et le nombre d'avis visibles du magasin.
+// La ligne du magasin est verrouillée pour que deux avis simultanés ne s'écrasent pas.
+func recomputeStoreRating(tx *gorm.DB, storeID uint) error {
+	var store models.Store
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&store, storeID).Error; err != nil {
+		return err
+	}
+
+	var stats struct {
+		Average float64
+		Count   int
+	}
+	err := tx.Model(&models.Review{}).
+		Select("CAST(COALESCE(ROUND(AVG(rating), 2), 0) AS DOUBLE PRECISION) AS average, COUNT(*) AS count").
+		Where("store_id = ? AND hidden = ?", storeID, false).
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Store{}).Where("id = ?", storeID).Updates(map[string]interface{}{
+		"rating":       stats.Average,
+		"review_count": stats.Count,
+	}).Error
+}
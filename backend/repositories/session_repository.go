@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrRefreshTokenInvalid = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create ouvre une session avec son premier refresh token
+func (r *SessionRepository) Create(session *models.Session, tokenHash string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(session).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(&models.RefreshToken{
+			SessionID: session.ID,
+			TokenHash: tokenHash,
+			CreatedAt: session.LastUsedAt,
+		}).Error
+	})
+}
+
+// Rotate remplace le refresh token d'empreinte oldHash par celui d'empreinte newHash et met à jour
+// la session (appareil, dernière utilisation, expiration). La ligne du jeton est verrouillée pour
+// qu'un même jeton ne puisse pas être échangé deux fois.
+// Un jeton déjà remplacé signale un vol probable : la session entière est révoquée et
+// ErrRefreshTokenReused est retournée.
+func (r *SessionRepository) Rotate(oldHash, newHash string, update func(session *models.Session), now time.Time) (*models.Session, error) {
+	var session models.Session
+	reused := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var token models.RefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", oldHash).
+			First(&token).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRefreshTokenInvalid
+			}
+			return err
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, token.SessionID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRefreshTokenInvalid
+			}
+			return err
+		}
+		if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+			return ErrRefreshTokenInvalid
+		}
+
+		if token.RotatedAt != nil {
+			// La révocation est validée avec la transaction ; l'erreur est retournée ensuite
+			reused = true
+			return tx.Model(&session).Update("revoked_at", now).Error
+		}
+
+		if err := tx.Model(&token).Update("rotated_at", now).Error; err != nil {
+			return err
+		}
+		err = tx.Omit(clause.Associations).Create(&models.RefreshToken{
+			SessionID: session.ID,
+			TokenHash: newHash,
+			CreatedAt: now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		update(&session)
+		return tx.Model(&session).Select("user_agent", "ip_address", "last_used_at", "expires_at").Updates(&session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	return &session, nil
+}
+
+// FindActiveByUser retourne les sessions actives de l'utilisateur, la plus récemment utilisée en premier
+func (r *SessionRepository) FindActiveByUser(userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Revoke révoque une session active de l'utilisateur
+func (r *SessionRepository) Revoke(userID, sessionID uint, now time.Time) error {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeByToken révoque la session à laquelle appartient le refresh token d'empreinte tokenHash
+func (r *SessionRepository) RevokeByToken(tokenHash string, now time.Time) error {
+	result := r.db.Model(&models.Session{}).
+		Where("revoked_at IS NULL AND id = (?)",
+			r.db.Model(&models.RefreshToken{}).Select("session_id").Where("token_hash = ?", tokenHash)).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRefreshTokenInvalid
+	}
+	return nil
+}
+
+// RevokeAll révoque les sessions actives de l'utilisateur, sauf exceptID (0 pour toutes),
+// et retourne leur nombre
+func (r *SessionRepository) RevokeAll(userID, exceptID uint, now time.Time) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL AND id <> ?", userID, exceptID).
+		Update("revoked_at", now)
+	return result.RowsAffected, result.Error
+}
@@ -1,8 +1,11 @@
 package repositories
 
 import (
+	"errors"
+
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StoreRepository struct {
@@ -62,16 +65,25 @@ func (r *StoreRepository) DeleteStore(store *models.Store) error {
 }
 func (r *StoreRepository) GetStoreStaff(storeID uint) ([]models.StoreStaff, error) {
 	var storeStaff []models.StoreStaff
-	err := r.db.Where("store_id = ?", storeID).Find(&storeStaff).Error
+	err := r.db.Preload("User").Where("store_id = ?", storeID).Find(&storeStaff).Error
 	if err != nil {
 		return nil, err
 	}
 	return storeStaff, nil
 }
-func (r *StoreRepository) GetStoreBasketConfig(storeID uint) (*models.BasketConfiguration, error) {
+func (r *StoreRepository) GetStoreBasketConfigs(storeID uint) ([]models.BasketConfiguration, error) {
+	var configs []models.BasketConfiguration
+	err := r.db.Where("store_id = ?", storeID).Order("id").Find(&configs).Error
+	return configs, err
+}
+
+func (r *StoreRepository) GetStoreBasketConfig(storeID, configID uint) (*models.BasketConfiguration, error) {
 	var config models.BasketConfiguration
-	err := r.db.Where("store_id = ?", storeID).First(&config).Error
+	err := r.db.Where("store_id = ? AND id = ?", storeID, configID).First(&config).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBasketConfigurationNotFound
+		}
 		return nil, err
 	}
 	return &config, nil
@@ -82,7 +94,7 @@ func (r *StoreRepository) CreateStoreBasketConfig(config *models.BasketConfigura
 }
 
 func (r *StoreRepository) UpdateStoreBasketConfig(config *models.BasketConfiguration) error {
-	return r.db.Save(config).Error
+	return r.db.Omit(clause.Associations).Save(config).Error
 }
 func (r *StoreRepository) DeleteStoreBasketConfig(config *models.BasketConfiguration) error {
 	return r.db.Delete(config).Error
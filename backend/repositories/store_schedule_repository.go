@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrStoreClosureNotFound = errors.New("store closure not found")
+
+// StoreScheduleRepository gère les horaires d'ouverture et les fermetures exceptionnelles des magasins
+type StoreScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewStoreScheduleRepository(db *gorm.DB) *StoreScheduleRepository {
+	return &StoreScheduleRepository{db: db}
+}
+
+func (r *StoreScheduleRepository) GetOpeningHours(storeID uint) ([]models.StoreOpeningHour, error) {
+	var hours []models.StoreOpeningHour
+	err := r.db.Where("store_id = ?", storeID).Order("weekday, opens_at").Find(&hours).Error
+	return hours, err
+}
+
+// ReplaceOpeningHours remplace l'ensemble des horaires hebdomadaires du magasin dans une même transaction
+func (r *StoreScheduleRepository) ReplaceOpeningHours(storeID uint, hours []models.StoreOpeningHour) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("store_id = ?", storeID).Delete(&models.StoreOpeningHour{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		for i := range hours {
+			hours[i].StoreID = storeID
+		}
+		return tx.Omit(clause.Associations).Create(&hours).Error
+	})
+}
+
+// GetClosuresFrom retourne les fermetures du magasin qui ne sont pas terminées au jour day (YYYY-MM-DD)
+func (r *StoreScheduleRepository) GetClosuresFrom(storeID uint, day string) ([]models.StoreClosure, error) {
+	var closures []models.StoreClosure
+	err := r.db.Where("store_id = ? AND end_date >= ?", storeID, day).
+		Order("start_date").
+		Find(&closures).Error
+	return closures, err
+}
+
+func (r *StoreScheduleRepository) GetClosure(storeID, closureID uint) (*models.StoreClosure, error) {
+	var closure models.StoreClosure
+	err := r.db.Where("store_id = ? AND id = ?", storeID, closureID).First(&closure).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStoreClosureNotFound
+		}
+		return nil, err
+	}
+	return &closure, nil
+}
+
+func (r *StoreScheduleRepository) CreateClosure(closure *models.StoreClosure) error {
+	return r.db.Omit(clause.Associations).Create(closure).Error
+}
+
+func (r *StoreScheduleRepository) UpdateClosure(closure *models.StoreClosure) error {
+	return r.db.Omit(clause.Associations).Save(closure).Error
+}
+
+func (r *StoreScheduleRepository) DeleteClosure(closure *models.StoreClosure) error {
+	return r.db.Delete(closure).Error
+}
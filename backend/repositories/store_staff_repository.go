@@ -23,6 +23,30 @@ func (r *StoreStaffRepository) GetStaffByStore(storeID uint) ([]models.StoreStaf
 	return staffMembers, err
 }
 
+// FindMember retourne l'appartenance de l'utilisateur au staff du magasin, ou nil s'il n'en fait pas partie
+func (r *StoreStaffRepository) FindMember(storeID, userID uint) (*models.StoreStaff, error) {
+	var members []models.StoreStaff
+	err := r.db.Preload("User").Where("store_id = ? AND user_id = ?", storeID, userID).Limit(1).Find(&members).Error
+	if err != nil || len(members) == 0 {
+		return nil, err
+	}
+	return &members[0], nil
+}
+
+// FindByUser retourne les appartenances de l'utilisateur au staff des magasins
+func (r *StoreStaffRepository) FindByUser(userID uint) ([]models.StoreStaff, error) {
+	var members []models.StoreStaff
+	err := r.db.Where("user_id = ?", userID).Find(&members).Error
+	return members, err
+}
+
+// UpdateRole change le rôle du membre du staff
+func (r *StoreStaffRepository) UpdateRole(storeID, userID uint, role string) error {
+	return r.db.Model(&models.StoreStaff{}).
+		Where("store_id = ? AND user_id = ?", storeID, userID).
+		Update("role", role).Error
+}
+
 func (r *StoreStaffRepository) IsUserStaffMember(storeID, userID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.StoreStaff{}).
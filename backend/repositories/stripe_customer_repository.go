@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"gorm.io/gorm"
+)
+
+type StripeCustomerRepository struct {
+	db *gorm.DB
+}
+
+func NewStripeCustomerRepository(db *gorm.DB) *StripeCustomerRepository {
+	return &StripeCustomerRepository{db: db}
+}
+
+func (r *StripeCustomerRepository) FindByUserID(userID uint) (*models.StripeCustomer, error) {
+	var customer models.StripeCustomer
+	err := r.db.Where("user_id = ?", userID).First(&customer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (r *StripeCustomerRepository) Save(customer *models.StripeCustomer) error {
+	return r.db.Save(customer).Error
+}
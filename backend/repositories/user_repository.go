@@ -5,6 +5,7 @@ import (
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -38,6 +39,37 @@ func (r *UserRepository) FindByID(userID uint) (*models.User, error) {
 func (r *UserRepository) Update(user *models.User) error {
 	return r.DB.Save(user).Error
 }
+
+// UpdateByEmail verrouille l'utilisateur (SELECT ... FOR UPDATE) et l'enregistre si update retourne true.
+// Le verrou sérialise les requêtes concurrentes sur un même compte, par exemple les saisies du code de validation.
+func (r *UserRepository) UpdateByEmail(email string, update func(user *models.User) bool) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("email = ?", email).First(&user).Error
+		if err != nil {
+			return err
+		}
+		if !update(&user) {
+			return nil
+		}
+		return tx.Save(&user).Error
+	})
+}
+
+// UpdateByID verrouille l'utilisateur (SELECT ... FOR UPDATE) et l'enregistre si update retourne true
+func (r *UserRepository) UpdateByID(userID uint, update func(user *models.User) bool) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error
+		if err != nil {
+			return err
+		}
+		if !update(&user) {
+			return nil
+		}
+		return tx.Save(&user).Error
+	})
+}
 func (r *UserRepository) IsMerchant(userID uint) (bool, error) {
 	var merchantCount int64
 
@@ -81,16 +113,15 @@ func (r *UserRepository) GetUsers() ([]models.User, error) {
 	return users, err
 }
 
-func (r *UserRepository) StoreRefreshToken(userID uint, refreshToken string, expiredTime time.Time) error {
-	return r.DB.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
-		"refresh_token": refreshToken,
-		"expiry_time":   expiredTime,
-	}).Error
-}
-func (r *UserRepository) FindByRefreshToken(refreshToken string) (*models.User, error) {
-	var user models.User
-	if err := r.DB.Where("refresh_token = ?", refreshToken).First(&user).Error; err != nil {
-		return nil, err
-	}
-	return &user, nil
+// UpdatePassword remplace le mot de passe de l'utilisateur et révoque ses sessions, sauf keepSessionID
+// (0 pour toutes) : les appareils connectés avec l'ancien mot de passe devront se reconnecter
+func (r *UserRepository) UpdatePassword(userID uint, passwordHash string, keepSessionID uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", userID).Update("password_hash", passwordHash).Error
+		if err != nil {
+			return err
+		}
+		_, err = NewSessionRepository(tx).RevokeAll(userID, keepSessionID, time.Now())
+		return err
+	})
 }
@@ -1,8 +1,12 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/handlers"
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
 	"github.com/Sebiche09/app-anti-gaspillage.git/middlewares"
+	"github.com/Sebiche09/app-anti-gaspillage.git/ratelimit"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -13,19 +17,56 @@ type Handler struct {
 	DB *gorm.DB
 }
 
-func RegisterRoutes(r *gin.Engine, db *gorm.DB, h *handlers.Handlers) {
+// RegisterRoutes enregistre les routes de l'API. limiter conserve les compteurs des limites de débit
+// définies ci-dessous pour les routes exposées au bourrage d'identifiants et à l'envoi massif d'emails,
+// ainsi que pour la recherche de paniers et les flux temps réel, coûteux à servir.
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, h *handlers.Handlers, limiter ratelimit.Store) {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	limit := func(name string, limit ratelimit.Limit, key middlewares.RateLimitKey) gin.HandlerFunc {
+		return middlewares.RateLimit(limiter, name, limit, key)
+	}
+
 	api := r.Group("/api")
 
 	{
 		auth := api.Group("/auth")
 		{
-			auth.POST("/refresh-token", h.User.RefreshToken)
-			auth.POST("/resend-code", h.User.ResendCode)
-			auth.POST("/validate-code", h.User.ValidateCode)
-			auth.POST("/signup", h.User.Signup)
-			auth.POST("/login", h.User.Login)
+			auth.POST("/refresh-token",
+				limit("refresh-token", ratelimit.PerMinute(30), middlewares.ByIP),
+				h.Session.RefreshToken)
+			auth.POST("/logout",
+				limit("logout", ratelimit.PerMinute(30), middlewares.ByIP),
+				h.Session.Logout)
+			auth.POST("/resend-code",
+				limit("resend-code", ratelimit.PerHour(20), middlewares.ByIP),
+				limit("resend-code", ratelimit.PerHour(5), middlewares.ByEmail),
+				h.User.ResendCode)
+			auth.POST("/validate-code",
+				limit("validate-code", ratelimit.PerMinute(20), middlewares.ByIP),
+				limit("validate-code", ratelimit.PerHour(10), middlewares.ByEmail),
+				h.User.ValidateCode)
+			auth.POST("/signup",
+				limit("signup", ratelimit.PerHour(10), middlewares.ByIP),
+				limit("signup", ratelimit.PerHour(3), middlewares.ByEmail),
+				h.User.Signup)
+			auth.POST("/login",
+				limit("login", ratelimit.PerMinute(10), middlewares.ByIP),
+				limit("login", ratelimit.Limit{Burst: 5, Every: 3 * time.Minute}, middlewares.ByEmail),
+				h.User.Login)
+			auth.POST("/forgot-password",
+				limit("forgot-password", ratelimit.PerHour(10), middlewares.ByIP),
+				limit("forgot-password", ratelimit.PerHour(3), middlewares.ByEmail),
+				h.User.ForgotPassword)
+			auth.POST("/reset-password",
+				limit("reset-password", ratelimit.PerHour(20), middlewares.ByIP),
+				h.User.ResetPassword)
+		}
+
+		// Webhooks des prestataires externes, authentifiés par signature
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.POST("/payments", h.Webhook.PaymentWebhook)
 		}
 	}
 
@@ -38,19 +79,80 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB, h *handlers.Handlers) {
 			stores.GET("/", h.Store.GetStores)
 			stores.GET("/:id", h.Store.GetStore)
 			stores.GET("/:id/baskets", h.Basket.GetBasketsByStore)
+			stores.GET("/:id/baskets/stream",
+				limit("store-baskets-stream", ratelimit.PerMinute(10), middlewares.ByIP),
+				h.Stream.StreamStoreBaskets)
+			stores.GET("/:id/reviews", h.Review.GetStoreReviews)
+			stores.POST("/:id/favorite", h.Favorite.AddFavorite)
+			stores.DELETE("/:id/favorite", h.Favorite.RemoveFavorite)
+			stores.POST("/:id/orders/verify", middlewares.RequireStorePermission(db, authz.PermissionOrdersVerify), h.Order.VerifyOrder)
 
 			// Route pour obtenir les invitations en attente d'un magasin
-			stores.GET("/:id/request-status-statustions", h.Invitation.GetPendingInvitations)
+			stores.GET("/:id/invitations", h.Invitation.GetPendingInvitations)
+			// Ancienne adresse de la même liste, dépréciée
+			stores.GET("/:id/request-status-statustions", h.Invitation.GetPendingInvitationsLegacy)
+		}
+
+		// Données propres à l'utilisateur connecté
+		me := authenticated.Group("/me")
+		{
+			me.GET("", h.Profile.GetProfile)
+			me.PUT("", h.Profile.UpdateProfile)
+			me.DELETE("/default-location", h.Profile.ClearDefaultLocation)
+			me.POST("/email",
+				limit("change-email", ratelimit.PerHour(5), middlewares.ByUser),
+				h.Profile.RequestEmailChange)
+			me.POST("/email/confirm",
+				limit("confirm-email-change", ratelimit.PerHour(10), middlewares.ByUser),
+				h.Profile.ConfirmEmailChange)
+			me.DELETE("/email", h.Profile.CancelEmailChange)
+			me.POST("/password",
+				limit("change-password", ratelimit.Limit{Burst: 5, Every: 3 * time.Minute}, middlewares.ByUser),
+				h.User.ChangePassword)
+			me.GET("/sessions", h.Session.GetSessions)
+			me.DELETE("/sessions/:id", h.Session.RevokeSession)
+			me.POST("/logout-all", h.Session.LogoutAll)
+			me.GET("/favorites", h.Favorite.GetFavorites)
+			me.GET("/invitations", h.Invitation.GetMyInvitations)
+			me.POST("/invitations/:id/accept", h.Invitation.AcceptMyInvitation)
+			me.POST("/invitations/:id/decline", h.Invitation.DeclineInvitation)
+			me.GET("/notifications", h.Notification.GetNotifications)
+			me.PUT("/notifications/read-all", h.Notification.MarkAllNotificationsRead)
+			me.PUT("/notifications/:id/read", h.Notification.MarkNotificationRead)
+			me.GET("/notification-preferences", h.Notification.GetNotificationPreferences)
+			me.PUT("/notification-preferences", h.Notification.UpdateNotificationPreferences)
 		}
 
 		merchants := authenticated.Group("/merchants")
 		{
 			merchants.POST("/", h.Merchant.CreateMerchantRequest)
-			merchants.DELETE("stores/:id", h.Store.DeleteStore)
+			merchants.DELETE("stores/:id", middlewares.RequireStorePermission(db, authz.PermissionStoreManage), h.Store.DeleteStore)
 			merchants.GET("/request-status", h.Merchant.MerchantRequestStatus)
 			merchants.GET("/stores", h.Store.GetStoresMerchant)
-			merchants.PUT("/stores/:id", h.Store.UpdateStore)
+			merchants.PUT("/stores/:id", middlewares.RequireStorePermission(db, authz.PermissionStoreManage), h.Store.UpdateStore)
 			merchants.POST("/stores", h.Store.CreateStore)
+
+			// Configurations panier et publication automatique
+			schedule := middlewares.RequireStorePermission(db, authz.PermissionScheduleManage)
+			merchants.GET("/stores/:id/basket-configurations", schedule, h.Store.GetStoreBasketConfigs)
+			merchants.POST("/stores/:id/basket-configurations", schedule, h.Store.CreateStoreBasketConfig)
+			merchants.PUT("/stores/:id/basket-configurations/:configId", schedule, h.Store.UpdateStoreBasketConfig)
+			merchants.DELETE("/stores/:id/basket-configurations/:configId", schedule, h.Store.DeleteStoreBasketConfig)
+
+			// Horaires d'ouverture et fermetures exceptionnelles
+			merchants.PUT("/stores/:id/opening-hours", schedule, h.Store.UpdateOpeningHours)
+			merchants.GET("/stores/:id/closures", schedule, h.Store.GetStoreClosures)
+			merchants.POST("/stores/:id/closures", schedule, h.Store.CreateStoreClosure)
+			merchants.PUT("/stores/:id/closures/:closureId", schedule, h.Store.UpdateStoreClosure)
+			merchants.DELETE("/stores/:id/closures/:closureId", schedule, h.Store.DeleteStoreClosure)
+
+			// Équipe du magasin
+			merchants.GET("/stores/:id/staff", middlewares.RequireStorePermission(db, authz.PermissionStaffManage), h.Store.GeStoreStaff)
+			merchants.PUT("/stores/:id/staff/:userId", middlewares.RequireStorePermission(db, authz.PermissionStaffManage), h.Store.UpdateStaffRole)
+			merchants.DELETE("/stores/:id/staff/:userId", middlewares.RequireStorePermission(db, authz.PermissionStaffManage), h.Store.RemoveStaffMember)
+
+			// Réponses aux avis clients
+			merchants.PUT("/stores/:id/reviews/:reviewId/reply", middlewares.RequireStorePermission(db, authz.PermissionReviewsReply), h.Review.ReplyToReview)
 		}
 
 		merchants.Use(middlewares.RequireMerchantWithSync(db))
@@ -67,6 +169,10 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB, h *handlers.Handlers) {
 			admin.GET("/merchant-requests", h.Merchant.GetPendingRequests)
 			admin.PUT("/merchant-requests/:id", h.Merchant.ProcessRequest)
 			admin.GET("/users", h.User.GetUsers)
+
+			// Modération des avis
+			admin.PUT("/reviews/:id", h.Review.ModerateReview)
+			admin.DELETE("/reviews/:id", h.Review.DeleteReview)
 		}
 
 		// Routes pour les invitations
@@ -74,6 +180,7 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB, h *handlers.Handlers) {
 		{
 			invitations.POST("/", h.Invitation.CreateInvitation)
 			invitations.GET("/accept", h.Invitation.AcceptInvitation)
+			invitations.POST("/:id/resend", h.Invitation.ResendInvitation)
 			invitations.DELETE("/:id", h.Invitation.CancelInvitation)
 		}
 
@@ -81,17 +188,34 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB, h *handlers.Handlers) {
 		baskets := authenticated.Group("/baskets")
 		{
 			// Routes publiques pour les paniers
-			baskets.GET("/", h.Basket.GetBaskets)
+			baskets.GET("/",
+				limit("search-baskets", ratelimit.PerMinute(60), middlewares.ByIP),
+				h.Basket.GetBaskets)
+			baskets.GET("/stream",
+				limit("baskets-stream", ratelimit.PerMinute(10), middlewares.ByIP),
+				h.Stream.StreamNearbyBaskets)
 			baskets.GET("/:id", h.Basket.GetBasket)
 
 			// Routes pour la gestion des paniers (staff du magasin uniquement)
 			staffBaskets := baskets.Group("")
-			staffBaskets.Use(middlewares.RequireStoreStaff())
+			staffBaskets.Use(middlewares.RequireRequestStorePermission(db, authz.PermissionBasketsWrite))
 			{
 				staffBaskets.POST("/", h.Basket.CreateBasket)
 				staffBaskets.PUT("/:id", h.Basket.UpdateBasket)
 				staffBaskets.DELETE("/:id", h.Basket.DeleteBasket)
 			}
 		}
+
+		// Routes pour les commandes (réservations)
+		orders := authenticated.Group("/orders")
+		{
+			orders.POST("/", h.Order.ReserveBasket)
+			orders.GET("/me", h.Order.GetMyOrders)
+			orders.GET("/stream",
+				limit("orders-stream", ratelimit.PerMinute(10), middlewares.ByIP),
+				h.Stream.StreamStoreOrders)
+			orders.POST("/:id/pay", h.Order.PayOrder)
+			orders.POST("/:id/review", h.Review.CreateReview)
+		}
 	}
 }
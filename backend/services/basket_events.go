@@ -0,0 +1,102 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/events"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+// Types des événements du flux temps réel des paniers
+const (
+	BasketEventCreated   = "basket.created"
+	BasketEventUpdated   = "basket.updated"
+	BasketEventReserved  = "basket.reserved"
+	BasketEventSoldOut   = "basket.sold_out"
+	BasketEventRestocked = "basket.restocked"
+	BasketEventDeleted   = "basket.deleted"
+	BasketEventExpired   = "basket.expired"
+)
+
+// BasketEvent est l'état d'un panier après un changement, diffusé aux clients du flux temps réel
+type BasketEvent struct {
+	BasketID  uint
+	StoreID   uint
+	Quantity  int
+	Status    string
+	Available bool // Réservable : en stock, au statut Disponible et plage de retrait non terminée
+	Latitude  float64
+	Longitude float64
+}
+
+// BasketEvents publie les changements de disponibilité des paniers sur le broker du flux temps réel
+type BasketEvents struct {
+	broker     *events.Broker
+	basketRepo *repositories.BasketRepository
+}
+
+func NewBasketEvents(broker *events.Broker, basketRepo *repositories.BasketRepository) *BasketEvents {
+	return &BasketEvents{broker: broker, basketRepo: basketRepo}
+}
+
+// Publish relit le panier et diffuse son état. Un échec est journalisé sans interrompre l'opération
+// qui l'a déclenché : les clients se resynchronisent avec GET /api/baskets.
+func (e *BasketEvents) Publish(eventType string, basketID uint) {
+	basket, err := e.basketRepo.GetByID(int(basketID))
+	if err != nil {
+		log.Printf("basket events: basket %d: %v", basketID, err)
+		return
+	}
+	e.PublishBasket(eventType, basket)
+}
+
+// PublishReservation diffuse l'état du panier après une réservation : sold_out s'il n'en reste plus
+func (e *BasketEvents) PublishReservation(basketID uint) {
+	basket, err := e.basketRepo.GetByID(int(basketID))
+	if err != nil {
+		log.Printf("basket events: basket %d: %v", basketID, err)
+		return
+	}
+	eventType := BasketEventReserved
+	if basket.Quantity <= 0 {
+		eventType = BasketEventSoldOut
+	}
+	e.PublishBasket(eventType, basket)
+}
+
+// PublishBasket diffuse l'état du panier donné, dont le magasin et le statut doivent être chargés
+func (e *BasketEvents) PublishBasket(eventType string, basket *models.Basket) {
+	event := BasketEvent{
+		BasketID:  basket.ID,
+		StoreID:   uint(basket.StoreID),
+		Quantity:  basket.Quantity,
+		Status:    basket.Status.Name,
+		Latitude:  basket.Store.Latitude,
+		Longitude: basket.Store.Longitude,
+	}
+	if eventType != BasketEventDeleted {
+		event.Available = basket.Quantity > 0 &&
+			basket.Status.Name == models.BasketStatusAvailable &&
+			(basket.PickupEnd == nil || basket.PickupEnd.After(time.Now()))
+	}
+	e.broker.Publish(eventType, event)
+}
+
+// SubscribeStore abonne un client aux changements des paniers du magasin
+func (e *BasketEvents) SubscribeStore(storeID uint) *events.Subscription {
+	return e.broker.Subscribe(func(event events.Event) bool {
+		basket, ok := event.Data.(BasketEvent)
+		return ok && basket.StoreID == storeID
+	})
+}
+
+// SubscribeNearby abonne un client aux changements des paniers des magasins situés à moins de radiusKm du point donné
+func (e *BasketEvents) SubscribeNearby(lat, lng, radiusKm float64) *events.Subscription {
+	return e.broker.Subscribe(func(event events.Event) bool {
+		basket, ok := event.Data.(BasketEvent)
+		return ok && utils.DistanceKm(lat, lng, basket.Latitude, basket.Longitude) <= radiusKm
+	})
+}
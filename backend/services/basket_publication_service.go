@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+var (
+	ErrInvalidWeekday         = errors.New("weekdays must be between 1 (monday) and 7 (sunday)")
+	ErrScheduleRequiresPickup = errors.New("a weekly schedule requires pickup start and end times")
+)
+
+// BasketPublicationService publie chaque jour les paniers des configurations ayant un planning hebdomadaire
+type BasketPublicationService struct {
+	basketRepo    *repositories.BasketRepository
+	scheduleRepo  *repositories.StoreScheduleRepository
+	notifications *NotificationService
+	events        *BasketEvents
+	clock         utils.Clock
+}
+
+func NewBasketPublicationService(basketRepo *repositories.BasketRepository, scheduleRepo *repositories.StoreScheduleRepository, notifications *NotificationService, events *BasketEvents, clock utils.Clock) *BasketPublicationService {
+	return &BasketPublicationService{basketRepo: basketRepo, scheduleRepo: scheduleRepo, notifications: notifications, events: events, clock: clock}
+}
+
+// PublishDue publie les paniers du jour qui ne l'ont pas encore été ; destiné au scheduler.
+// Le jour est évalué dans le fuseau horaire de chaque magasin, et aucun panier n'est publié
+// une fois la plage de retrait du jour terminée ni un jour où le magasin est fermé.
+func (s *BasketPublicationService) PublishDue(ctx context.Context) error {
+	configs, err := s.basketRepo.FindScheduledConfigurations()
+	if err != nil {
+		return err
+	}
+
+	published := 0
+	for i := range configs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		basket, err := s.publish(&configs[i])
+		if err != nil {
+			log.Printf("publication: configuration %d: %v", configs[i].ID, err)
+			continue
+		}
+		if basket != nil {
+			published++
+			s.events.Publish(BasketEventCreated, basket.ID)
+			if err := s.notifications.NotifyNewBasket(ctx, basket); err != nil {
+				log.Printf("publication: basket %d: notifications: %v", basket.ID, err)
+			}
+		}
+	}
+
+	if published > 0 {
+		log.Printf("publication: %d basket(s) published", published)
+	}
+	return nil
+}
+
+// publish publie le panier du jour de la configuration et le retourne, ou retourne nil s'il n'y a rien à publier
+func (s *BasketPublicationService) publish(config *models.BasketConfiguration) (*models.Basket, error) {
+	now := s.clock.Now().In(config.Store.Location())
+	if !config.PublishesOn(now.Weekday()) {
+		return nil, nil
+	}
+
+	start, end, err := configurationPickupWindow(config, now)
+	if err != nil {
+		return nil, err
+	}
+	if end == nil || !now.Before(*end) {
+		return nil, nil
+	}
+
+	schedule, err := loadStoreSchedule(s.scheduleRepo, &config.Store, now)
+	if err != nil {
+		return nil, err
+	}
+	if !schedule.allowsPickup(*start, *end) {
+		return nil, nil
+	}
+
+	day := now.Format("2006-01-02")
+	var basket *models.Basket
+	published, err := s.basketRepo.PublishConfiguration(config.ID, day, func(locked *models.BasketConfiguration) (*models.Basket, error) {
+		configID := int(locked.ID)
+		basket = &models.Basket{
+			ConfigurationID:    &configID,
+			StoreID:            int(locked.StoreID),
+			Name:               fmt.Sprintf("%s - %s #%d", locked.Name, day, locked.ID),
+			Description:        locked.Description,
+			DiscountPercentage: locked.DiscountPercentage,
+			OriginalPrice:      locked.OriginalPrice,
+			Quantity:           locked.Quantity,
+			ExpirationDate:     &day,
+			PickupStart:        start,
+			PickupEnd:          end,
+		}
+		return basket, nil
+	})
+	if err != nil || !published {
+		return nil, err
+	}
+	return basket, nil
+}
+
+// validateBasketSchedule vérifie le planning hebdomadaire et les heures de retrait d'une configuration
+func validateBasketSchedule(weekdays []int, pickupStart, pickupEnd string) error {
+	for _, day := range weekdays {
+		if day < 1 || day > 7 {
+			return ErrInvalidWeekday
+		}
+	}
+	if err := validatePickupTimes(pickupStart, pickupEnd); err != nil {
+		return err
+	}
+	if len(weekdays) > 0 && pickupStart == "" {
+		return ErrScheduleRequiresPickup
+	}
+	return nil
+}
+
+// normalizeWeekdays trie les jours de publication et supprime les doublons
+func normalizeWeekdays(weekdays []int) []int {
+	days := append([]int(nil), weekdays...)
+	sort.Ints(days)
+	unique := days[:0]
+	for _, day := range days {
+		if len(unique) == 0 || day != unique[len(unique)-1] {
+			unique = append(unique, day)
+		}
+	}
+	return unique
+}
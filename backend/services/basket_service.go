@@ -2,22 +2,33 @@ package services
 
 import (
 	"errors"
+	"time"
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
 )
 
+var (
+	ErrInvalidExpirationDate   = errors.New("invalid expiration date, expected YYYY-MM-DD")
+	ErrConfigurationWrongStore = errors.New("basket configuration belongs to another store")
+	ErrBasketWrongStore        = errors.New("basket belongs to another store")
+)
+
 type BasketService struct {
-	BasketRepo *repositories.BasketRepository
+	BasketRepo    *repositories.BasketRepository
+	storeRepo     *repositories.StoreRepository
+	scheduleRepo  *repositories.StoreScheduleRepository
+	notifications *NotificationService
+	events        *BasketEvents
 }
 
-func NewBasketService(basketRepo *repositories.BasketRepository) *BasketService {
-	return &BasketService{BasketRepo: basketRepo}
+func NewBasketService(basketRepo *repositories.BasketRepository, storeRepo *repositories.StoreRepository, scheduleRepo *repositories.StoreScheduleRepository, notifications *NotificationService, events *BasketEvents) *BasketService {
+	return &BasketService{BasketRepo: basketRepo, storeRepo: storeRepo, scheduleRepo: scheduleRepo, notifications: notifications, events: events}
 }
 
-func (s *BasketService) GetBaskets() ([]models.Basket, error) {
-	return s.BasketRepo.GetAll()
+func (s *BasketService) GetBaskets(search repositories.BasketSearch) (*repositories.BasketPage, error) {
+	return s.BasketRepo.Search(search)
 }
 
 func (s *BasketService) GetBasket(id int) (*models.Basket, error) {
@@ -34,41 +45,134 @@ func (s *BasketService) CreateBasket(req requests.CreateBasketRequest, userId ui
 		OriginalPrice:      req.OriginalPrice,
 		Quantity:           req.Quantity,
 		ExpirationDate:     req.ExpirationDate,
+		PickupStart:        req.PickupStart,
+		PickupEnd:          req.PickupEnd,
+	}
+
+	// Sans plage explicite, le panier reprend les heures de retrait de sa configuration
+	if basket.PickupStart == nil && basket.PickupEnd == nil && basket.ConfigurationID != nil {
+		if err := s.applyConfigurationPickup(&basket); err != nil {
+			return err
+		}
+	}
+
+	if err := validatePickupWindow(basket.PickupStart, basket.PickupEnd, time.Now()); err != nil {
+		return err
+	}
+	if err := s.validateOpeningHours(uint(basket.StoreID), basket.PickupStart, basket.PickupEnd); err != nil {
+		return err
+	}
+
+	if err := s.BasketRepo.Create(&basket); err != nil {
+		return err
 	}
-	return s.BasketRepo.Create(&basket)
+
+	s.events.Publish(BasketEventCreated, basket.ID)
+	s.notifications.NotifyNewBasketAsync(&basket)
+	return nil
 }
 
-func (s *BasketService) UpdateBasket(id int, updates models.Basket, userId int) (*models.Basket, error) {
+// validateOpeningHours vérifie que la plage de retrait tient dans les horaires d'ouverture du magasin
+// et ne tombe pas un jour de fermeture exceptionnelle
+func (s *BasketService) validateOpeningHours(storeID uint, start, end *time.Time) error {
+	if start == nil || end == nil {
+		return nil
+	}
+	store, err := s.storeRepo.GetStoreByID(storeID)
+	if err != nil {
+		return err
+	}
+	schedule, err := loadStoreSchedule(s.scheduleRepo, store, *start)
+	if err != nil {
+		return err
+	}
+	if !schedule.allowsPickup(*start, *end) {
+		return ErrPickupOutsideOpeningHours
+	}
+	return nil
+}
+
+// applyConfigurationPickup calcule la plage de retrait du panier à partir de sa configuration,
+// le jour de sa date d'expiration ou, à défaut, le jour même
+func (s *BasketService) applyConfigurationPickup(basket *models.Basket) error {
+	config, err := s.BasketRepo.GetConfiguration(*basket.ConfigurationID)
+	if err != nil {
+		return err
+	}
+	if config.StoreID != uint(basket.StoreID) {
+		return ErrConfigurationWrongStore
+	}
+
+	day := time.Now()
+	if basket.ExpirationDate != nil {
+		day, err = time.ParseInLocation("2006-01-02", *basket.ExpirationDate, config.Store.Location())
+		if err != nil {
+			return ErrInvalidExpirationDate
+		}
+	}
+
+	basket.PickupStart, basket.PickupEnd, err = configurationPickupWindow(config, day)
+	return err
+}
+
+// UpdateBasket modifie un panier du magasin storeID, sur lequel l'appelant a été autorisé
+func (s *BasketService) UpdateBasket(id int, updates models.Basket, storeID uint) (*models.Basket, error) {
 	basket, err := s.BasketRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Vérifie si le basket appartient au magasin de l'utilisateur
-	if basket.StoreID != userId {
-		return nil, errors.New("not authorized to update this basket")
+	// Vérifie que le panier appartient bien au magasin autorisé
+	if uint(basket.StoreID) != storeID {
+		return nil, ErrBasketWrongStore
 	}
 
-	err = s.BasketRepo.Update(basket, updates)
-	return basket, err
+	if updates.PickupStart != nil || updates.PickupEnd != nil {
+		start, end := basket.PickupStart, basket.PickupEnd
+		if updates.PickupStart != nil {
+			start = updates.PickupStart
+		}
+		if updates.PickupEnd != nil {
+			end = updates.PickupEnd
+		}
+		if err := validatePickupWindow(start, end, time.Now()); err != nil {
+			return nil, err
+		}
+		if err := s.validateOpeningHours(uint(basket.StoreID), start, end); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.BasketRepo.Update(basket, updates); err != nil {
+		return basket, err
+	}
+
+	s.events.Publish(BasketEventUpdated, basket.ID)
+	return basket, nil
 }
 
-func (s *BasketService) DeleteBasket(id int, userId int) error {
+// DeleteBasket supprime un panier du magasin storeID, sur lequel l'appelant a été autorisé
+func (s *BasketService) DeleteBasket(id int, storeID uint) error {
 	basket, err := s.BasketRepo.GetByID(id)
 	if err != nil {
 		return err
 	}
 
-	// Vérifie si le basket appartient au magasin de l'utilisateur
-	if basket.StoreID != userId {
-		return errors.New("not authorized to delete this basket")
+	// Vérifie que le panier appartient bien au magasin autorisé
+	if uint(basket.StoreID) != storeID {
+		return ErrBasketWrongStore
+	}
+
+	if err := s.BasketRepo.Delete(basket); err != nil {
+		return err
 	}
 
-	return s.BasketRepo.Delete(basket)
+	s.events.PublishBasket(BasketEventDeleted, basket)
+	return nil
 }
 func (s *BasketService) GetBasketsByStore(storeId int) ([]models.Basket, error) {
 	var baskets []models.Basket
-	err := s.BasketRepo.DB.Where("store_id = ?", storeId).Find(&baskets).Error
+	err := s.BasketRepo.DB.Preload("Store").Preload("Store.Category").Where("store_id = ?", storeId).Find(&baskets).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+var errOrderNoLongerExpired = errors.New("order is no longer expired")
+
+// ExpiryService fait passer dans le temps les réservations, paniers et invitations expirés
+type ExpiryService struct {
+	orderRepo      *repositories.OrderRepository
+	basketRepo     *repositories.BasketRepository
+	invitationRepo *repositories.InvitationRepository
+	notifications  *NotificationService
+	basketEvents   *BasketEvents
+	orderEvents    *OrderEvents
+	clock          utils.Clock
+}
+
+func NewExpiryService(
+	orderRepo *repositories.OrderRepository,
+	basketRepo *repositories.BasketRepository,
+	invitationRepo *repositories.InvitationRepository,
+	notifications *NotificationService,
+	basketEvents *BasketEvents,
+	orderEvents *OrderEvents,
+	clock utils.Clock,
+) *ExpiryService {
+	return &ExpiryService{
+		orderRepo:      orderRepo,
+		basketRepo:     basketRepo,
+		invitationRepo: invitationRepo,
+		notifications:  notifications,
+		basketEvents:   basketEvents,
+		orderEvents:    orderEvents,
+		clock:          clock,
+	}
+}
+
+// Sweep exécute tous les traitements d'expiration ; destiné au scheduler
+func (s *ExpiryService) Sweep(ctx context.Context) error {
+	orders, err := s.ExpireOrders()
+	if err != nil {
+		return err
+	}
+	baskets, err := s.ExpireBaskets()
+	if err != nil {
+		return err
+	}
+	invitations, err := s.ExpireInvitations()
+	if err != nil {
+		return err
+	}
+
+	if orders+baskets+invitations > 0 {
+		log.Printf("expiry: %d order(s) cancelled, %d basket(s) expired, %d invitation(s) expired", orders, baskets, invitations)
+	}
+	return nil
+}
+
+// ExpireOrders annule les réservations non retirées à temps et remet les paniers en stock
+func (s *ExpiryService) ExpireOrders() (int, error) {
+	now := s.clock.Now()
+	ids, err := s.orderRepo.FindExpiredPendingIDs(now)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, id := range ids {
+		var basketID uint
+		err := s.orderRepo.CancelOrder(id, func(order *models.Order) error {
+			// La commande a pu être retirée entre la recherche et le verrouillage
+			if order.Status != models.OrderPending || order.ExpiredAt == nil || !order.ExpiredAt.Before(now) {
+				return errOrderNoLongerExpired
+			}
+			basketID = order.BasketID
+			return nil
+		})
+		if errors.Is(err, errOrderNoLongerExpired) {
+			continue
+		}
+		if err != nil {
+			return cancelled, err
+		}
+		s.notifyCancelled(id)
+		s.basketEvents.Publish(BasketEventRestocked, basketID)
+		s.orderEvents.Publish(OrderEventCancelled, id)
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+// notifyCancelled prévient le client de l'annulation de sa commande, relue avec son panier et son magasin
+func (s *ExpiryService) notifyCancelled(orderID uint) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		log.Printf("expiry: order %d: %v", orderID, err)
+		return
+	}
+	s.notifications.NotifyOrderStatus(order)
+}
+
+// ExpireBaskets marque comme annulés les paniers dont la date d'expiration ou la plage de retrait est passée
+func (s *ExpiryService) ExpireBaskets() (int, error) {
+	now := s.clock.Now()
+	pastExpiration, err := s.basketRepo.CancelPastExpiration(now.Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	s.publishExpired(pastExpiration)
+
+	pastPickup, err := s.basketRepo.CancelPastPickup(now)
+	if err != nil {
+		return len(pastExpiration), err
+	}
+	s.publishExpired(pastPickup)
+	return len(pastExpiration) + len(pastPickup), nil
+}
+
+// publishExpired diffuse aux clients du flux temps réel que les paniers ne sont plus réservables
+func (s *ExpiryService) publishExpired(basketIDs []uint) {
+	for _, basketID := range basketIDs {
+		s.basketEvents.Publish(BasketEventExpired, basketID)
+	}
+}
+
+// ExpireInvitations marque comme expirées les invitations en attente arrivées à échéance
+func (s *ExpiryService) ExpireInvitations() (int, error) {
+	count, err := s.invitationRepo.ExpirePending(s.clock.Now())
+	return int(count), err
+}
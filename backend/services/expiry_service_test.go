@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+// expiryNow est l'heure des tests d'expiration
+var expiryNow = time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+
+func TestExpireOrders(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := utils.FixedClock{Time: expiryNow}
+	ts := newTestServices(db, clock)
+	expiry := newTestExpiryService(db, ts, clock)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+	customer := testutil.CreateUser(t, db, "customer@example.com")
+	basketSub := subscribe(t, ts.basketBroker)
+	orderSub := subscribe(t, ts.orderBroker)
+
+	reserved := testutil.CreateBasket(t, db, store, "Panier surprise", models.BasketStatusReserved, func(basket *models.Basket) {
+		basket.Quantity = 0
+	})
+	expired := testutil.CreateOrder(t, db, reserved, customer, models.OrderPending, expiryNow.Add(-time.Minute))
+	other := testutil.CreateBasket(t, db, store, "Panier du soir", models.BasketStatusAvailable, nil)
+	stillReserved := testutil.CreateOrder(t, db, other, customer, models.OrderPending, expiryNow.Add(time.Hour))
+	paid := testutil.CreateOrder(t, db, other, customer, models.OrderConfirmed, expiryNow.Add(-time.Hour))
+
+	cancelled, err := expiry.ExpireOrders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled != 1 {
+		t.Fatalf("cancelled = %d, want 1", cancelled)
+	}
+
+	for _, tt := range []struct {
+		order *models.Order
+		want  string
+	}{
+		{expired, models.OrderCancelled},
+		{stillReserved, models.OrderPending},
+		{paid, models.OrderConfirmed},
+	} {
+		if got := testutil.OrderStatus(t, db, tt.order.ID); got != tt.want {
+			t.Errorf("order %d status = %q, want %q", tt.order.ID, got, tt.want)
+		}
+	}
+
+	basket := testutil.LoadBasket(t, db, reserved.ID)
+	if basket.Quantity != 1 || basket.Status.Name != models.BasketStatusAvailable {
+		t.Errorf("basket = quantity %d, status %q; want restocked and available", basket.Quantity, basket.Status.Name)
+	}
+
+	// La notification est construite à partir de la commande relue avec son panier
+	var notifications []models.Notification
+	if err := db.Where("user_id = ?", customer.ID).Find(&notifications).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(notifications))
+	}
+	if n := notifications[0]; n.Type != models.NotificationTypeOrderCancelled || !strings.Contains(n.Body, "Panier surprise") {
+		t.Errorf("notification = %q %q, want order cancelled naming the basket", n.Type, n.Body)
+	}
+
+	basketEvents := received(basketSub)
+	if len(basketEvents) != 1 || basketEvents[0].Type != BasketEventRestocked || basketEvents[0].Data.(BasketEvent).BasketID != reserved.ID {
+		t.Errorf("basket events = %+v, want one restocked event for basket %d", basketEvents, reserved.ID)
+	}
+	orderEvents := received(orderSub)
+	if len(orderEvents) != 1 || orderEvents[0].Type != OrderEventCancelled {
+		t.Errorf("order events = %+v, want one cancelled event", orderEvents)
+	}
+}
+
+func TestExpireBaskets(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := utils.FixedClock{Time: expiryNow}
+	ts := newTestServices(db, clock)
+	expiry := newTestExpiryService(db, ts, clock)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+	sub := subscribe(t, ts.basketBroker)
+
+	yesterday := expiryNow.AddDate(0, 0, -1).Format("2006-01-02")
+	tomorrow := expiryNow.AddDate(0, 0, 1).Format("2006-01-02")
+	pickupOver := expiryNow.Add(-time.Hour)
+	pickupLater := expiryNow.Add(time.Hour)
+
+	pastExpiration := testutil.CreateBasket(t, db, store, "Date dépassée", models.BasketStatusReserved, func(basket *models.Basket) {
+		basket.ExpirationDate = &yesterday
+	})
+	pastPickup := testutil.CreateBasket(t, db, store, "Retrait terminé", models.BasketStatusAvailable, func(basket *models.Basket) {
+		basket.PickupEnd = &pickupOver
+	})
+	reservedPastPickup := testutil.CreateBasket(t, db, store, "Retrait terminé réservé", models.BasketStatusReserved, func(basket *models.Basket) {
+		basket.PickupEnd = &pickupOver
+	})
+	current := testutil.CreateBasket(t, db, store, "En cours", models.BasketStatusAvailable, func(basket *models.Basket) {
+		basket.ExpirationDate = &tomorrow
+		basket.PickupEnd = &pickupLater
+	})
+	sold := testutil.CreateBasket(t, db, store, "Vendu", models.BasketStatusSold, func(basket *models.Basket) {
+		basket.ExpirationDate = &yesterday
+	})
+
+	count, err := expiry.ExpireBaskets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expired = %d, want 2", count)
+	}
+
+	for _, tt := range []struct {
+		basket *models.Basket
+		want   string
+	}{
+		{pastExpiration, models.BasketStatusCancelled},
+		{pastPickup, models.BasketStatusCancelled},
+		{reservedPastPickup, models.BasketStatusReserved},
+		{current, models.BasketStatusAvailable},
+		{sold, models.BasketStatusSold},
+	} {
+		if got := testutil.LoadBasket(t, db, tt.basket.ID).Status.Name; got != tt.want {
+			t.Errorf("basket %q status = %q, want %q", tt.basket.Name, got, tt.want)
+		}
+	}
+
+	expired := map[uint]bool{}
+	for _, event := range received(sub) {
+		data := event.Data.(BasketEvent)
+		if event.Type != BasketEventExpired || data.Available {
+			t.Errorf("event %s for basket %d (available %v), want unavailable %s", event.Type, data.BasketID, data.Available, BasketEventExpired)
+		}
+		expired[data.BasketID] = true
+	}
+	if len(expired) != 2 || !expired[pastExpiration.ID] || !expired[pastPickup.ID] {
+		t.Errorf("expired events for baskets %v, want %d and %d", expired, pastExpiration.ID, pastPickup.ID)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := utils.FixedClock{Time: expiryNow}
+	ts := newTestServices(db, clock)
+	expiry := newTestExpiryService(db, ts, clock)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+	customer := testutil.CreateUser(t, db, "customer@example.com")
+
+	basket := testutil.CreateBasket(t, db, store, "Panier", models.BasketStatusReserved, nil)
+	order := testutil.CreateOrder(t, db, basket, customer, models.OrderPending, expiryNow.Add(-time.Second))
+	invitation := &models.Invitation{
+		StoreID:   store.ID,
+		SenderID:  customer.ID,
+		Email:     "staff@example.com",
+		Code:      "ABC123",
+		Token:     "token",
+		Status:    models.InvitationPending,
+		ExpiresAt: expiryNow.Add(-time.Minute),
+	}
+	if err := db.Omit("Store", "Sender").Create(invitation).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := expiry.Sweep(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testutil.OrderStatus(t, db, order.ID); got != models.OrderCancelled {
+		t.Errorf("order status = %q, want %q", got, models.OrderCancelled)
+	}
+	if err := db.First(invitation, invitation.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if invitation.Status != models.InvitationExpired {
+		t.Errorf("invitation status = %q, want %q", invitation.Status, models.InvitationExpired)
+	}
+
+	// Un second passage à la même heure ne trouve plus rien à expirer
+	orders, err := expiry.ExpireOrders()
+	if err != nil || orders != 0 {
+		t.Errorf("second ExpireOrders = %d, %v; want 0, nil", orders, err)
+	}
+}
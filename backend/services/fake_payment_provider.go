@@ -0,0 +1,156 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeDeclinedPaymentMethod est le moyen de paiement refusé par FakePaymentProvider
+const FakeDeclinedPaymentMethod = "pm_card_declined"
+
+// Préfixes des identifiants créés par FakePaymentProvider
+const (
+	fakeCustomerPrefix = "cus_fake_"
+	fakeIntentPrefix   = "pi_fake_"
+)
+
+// FakePaymentProvider est une implémentation en mémoire de PaymentProvider,
+// utile pour le développement local et les tests. Tous les paiements réussissent
+// sauf avec FakeDeclinedPaymentMethod.
+// Les identifiants étant enregistrés en base, ils doivent rester valables après un redémarrage :
+// les clients ne sont pas conservés (tout identifiant fictif est accepté) et une intention
+// inconnue portant un identifiant fictif est reprise comme en attente de paiement.
+type FakePaymentProvider struct {
+	mu       sync.Mutex
+	intents  map[string]*PaymentIntent
+	keys     map[string]string // Intention créée pour chaque clé d'idempotence
+	refunded map[string]bool
+}
+
+func NewFakePaymentProvider() *FakePaymentProvider {
+	return &FakePaymentProvider{
+		intents:  make(map[string]*PaymentIntent),
+		keys:     make(map[string]string),
+		refunded: make(map[string]bool),
+	}
+}
+
+func (p *FakePaymentProvider) CreateCustomer(email string) (string, error) {
+	return newFakeID(fakeCustomerPrefix)
+}
+
+func (p *FakePaymentProvider) AttachPaymentMethod(customerID, paymentMethodID string) error {
+	if !strings.HasPrefix(customerID, fakeCustomerPrefix) {
+		return errors.New("unknown customer")
+	}
+	return nil
+}
+
+func (p *FakePaymentProvider) CreatePaymentIntent(amount int64, currency, customerID, idempotencyKey string, metadata map[string]string) (*PaymentIntent, error) {
+	id, err := newFakeID(fakeIntentPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if existing, ok := p.keys[idempotencyKey]; ok && idempotencyKey != "" {
+		copied := *p.intents[existing]
+		return &copied, nil
+	}
+
+	intent := &PaymentIntent{
+		ID:           id,
+		Amount:       amount,
+		Currency:     currency,
+		CustomerID:   customerID,
+		Status:       PaymentIntentRequiresPaymentMethod,
+		ClientSecret: id + "_secret",
+	}
+	p.intents[id] = intent
+	if idempotencyKey != "" {
+		p.keys[idempotencyKey] = id
+	}
+
+	copied := *intent
+	return &copied, nil
+}
+
+func (p *FakePaymentProvider) ConfirmPaymentIntent(intentID, paymentMethodID string) (*PaymentIntent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intent(intentID)
+	if !ok {
+		return nil, errors.New("unknown payment intent")
+	}
+	if paymentMethodID == FakeDeclinedPaymentMethod {
+		intent.Status = PaymentIntentRequiresPaymentMethod
+		return nil, fmt.Errorf("%w: your card was declined", ErrPaymentDeclined)
+	}
+
+	intent.Status = PaymentIntentSucceeded
+	copied := *intent
+	return &copied, nil
+}
+
+func (p *FakePaymentProvider) RefundPaymentIntent(intentID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intent(intentID)
+	if !ok {
+		return errors.New("unknown payment intent")
+	}
+	if intent.Status != PaymentIntentSucceeded {
+		return errors.New("payment intent has not succeeded")
+	}
+	if p.refunded[intentID] {
+		return ErrPaymentAlreadyRefunded
+	}
+	p.refunded[intentID] = true
+	return nil
+}
+
+// Refunded indique si l'intention de paiement a été remboursée
+func (p *FakePaymentProvider) Refunded(intentID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.refunded[intentID]
+}
+
+// intent retourne l'intention de paiement. Une intention fictive créée avant un redémarrage
+// est reprise en attente de paiement ; p.mu doit être verrouillé.
+func (p *FakePaymentProvider) intent(intentID string) (*PaymentIntent, bool) {
+	intent, ok := p.intents[intentID]
+	if ok {
+		return intent, true
+	}
+	if !strings.HasPrefix(intentID, fakeIntentPrefix) {
+		return nil, false
+	}
+	intent = &PaymentIntent{
+		ID:           intentID,
+		Currency:     paymentCurrency,
+		Status:       PaymentIntentRequiresPaymentMethod,
+		ClientSecret: intentID + "_secret",
+	}
+	p.intents[intentID] = intent
+	return intent, true
+}
+
+// newFakeID retourne un identifiant aléatoire, pour ne pas réutiliser après un redémarrage
+// un identifiant déjà enregistré en base
+func newFakeID(prefix string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
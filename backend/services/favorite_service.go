@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"gorm.io/gorm"
+)
+
+// FavoriteStore est un magasin favori avec ses paniers actuellement réservables
+type FavoriteStore struct {
+	Store   models.Store
+	Baskets []models.Basket
+}
+
+type FavoriteService struct {
+	favoriteRepo *repositories.FavoriteRepository
+	storeRepo    *repositories.StoreRepository
+}
+
+func NewFavoriteService(favoriteRepo *repositories.FavoriteRepository, storeRepo *repositories.StoreRepository) *FavoriteService {
+	return &FavoriteService{favoriteRepo: favoriteRepo, storeRepo: storeRepo}
+}
+
+// AddFavorite ajoute le magasin aux favoris de l'utilisateur
+func (s *FavoriteService) AddFavorite(userID, storeID uint) error {
+	if _, err := s.storeRepo.GetStoreByID(storeID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrStoreNotFound
+		}
+		return err
+	}
+	return s.favoriteRepo.Add(userID, storeID)
+}
+
+// RemoveFavorite retire le magasin des favoris de l'utilisateur
+func (s *FavoriteService) RemoveFavorite(userID, storeID uint) error {
+	return s.favoriteRepo.Remove(userID, storeID)
+}
+
+// GetFavorites retourne les magasins favoris de l'utilisateur avec leurs paniers disponibles
+func (s *FavoriteService) GetFavorites(userID uint) ([]FavoriteStore, error) {
+	stores, err := s.favoriteRepo.GetStores(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	storeIDs := make([]uint, 0, len(stores))
+	for _, store := range stores {
+		storeIDs = append(storeIDs, store.ID)
+	}
+	baskets, err := s.favoriteRepo.GetAvailableBaskets(storeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byStore := map[uint][]models.Basket{}
+	for _, basket := range baskets {
+		byStore[uint(basket.StoreID)] = append(byStore[uint(basket.StoreID)], basket)
+	}
+
+	favorites := make([]FavoriteStore, 0, len(stores))
+	for _, store := range stores {
+		store.IsFavorite = true
+		favorites = append(favorites, FavoriteStore{Store: store, Baskets: byStore[store.ID]})
+	}
+	return favorites, nil
+}
+
+// FavoriteStoreIDs retourne l'ensemble des magasins favoris de l'utilisateur
+func (s *FavoriteService) FavoriteStoreIDs(userID uint) (map[uint]bool, error) {
+	ids, err := s.favoriteRepo.StoreIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	favorites := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		favorites[id] = true
+	}
+	return favorites, nil
+}
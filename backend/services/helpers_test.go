@@ -0,0 +1,118 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
+	"github.com/Sebiche09/app-anti-gaspillage.git/events"
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+)
+
+// testServices regroupe les services partagés par les tests, construits sur la même base
+type testServices struct {
+	mailbox       *mailer.Mailbox
+	mail          *MailService
+	notifications *NotificationService
+	basketBroker  *events.Broker
+	orderBroker   *events.Broker
+	basketEvents  *BasketEvents
+	orderEvents   *OrderEvents
+}
+
+// newTestServices construit les services de notification et d'événements ; les emails
+// expédiés par l'outbox arrivent dans mailbox
+func newTestServices(db *gorm.DB, clock utils.Clock) *testServices {
+	mailbox := mailer.NewMailbox()
+	mail := NewMailService(repositories.NewEmailOutboxRepository(db), mailer.NewRenderer(), mailbox, "no-reply@test", clock)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	notifications := NewNotificationService(
+		repositories.NewFavoriteRepository(db),
+		notificationRepo,
+		repositories.NewStoreRepository(db),
+		repositories.NewUserRepository(db),
+		[]Notifier{NewInAppNotifier(notificationRepo), NewEmailNotifier(mail)},
+		mail,
+		clock,
+		DefaultBasketAlertInterval,
+	)
+	basketBroker := events.NewBroker(64, 0)
+	orderBroker := events.NewBroker(64, 0)
+	return &testServices{
+		mailbox:       mailbox,
+		mail:          mail,
+		notifications: notifications,
+		basketBroker:  basketBroker,
+		orderBroker:   orderBroker,
+		basketEvents:  NewBasketEvents(basketBroker, repositories.NewBasketRepository(db)),
+		orderEvents:   NewOrderEvents(orderBroker, repositories.NewOrderRepository(db)),
+	}
+}
+
+// subscribe abonne le test à tous les événements du broker ; l'abonnement est libéré à la fin du test
+func subscribe(t *testing.T, broker *events.Broker) *events.Subscription {
+	t.Helper()
+	sub := broker.Subscribe(nil)
+	t.Cleanup(sub.Close)
+	return sub
+}
+
+// received retourne les événements déjà reçus par l'abonnement, sans attendre
+func received(sub *events.Subscription) []events.Event {
+	var received []events.Event
+	for {
+		select {
+		case event := <-sub.Events():
+			received = append(received, event)
+		default:
+			return received
+		}
+	}
+}
+
+// newTestExpiryService construit le service d'expiration sur les services de test
+func newTestExpiryService(db *gorm.DB, ts *testServices, clock utils.Clock) *ExpiryService {
+	return NewExpiryService(
+		repositories.NewOrderRepository(db),
+		repositories.NewBasketRepository(db),
+		repositories.NewInvitationRepository(db),
+		ts.notifications,
+		ts.basketEvents,
+		ts.orderEvents,
+		clock,
+	)
+}
+
+// newTestOrderService construit le service des commandes avec le prestataire de paiement donné
+func newTestOrderService(db *gorm.DB, ts *testServices, payments PaymentProvider, clock utils.Clock) *OrderService {
+	return NewOrderService(
+		repositories.NewOrderRepository(db),
+		repositories.NewStripeCustomerRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewPaymentEventRepository(db),
+		payments,
+		NewRefundService(repositories.NewPaymentRefundRepository(db), payments, clock),
+		ts.notifications,
+		ts.basketEvents,
+		ts.orderEvents,
+	)
+}
+
+// newTestInvitationService construit le service des invitations ; les liens pointent vers https://app.example.com
+func newTestInvitationService(db *gorm.DB, ts *testServices) *InvitationService {
+	userRepo := repositories.NewUserRepository(db)
+	storeRepo := repositories.NewStoreRepository(db)
+	staffRepo := repositories.NewStoreStaffRepository(db)
+	return NewInvitationService(
+		repositories.NewInvitationRepository(db),
+		storeRepo,
+		staffRepo,
+		userRepo,
+		authz.NewPolicy(userRepo, storeRepo, repositories.NewMerchantRepository(db), staffRepo),
+		ts.mail,
+		ts.notifications,
+		"https://app.example.com",
+	)
+}
@@ -1,145 +1,304 @@
 package services
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
 )
 
-type EmailService interface {
-	SendInvitationEmail(email, invitationURL string) error
-}
+// invitationValidity est la durée de validité d'une invitation, prolongée à chaque renvoi
+const invitationValidity = 7 * 24 * time.Hour
+
+var (
+	ErrInvitationExpired       = errors.New("invitation has expired")
+	ErrInvitationEmailMismatch = errors.New("this invitation was sent to another email address")
+	ErrInvitationExists        = errors.New("an invitation is already pending for this email")
+	ErrInvalidInvitationToken  = errors.New("invalid invitation token")
+	ErrAlreadyStaffMember      = errors.New("you are already a staff member of this store")
+)
 
 type InvitationService struct {
 	invitationRepo *repositories.InvitationRepository
 	storeRepo      *repositories.StoreRepository
-	merchantRepo   *repositories.MerchantRepository
 	staffRepo      *repositories.StoreStaffRepository
-	emailService   EmailService
+	userRepo       *repositories.UserRepository
+	policy         *authz.Policy
+	mail           *MailService
+	notifications  *NotificationService
+	appURL         string
 }
 
+// NewInvitationService construit le service des invitations ; appURL est l'adresse de l'application
+// web vers laquelle pointent les liens envoyés par email
 func NewInvitationService(
 	invitationRepo *repositories.InvitationRepository,
 	storeRepo *repositories.StoreRepository,
-	merchantRepo *repositories.MerchantRepository,
 	staffRepo *repositories.StoreStaffRepository,
-	emailService EmailService,
+	userRepo *repositories.UserRepository,
+	policy *authz.Policy,
+	mail *MailService,
+	notifications *NotificationService,
+	appURL string,
 ) *InvitationService {
 	return &InvitationService{
 		invitationRepo: invitationRepo,
 		storeRepo:      storeRepo,
-		merchantRepo:   merchantRepo,
 		staffRepo:      staffRepo,
-		emailService:   emailService,
+		userRepo:       userRepo,
+		policy:         policy,
+		mail:           mail,
+		notifications:  notifications,
+		appURL:         strings.TrimRight(appURL, "/"),
 	}
 }
 
-// generateInviteCode génère un code unique pour l'invitation
-func generateInviteCode() string {
-	b := make([]byte, 6)
-	rand.Read(b)
-	return hex.EncodeToString(b)
-}
-
+// CreateInvitation invite l'email à rejoindre l'équipe du magasin ; réservé aux utilisateurs
+// autorisés à gérer l'équipe (commerçant propriétaire, responsables)
 func (s *InvitationService) CreateInvitation(senderID, storeID uint, email string) (*models.Invitation, error) {
+	if _, err := s.policy.Authorize(senderID, storeID, authz.PermissionStaffManage); err != nil {
+		return nil, err
+	}
 	store, err := s.storeRepo.GetStoreByID(storeID)
 	if err != nil {
 		return nil, err
 	}
 
-	merchant, err := s.merchantRepo.FindMerchantByUserID(senderID)
-	if err != nil || store.MerchantID != merchant.ID {
-		return nil, errors.New("unauthorized: you don't own this store")
+	email = strings.ToLower(strings.TrimSpace(email))
+	exists, err := s.invitationRepo.HasPendingInvitation(storeID, email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrInvitationExists
 	}
-
-	code := generateInviteCode()
 
 	invitation := &models.Invitation{
-		StoreID:   storeID,
-		SenderID:  senderID,
-		Email:     email,
-		Code:      code,
-		Status:    models.InvitationPending,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+		StoreID:  storeID,
+		SenderID: senderID,
+		Email:    email,
+		Status:   models.InvitationPending,
+		Store:    *store,
+	}
+	if err := s.renew(invitation); err != nil {
+		return nil, err
 	}
 
-	if err := s.invitationRepo.CreateInvitation(invitation); err != nil {
+	err = s.save(invitation, store, func(invitations *repositories.InvitationRepository) error {
+		return invitations.CreateInvitation(invitation)
+	})
+	if err != nil {
 		return nil, err
 	}
+	return invitation, nil
+}
 
-	invitationURL := "http://localhost:3000//invitations/accept?code=" + code
-	err = s.emailService.SendInvitationEmail(email, invitationURL)
+// ResendInvitation renvoie une invitation en attente ou expirée avec un nouveau code et une validité prolongée ;
+// les liens envoyés précédemment ne sont plus valables
+func (s *InvitationService) ResendInvitation(invitationID, userID uint) (*models.Invitation, error) {
+	invitation, err := s.invitationRepo.GetInvitationByID(invitationID)
 	if err != nil {
+		return nil, err
+	}
+	if _, err := s.policy.Authorize(userID, invitation.StoreID, authz.PermissionStaffManage); err != nil {
+		return nil, err
+	}
+	if invitation.Status != models.InvitationPending && invitation.Status != models.InvitationExpired {
+		return nil, repositories.ErrInvitationNotPending
+	}
+	// Une invitation expirée n'est relancée que si aucune autre n'a été envoyée depuis à la même adresse
+	if invitation.Status == models.InvitationExpired {
+		exists, err := s.invitationRepo.HasPendingInvitation(invitation.StoreID, invitation.Email)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrInvitationExists
+		}
 	}
 
+	invitation.Status = models.InvitationPending
+	if err := s.renew(invitation); err != nil {
+		return nil, err
+	}
+
+	err = s.save(invitation, &invitation.Store, func(invitations *repositories.InvitationRepository) error {
+		return invitations.UpdateInvitation(invitation)
+	})
+	if err != nil {
+		return nil, err
+	}
 	return invitation, nil
 }
 
-func (s *InvitationService) AcceptInvitation(code string, userID uint) error {
+// renew attribue à l'invitation un nouveau code, une nouvelle échéance et le lien signé correspondant
+func (s *InvitationService) renew(invitation *models.Invitation) error {
+	invitation.Code = utils.GenerateUniqueInviteCode()
+	invitation.ExpiresAt = time.Now().Add(invitationValidity)
+
+	token, err := utils.GenerateInvitationToken(invitation.StoreID, invitation.Email, invitation.Code, invitation.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	invitation.Token = token
+	return nil
+}
+
+// save enregistre l'invitation avec write et met en file l'email de son lien dans la même transaction :
+// une invitation n'est jamais enregistrée sans son email. L'invité est ensuite prévenu dans l'application
+// s'il a un compte.
+func (s *InvitationService) save(invitation *models.Invitation, store *models.Store, write func(invitations *repositories.InvitationRepository) error) error {
+	err := s.invitationRepo.Transaction(func(tx *gorm.DB) error {
+		if err := write(s.invitationRepo.WithTx(tx)); err != nil {
+			return err
+		}
+		return s.mail.WithTx(tx).Enqueue(invitation.Email, mailer.TemplateInvitation, mailer.InvitationData{
+			StoreName: store.Name,
+			URL:       s.appURL + "/invitations/accept?token=" + url.QueryEscape(invitation.Token),
+			Code:      invitation.Code,
+			ExpiresOn: invitation.ExpiresAt.In(store.Location()).Format("02/01/2006"),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifications.NotifyInvitation(invitation, store)
+	return nil
+}
+
+// AcceptInvitationByToken accepte l'invitation du lien signé reçu par email
+func (s *InvitationService) AcceptInvitationByToken(token string, userID uint) error {
+	storeID, email, code, err := utils.VerifyInvitationToken(token)
+	if err != nil {
+		return ErrInvalidInvitationToken
+	}
+
 	invitation, err := s.invitationRepo.GetInvitationByCode(code)
 	if err != nil {
+		if errors.Is(err, repositories.ErrInvitationNotFound) {
+			return ErrInvalidInvitationToken
+		}
 		return err
 	}
+	if invitation.StoreID != storeID || !strings.EqualFold(invitation.Email, email) {
+		return ErrInvalidInvitationToken
+	}
+	return s.accept(invitation, userID)
+}
 
-	if invitation.Status != models.InvitationPending {
-		return errors.New("invitation is no longer valid")
+// AcceptInvitationByCode accepte l'invitation correspondant au code
+func (s *InvitationService) AcceptInvitationByCode(code string, userID uint) error {
+	invitation, err := s.invitationRepo.GetInvitationByCode(code)
+	if err != nil {
+		return err
 	}
+	return s.accept(invitation, userID)
+}
 
-	if invitation.ExpiresAt.Before(time.Now()) {
-		invitation.Status = models.InvitationExpired
-		s.invitationRepo.UpdateInvitation(invitation)
-		return errors.New("invitation has expired")
+// AcceptInvitation accepte une invitation reçue par l'utilisateur
+func (s *InvitationService) AcceptInvitation(invitationID, userID uint) error {
+	invitation, err := s.invitationRepo.GetInvitationByID(invitationID)
+	if err != nil {
+		return err
+	}
+	return s.accept(invitation, userID)
+}
+
+// accept ajoute l'utilisateur à l'équipe du magasin si l'invitation lui est adressée et toujours valable
+func (s *InvitationService) accept(invitation *models.Invitation, userID uint) error {
+	if err := s.checkInvitee(invitation, userID); err != nil {
+		return err
+	}
+
+	isMember, err := s.staffRepo.IsUserStaffMember(invitation.StoreID, userID)
+	if err != nil {
+		return err
 	}
-	isMember, _ := s.staffRepo.IsUserStaffMember(invitation.StoreID, userID)
 	if isMember {
-		return errors.New("you are already a staff member of this store")
+		return ErrAlreadyStaffMember
 	}
 
 	staff := &models.StoreStaff{
 		StoreID: invitation.StoreID,
 		UserID:  userID,
+		Role:    models.StaffRoleClerk,
 	}
+	return s.invitationRepo.Accept(invitation.ID, staff, time.Now())
+}
 
-	if err := s.staffRepo.AddStaffMember(staff); err != nil {
+// DeclineInvitation refuse une invitation reçue par l'utilisateur
+func (s *InvitationService) DeclineInvitation(invitationID, userID uint) error {
+	invitation, err := s.invitationRepo.GetInvitationByID(invitationID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkInvitee(invitation, userID); err != nil {
 		return err
 	}
 
-	invitation.Status = models.InvitationAccepted
+	invitation.Status = models.InvitationDeclined
 	return s.invitationRepo.UpdateInvitation(invitation)
 }
 
-func (s *InvitationService) GetPendingInvitations(storeID uint, userID uint) ([]models.Invitation, error) {
-	merchant, err := s.merchantRepo.FindMerchantByUserID(userID)
+// checkInvitee vérifie que l'invitation est adressée à l'email de l'utilisateur et toujours en attente
+func (s *InvitationService) checkInvitee(invitation *models.Invitation, userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return ErrInvitationEmailMismatch
 	}
 
-	store, err := s.storeRepo.GetStoreByID(storeID)
+	if invitation.Status != models.InvitationPending {
+		return repositories.ErrInvitationNotPending
+	}
+	if invitation.ExpiresAt.Before(time.Now()) {
+		invitation.Status = models.InvitationExpired
+		s.invitationRepo.UpdateInvitation(invitation)
+		return ErrInvitationExpired
+	}
+	return nil
+}
+
+// GetMyInvitations retourne les invitations en attente adressées à l'email de l'utilisateur
+func (s *InvitationService) GetMyInvitations(userID uint) ([]models.Invitation, error) {
+	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return nil, err
 	}
+	return s.invitationRepo.GetPendingInvitationsByEmail(user.Email, time.Now())
+}
 
-	if store.MerchantID != merchant.ID {
-		return nil, errors.New("unauthorized: you don't have access to this store")
+func (s *InvitationService) GetPendingInvitations(storeID uint, userID uint) ([]models.Invitation, error) {
+	if _, err := s.policy.Authorize(userID, storeID, authz.PermissionStaffManage); err != nil {
+		return nil, err
 	}
 
 	return s.invitationRepo.GetPendingInvitationsByStore(storeID)
 }
 
+// CancelInvitation annule une invitation en attente du magasin
 func (s *InvitationService) CancelInvitation(invitationID, userID uint) error {
 	invitation, err := s.invitationRepo.GetInvitationByID(invitationID)
 	if err != nil {
 		return err
 	}
-
-	if invitation.SenderID != userID {
-		return errors.New("only the sender can cancel the invitation")
+	if _, err := s.policy.Authorize(userID, invitation.StoreID, authz.PermissionStaffManage); err != nil {
+		return err
+	}
+	if invitation.Status != models.InvitationPending {
+		return repositories.ErrInvitationNotPending
 	}
 
-	invitation.Status = models.InvitationRejected
+	invitation.Status = models.InvitationCanceled
 	return s.invitationRepo.UpdateInvitation(invitation)
 }
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+func TestResendExpiredInvitation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	db := testutil.OpenDB(t)
+	invitations := newTestInvitationService(db, newTestServices(db, utils.SystemClock{}))
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+
+	expired := &models.Invitation{
+		StoreID:   store.ID,
+		SenderID:  owner.ID,
+		Email:     "staff@example.com",
+		Code:      "ABC123",
+		Token:     "token",
+		Status:    models.InvitationExpired,
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}
+	if err := db.Omit("Store", "Sender").Create(expired).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Une nouvelle invitation a été envoyée depuis à la même adresse : l'ancienne n'est pas relancée
+	pending, err := invitations.CreateInvitation(owner.ID, store.ID, "staff@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := invitations.ResendInvitation(expired.ID, owner.ID); !errors.Is(err, ErrInvitationExists) {
+		t.Fatalf("resend with a pending invitation: err = %v, want %v", err, ErrInvitationExists)
+	}
+	if err := db.First(expired, expired.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if expired.Status != models.InvitationExpired || expired.Code != "ABC123" {
+		t.Errorf("expired invitation = status %q, code %q; want unchanged", expired.Status, expired.Code)
+	}
+
+	// Une fois l'autre invitation annulée, l'invitation expirée peut être relancée
+	if err := db.Model(pending).Update("status", models.InvitationCanceled).Error; err != nil {
+		t.Fatal(err)
+	}
+	resent, err := invitations.ResendInvitation(expired.ID, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resent.Status != models.InvitationPending || resent.Code == "ABC123" || !resent.ExpiresAt.After(time.Now()) {
+		t.Errorf("resent invitation = status %q, code %q, expires %v; want pending with a new code", resent.Status, resent.Code, resent.ExpiresAt)
+	}
+}
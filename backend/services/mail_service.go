@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// mailBatchSize est le nombre maximal d'emails envoyés à chaque passage du scheduler
+	mailBatchSize = 50
+	// mailLease est le délai après lequel un email réservé mais non confirmé est retenté
+	mailLease = 5 * time.Minute
+	// mailMaxAttempts est le nombre de tentatives avant d'abandonner un email
+	mailMaxAttempts = 8
+	// mailRetryBase et mailRetryMax bornent l'attente entre deux tentatives, doublée à chaque échec
+	mailRetryBase = 30 * time.Second
+	mailRetryMax  = 6 * time.Hour
+	// mailRetention est la durée pendant laquelle un email envoyé reste dans l'outbox
+	mailRetention = 30 * 24 * time.Hour
+)
+
+// MailService rend les emails transactionnels et les met dans l'outbox ; Dispatch les envoie ensuite
+// en arrière-plan avec le transport configuré, en retentant les échecs avec un délai croissant
+type MailService struct {
+	outboxRepo *repositories.EmailOutboxRepository
+	renderer   *mailer.Renderer
+	transport  mailer.Transport
+	from       string
+	clock      utils.Clock
+}
+
+func NewMailService(
+	outboxRepo *repositories.EmailOutboxRepository,
+	renderer *mailer.Renderer,
+	transport mailer.Transport,
+	from string,
+	clock utils.Clock,
+) *MailService {
+	return &MailService{
+		outboxRepo: outboxRepo,
+		renderer:   renderer,
+		transport:  transport,
+		from:       from,
+		clock:      clock,
+	}
+}
+
+// WithTx retourne un MailService dont les emails sont mis en file dans la transaction tx :
+// ils ne partent que si la transaction est validée
+func (s *MailService) WithTx(tx *gorm.DB) *MailService {
+	scoped := *s
+	scoped.outboxRepo = s.outboxRepo.WithTx(tx)
+	return &scoped
+}
+
+// Enqueue rend le modèle template pour le destinataire to et met l'email en file d'attente
+func (s *MailService) Enqueue(to, template string, data interface{}) error {
+	message, err := s.renderer.Render(template, to, data)
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.Enqueue(&models.OutboxEmail{
+		Recipient:     message.To,
+		Template:      template,
+		Subject:       message.Subject,
+		HTMLBody:      message.HTML,
+		TextBody:      message.Text,
+		Status:        models.EmailPending,
+		NextAttemptAt: s.clock.Now(),
+	})
+}
+
+// Dispatch envoie les emails dus de l'outbox ; destiné au scheduler.
+// Un échec d'envoi est retenté plus tard et n'interrompt pas le traitement des autres emails.
+func (s *MailService) Dispatch(ctx context.Context) error {
+	emails, err := s.outboxRepo.ClaimDue(s.clock.Now(), mailBatchSize, mailLease)
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for i := range emails {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if s.deliver(ctx, &emails[i]) {
+			sent++
+		}
+	}
+
+	if len(emails) > 0 {
+		log.Printf("mail: %d/%d email(s) sent", sent, len(emails))
+	}
+	return nil
+}
+
+// Purge supprime de l'outbox les emails envoyés depuis plus de mailRetention ; destiné au scheduler
+func (s *MailService) Purge(ctx context.Context) error {
+	deleted, err := s.outboxRepo.DeleteSentBefore(s.clock.Now().Add(-mailRetention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("mail: %d sent email(s) purged from the outbox", deleted)
+	}
+	return nil
+}
+
+// deliver envoie un email réservé et enregistre le résultat de la tentative
+func (s *MailService) deliver(ctx context.Context, email *models.OutboxEmail) bool {
+	err := s.transport.Send(ctx, &mailer.Message{
+		From:    s.from,
+		To:      email.Recipient,
+		Subject: email.Subject,
+		HTML:    email.HTMLBody,
+		Text:    email.TextBody,
+	})
+
+	now := s.clock.Now()
+	if err == nil {
+		if err := s.outboxRepo.MarkSent(email.ID, now); err != nil {
+			log.Printf("mail: email %d sent but not marked: %v", email.ID, err)
+		}
+		return true
+	}
+
+	if email.Attempts >= mailMaxAttempts {
+		log.Printf("mail: email %d to %s abandoned after %d attempts: %v", email.ID, email.Recipient, email.Attempts, err)
+		err = s.outboxRepo.MarkFailed(email.ID, err.Error())
+	} else {
+		log.Printf("mail: email %d to %s, attempt %d: %v", email.ID, email.Recipient, email.Attempts, err)
+		err = s.outboxRepo.MarkRetry(email.ID, now.Add(utils.Backoff(mailRetryBase, mailRetryMax, email.Attempts)), err.Error())
+	}
+	if err != nil {
+		log.Printf("mail: email %d: %v", email.ID, err)
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+)
+
+// flakyTransport échoue tant que down est vrai, puis remet les messages à la boîte
+type flakyTransport struct {
+	*mailer.Mailbox
+	down bool
+}
+
+func (t *flakyTransport) Send(ctx context.Context, message *mailer.Message) error {
+	if t.down {
+		return errors.New("smtp unavailable")
+	}
+	return t.Mailbox.Send(ctx, message)
+}
+
+func newTestMailService(db *gorm.DB, transport mailer.Transport, clock utils.Clock) *MailService {
+	return NewMailService(repositories.NewEmailOutboxRepository(db), mailer.NewRenderer(), transport, "no-reply@test", clock)
+}
+
+func outboxEmails(t *testing.T, db *gorm.DB) []models.OutboxEmail {
+	t.Helper()
+	var emails []models.OutboxEmail
+	if err := db.Order("id").Find(&emails).Error; err != nil {
+		t.Fatal(err)
+	}
+	return emails
+}
+
+func TestMailDispatch(t *testing.T) {
+	db := testutil.OpenDB(t)
+	mailbox := mailer.NewMailbox()
+	mail := newTestMailService(db, mailbox, utils.FixedClock{Time: time.Now()})
+
+	for _, to := range []string{"a@example.com", "b@example.com"} {
+		if err := mail.Enqueue(to, mailer.TemplateEmailChanged, mailer.EmailChangedData{NewEmail: "new@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(mailbox.Messages()) != 0 {
+		t.Fatal("emails sent before Dispatch")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := mail.Dispatch(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := len(mailbox.Messages()); got != 2 {
+		t.Errorf("sent %d emails, want 2 (each once)", got)
+	}
+	if messages := mailbox.To("a@example.com"); len(messages) != 1 || messages[0].From != "no-reply@test" {
+		t.Errorf("messages to a@example.com = %+v", messages)
+	}
+	for _, email := range outboxEmails(t, db) {
+		if email.Status != models.EmailSent || email.SentAt == nil || email.Attempts != 1 {
+			t.Errorf("email %d = %s after %d attempts, want sent after 1", email.ID, email.Status, email.Attempts)
+		}
+		if email.HTMLBody != "" || email.TextBody != "" {
+			t.Errorf("email %d keeps its rendered body after sending", email.ID)
+		}
+	}
+}
+
+func TestMailDispatchRetries(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := &utils.FixedClock{Time: time.Now()}
+	transport := &flakyTransport{Mailbox: mailer.NewMailbox(), down: true}
+	mail := newTestMailService(db, transport, clock)
+
+	if err := mail.Enqueue("a@example.com", mailer.TemplateEmailChanged, mailer.EmailChangedData{NewEmail: "new@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Chaque échec repousse la tentative suivante d'un délai qui double
+	for attempt := 1; attempt < mailMaxAttempts; attempt++ {
+		if err := mail.Dispatch(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		email := outboxEmails(t, db)[0]
+		wantNext := clock.Time.Add(utils.Backoff(mailRetryBase, mailRetryMax, attempt))
+		if email.Status != models.EmailPending || email.Attempts != attempt || !email.NextAttemptAt.Equal(wantNext) || email.LastError == "" {
+			t.Fatalf("attempt %d: email = %s, %d attempts, next %v; want pending, next %v", attempt, email.Status, email.Attempts, email.NextAttemptAt, wantNext)
+		}
+		if attempt == 3 {
+			transport.down = false
+			break
+		}
+		clock.Time = email.NextAttemptAt
+	}
+
+	// Pas de nouvelle tentative avant l'échéance
+	if err := mail.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(transport.Messages()) != 0 {
+		t.Fatal("email retried before its next attempt")
+	}
+
+	clock.Time = outboxEmails(t, db)[0].NextAttemptAt
+	if err := mail.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if email := outboxEmails(t, db)[0]; email.Status != models.EmailSent || len(transport.Messages()) != 1 {
+		t.Errorf("email = %s with %d message(s) sent, want sent once", email.Status, len(transport.Messages()))
+	}
+}
+
+func TestMailDispatchGivesUp(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := &utils.FixedClock{Time: time.Now()}
+	mail := newTestMailService(db, &flakyTransport{Mailbox: mailer.NewMailbox(), down: true}, clock)
+
+	if err := mail.Enqueue("a@example.com", mailer.TemplateEmailChanged, mailer.EmailChangedData{NewEmail: "new@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	for attempt := 0; attempt < mailMaxAttempts; attempt++ {
+		if err := mail.Dispatch(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		clock.Time = clock.Time.Add(mailRetryMax)
+	}
+
+	email := outboxEmails(t, db)[0]
+	if email.Status != models.EmailFailed || email.Attempts != mailMaxAttempts {
+		t.Errorf("email = %s after %d attempts, want failed after %d", email.Status, email.Attempts, mailMaxAttempts)
+	}
+	if email.HTMLBody != "" || email.TextBody != "" {
+		t.Error("abandoned email keeps its rendered body")
+	}
+}
+
+func TestMailWithTx(t *testing.T) {
+	db := testutil.OpenDB(t)
+	mail := newTestMailService(db, mailer.NewMailbox(), utils.SystemClock{})
+	data := mailer.EmailChangedData{NewEmail: "new@example.com"}
+
+	// Un email mis en file dans une transaction annulée n'est jamais envoyé
+	rollback := errors.New("rollback")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := mail.WithTx(tx).Enqueue("rolled-back@example.com", mailer.TemplateEmailChanged, data); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("err = %v, want %v", err, rollback)
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return mail.WithTx(tx).Enqueue("committed@example.com", mailer.TemplateEmailChanged, data)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emails := outboxEmails(t, db)
+	if len(emails) != 1 || emails[0].Recipient != "committed@example.com" {
+		t.Errorf("outbox = %+v, want only the committed email", emails)
+	}
+}
+
+func TestCreateInvitationEnqueuesEmail(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	db := testutil.OpenDB(t)
+	ts := newTestServices(db, utils.SystemClock{})
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+
+	invitations := newTestInvitationService(db, ts)
+
+	invitation, err := invitations.CreateInvitation(owner.ID, store.ID, "Staff@Example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.mail.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := ts.mailbox.To("staff@example.com")
+	if len(messages) != 1 {
+		t.Fatalf("got %d invitation emails, want 1", len(messages))
+	}
+	if !strings.Contains(messages[0].Text, invitation.Code) || !strings.Contains(messages[0].Text, "https://app.example.com/invitations/accept?token=") {
+		t.Errorf("invitation email does not contain the code and link:\n%s", messages[0].Text)
+	}
+}
+
+func TestMailPurge(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := &utils.FixedClock{Time: time.Now()}
+	mail := newTestMailService(db, &flakyTransport{Mailbox: mailer.NewMailbox()}, clock)
+	data := mailer.EmailChangedData{NewEmail: "new@example.com"}
+
+	if err := mail.Enqueue("old@example.com", mailer.TemplateEmailChanged, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := mail.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	clock.Time = clock.Time.Add(mailRetention)
+	if err := mail.Enqueue("recent@example.com", mailer.TemplateEmailChanged, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := mail.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := mail.Enqueue("pending@example.com", mailer.TemplateEmailChanged, data); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Time = clock.Time.Add(time.Minute)
+	if err := mail.Purge(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var remaining []string
+	for _, email := range outboxEmails(t, db) {
+		remaining = append(remaining, email.Recipient)
+	}
+	if len(remaining) != 2 || remaining[0] != "recent@example.com" || remaining[1] != "pending@example.com" {
+		t.Errorf("outbox after purge = %v, want the recent and pending emails", remaining)
+	}
+	var total int64
+	db.Unscoped().Model(&models.OutboxEmail{}).Count(&total)
+	if total != 2 {
+		t.Errorf("outbox rows = %d, want 2 (purged emails deleted, not soft-deleted)", total)
+	}
+}
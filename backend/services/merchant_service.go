@@ -9,11 +9,12 @@ import (
 )
 
 type MerchantService struct {
-	repo *repositories.MerchantRepository
+	repo          *repositories.MerchantRepository
+	notifications *NotificationService
 }
 
-func NewMerchantService(repo *repositories.MerchantRepository) *MerchantService {
-	return &MerchantService{repo: repo}
+func NewMerchantService(repo *repositories.MerchantRepository, notifications *NotificationService) *MerchantService {
+	return &MerchantService{repo: repo, notifications: notifications}
 }
 
 // Vérifier le statut de la demande de marchand
@@ -78,7 +79,12 @@ func (s *MerchantService) ProcessRequest(requestID uint, status string) error {
 		}
 	}
 
-	return s.repo.UpdateRequest(request)
+	if err := s.repo.UpdateRequest(request); err != nil {
+		return err
+	}
+
+	s.notifications.NotifyMerchantRequestProcessed(request)
+	return nil
 }
 
 // Récupérer les informations du marchand
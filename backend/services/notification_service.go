@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+var ErrInvalidNotificationPage = errors.New("page and page_size must be positive")
+
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
+// DefaultBasketAlertInterval est l'intervalle minimal entre deux alertes de nouveau panier pour un même utilisateur
+const DefaultBasketAlertInterval = 30 * time.Minute
+
+// notificationTimeout borne la diffusion d'une notification lancée en arrière-plan
+const notificationTimeout = 2 * time.Minute
+
+// NotificationPage est une page de la boîte de réception d'un utilisateur
+type NotificationPage struct {
+	Notifications []models.Notification
+	Total         int64
+	Unread        int64
+	Page          int
+	PageSize      int
+}
+
+// NotificationService diffuse les notifications aux utilisateurs selon leurs préférences
+// et gère leur boîte de réception in-app
+type NotificationService struct {
+	favoriteRepo     *repositories.FavoriteRepository
+	notificationRepo *repositories.NotificationRepository
+	storeRepo        *repositories.StoreRepository
+	userRepo         *repositories.UserRepository
+	notifiers        []Notifier
+	mail             *MailService
+	clock            utils.Clock
+	alertInterval    time.Duration
+}
+
+func NewNotificationService(
+	favoriteRepo *repositories.FavoriteRepository,
+	notificationRepo *repositories.NotificationRepository,
+	storeRepo *repositories.StoreRepository,
+	userRepo *repositories.UserRepository,
+	notifiers []Notifier,
+	mail *MailService,
+	clock utils.Clock,
+	alertInterval time.Duration,
+) *NotificationService {
+	return &NotificationService{
+		favoriteRepo:     favoriteRepo,
+		notificationRepo: notificationRepo,
+		storeRepo:        storeRepo,
+		userRepo:         userRepo,
+		notifiers:        notifiers,
+		mail:             mail,
+		clock:            clock,
+		alertInterval:    alertInterval,
+	}
+}
+
+// NotifyNewBasketAsync prévient en arrière-plan les abonnés du magasin qu'un panier vient d'être publié,
+// pour ne pas retarder la requête du commerçant
+func (s *NotificationService) NotifyNewBasketAsync(basket *models.Basket) {
+	published := *basket
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+		if err := s.NotifyNewBasket(ctx, &published); err != nil {
+			log.Printf("notifications: basket %d: %v", published.ID, err)
+		}
+	}()
+}
+
+// NotifyNewBasket prévient les utilisateurs ayant mis le magasin en favori qu'un panier vient d'être publié.
+// Chaque utilisateur est alerté au plus une fois par intervalle, sur les canaux qu'il a activés ;
+// l'échec d'un canal n'empêche pas la diffusion sur les autres.
+func (s *NotificationService) NotifyNewBasket(ctx context.Context, basket *models.Basket) error {
+	if basket.Quantity <= 0 {
+		return nil
+	}
+
+	storeID := uint(basket.StoreID)
+	followers, err := s.favoriteRepo.GetFollowers(storeID)
+	if err != nil {
+		return err
+	}
+	if len(followers) == 0 {
+		return nil
+	}
+
+	store, err := s.storeRepo.GetStoreByID(storeID)
+	if err != nil {
+		return err
+	}
+
+	userIDs := make([]uint, 0, len(followers))
+	for _, follower := range followers {
+		userIDs = append(userIDs, follower.ID)
+	}
+	preferences, err := s.notificationRepo.GetPreferences(userIDs)
+	if err != nil {
+		return err
+	}
+
+	message := newBasketMessage(store, basket)
+	now := s.clock.Now()
+	notified := 0
+	for i := range followers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		follower := &followers[i]
+		preference := preferences[follower.ID]
+		if !preference.NewBaskets || !s.reachable(&preference) {
+			continue
+		}
+
+		reserved, err := s.notificationRepo.ReserveBasketAlert(follower.ID, now, s.alertInterval)
+		if err != nil {
+			log.Printf("notifications: user %d: %v", follower.ID, err)
+			continue
+		}
+		if !reserved {
+			continue
+		}
+
+		for _, notifier := range s.notifiers {
+			if !preference.Allows(notifier.Channel()) {
+				continue
+			}
+			if err := notifier.Notify(ctx, follower, message); err != nil {
+				log.Printf("notifications: %s to user %d: %v", notifier.Channel(), follower.ID, err)
+			}
+		}
+		notified++
+	}
+
+	if notified > 0 {
+		log.Printf("notifications: basket %d: %d follower(s) notified", basket.ID, notified)
+	}
+	return nil
+}
+
+// reachable indique si au moins un des canaux activés par l'utilisateur est disponible
+func (s *NotificationService) reachable(preference *models.NotificationPreference) bool {
+	for _, notifier := range s.notifiers {
+		if preference.Allows(notifier.Channel()) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPreferences retourne les préférences de notification de l'utilisateur
+func (s *NotificationService) GetPreferences(userID uint) (*models.NotificationPreference, error) {
+	return s.notificationRepo.GetPreference(userID)
+}
+
+// UpdatePreferences applique les champs renseignés aux préférences de notification de l'utilisateur
+func (s *NotificationService) UpdatePreferences(userID uint, newBaskets, email, push, inApp *bool) (*models.NotificationPreference, error) {
+	preference, err := s.notificationRepo.GetPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+	if newBaskets != nil {
+		preference.NewBaskets = *newBaskets
+	}
+	if email != nil {
+		preference.Email = *email
+	}
+	if push != nil {
+		preference.Push = *push
+	}
+	if inApp != nil {
+		preference.InApp = *inApp
+	}
+
+	if err := s.notificationRepo.SavePreference(preference); err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+// newBasketMessage rédige l'alerte de nouveau panier
+func newBasketMessage(store *models.Store, basket *models.Basket) NotificationMessage {
+	storeID := store.ID
+	basketID := basket.ID
+
+	body := fmt.Sprintf("%s vient de publier « %s » : %d panier(s) à %.2f € au lieu de %.2f €.",
+		store.Name, basket.Name, basket.Quantity, basket.FinalPrice(), basket.OriginalPrice)
+	if basket.PickupStart != nil && basket.PickupEnd != nil {
+		loc := store.Location()
+		start, end := basket.PickupStart.In(loc), basket.PickupEnd.In(loc)
+		body += fmt.Sprintf(" Retrait le %s entre %s et %s.", start.Format("02/01"), start.Format("15:04"), end.Format("15:04"))
+	}
+
+	return NotificationMessage{
+		Type:     models.NotificationTypeNewBasket,
+		Title:    "Nouveaux paniers chez " + store.Name,
+		Body:     body,
+		StoreID:  &storeID,
+		BasketID: &basketID,
+	}
+}
+
+// GetNotifications retourne une page de la boîte de réception de l'utilisateur (page commence à 1)
+// avec le nombre de notifications non lues
+func (s *NotificationService) GetNotifications(userID uint, unreadOnly bool, page, pageSize int) (*NotificationPage, error) {
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultNotificationPageSize
+	}
+	if page < 0 || pageSize < 0 {
+		return nil, ErrInvalidNotificationPage
+	}
+	if pageSize > maxNotificationPageSize {
+		pageSize = maxNotificationPageSize
+	}
+
+	notifications, total, err := s.notificationRepo.ListByUser(userID, unreadOnly, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationPage{Notifications: notifications, Total: total, Unread: unread, Page: page, PageSize: pageSize}, nil
+}
+
+// MarkRead marque comme lue une notification de l'utilisateur
+func (s *NotificationService) MarkRead(userID, notificationID uint) (*models.Notification, error) {
+	return s.notificationRepo.MarkRead(userID, notificationID, s.clock.Now())
+}
+
+// MarkAllRead marque comme lues toutes les notifications de l'utilisateur et retourne leur nombre
+func (s *NotificationService) MarkAllRead(userID uint) (int64, error) {
+	return s.notificationRepo.MarkAllRead(userID, s.clock.Now())
+}
+
+// Inbox dépose un message dans la boîte de réception de l'utilisateur. Ces messages concernent
+// son compte ou ses commandes : ils ne dépendent pas des préférences de notification.
+// Un échec est journalisé sans interrompre l'opération qui l'a déclenché.
+func (s *NotificationService) Inbox(userID uint, message NotificationMessage) {
+	err := s.notificationRepo.Create(&models.Notification{
+		UserID:   userID,
+		Type:     message.Type,
+		Title:    message.Title,
+		Body:     message.Body,
+		StoreID:  message.StoreID,
+		BasketID: message.BasketID,
+		OrderID:  message.OrderID,
+	})
+	if err != nil {
+		log.Printf("notifications: inbox of user %d: %v", userID, err)
+	}
+}
+
+// NotifyMerchantRequestProcessed prévient le demandeur que sa demande de marchand a été acceptée ou refusée
+func (s *NotificationService) NotifyMerchantRequestProcessed(request *models.MerchantRequest) {
+	message := NotificationMessage{Type: models.NotificationTypeMerchantRequest}
+	approved := request.Status == "approved"
+	switch request.Status {
+	case "approved":
+		message.Title = "Demande de marchand acceptée"
+		message.Body = fmt.Sprintf("Votre demande pour %s a été acceptée : vous pouvez maintenant créer vos magasins.", request.BusinessName)
+	case "rejected":
+		message.Title = "Demande de marchand refusée"
+		message.Body = fmt.Sprintf("Votre demande pour %s n'a pas été acceptée.", request.BusinessName)
+	default:
+		return
+	}
+	s.Inbox(request.UserID, message)
+	s.email(request.UserID, mailer.TemplateMerchantApproval, mailer.MerchantApprovalData{
+		BusinessName: request.BusinessName,
+		Approved:     approved,
+	})
+}
+
+// NotifyInvitation prévient l'invité, s'il a déjà un compte, qu'il est invité à rejoindre l'équipe du magasin
+func (s *NotificationService) NotifyInvitation(invitation *models.Invitation, store *models.Store) {
+	user, err := s.userRepo.FindByEmail(invitation.Email)
+	if err != nil || user == nil {
+		return
+	}
+
+	storeID := store.ID
+	s.Inbox(user.ID, NotificationMessage{
+		Type:    models.NotificationTypeInvitation,
+		Title:   "Invitation de " + store.Name,
+		Body:    fmt.Sprintf("Vous êtes invité à rejoindre l'équipe de %s. L'invitation expire le %s.", store.Name, invitation.ExpiresAt.In(store.Location()).Format("02/01/2006")),
+		StoreID: &storeID,
+	})
+}
+
+// NotifyOrderReserved envoie au client le récapitulatif de sa réservation
+func (s *NotificationService) NotifyOrderReserved(order *models.Order) {
+	s.email(order.UserID, mailer.TemplateOrderConfirmation, orderEmailData(order))
+}
+
+// NotifyOrderStatus prévient le client qu'une de ses commandes est prête à être retirée ou a été annulée ;
+// une commande prête est aussi annoncée par email. Les autres statuts ne donnent pas lieu à une notification.
+func (s *NotificationService) NotifyOrderStatus(order *models.Order) {
+	orderID, storeID := order.ID, order.StoreID
+	message := NotificationMessage{StoreID: &storeID, OrderID: &orderID}
+
+	label := "Votre commande " + order.Code
+	if order.Basket.Name != "" {
+		label = fmt.Sprintf("Votre commande %s (%s)", order.Code, order.Basket.Name)
+	}
+
+	switch order.Status {
+	case models.OrderConfirmed:
+		message.Type = models.NotificationTypeOrderReady
+		message.Title = "Commande prête"
+		message.Body = label + " est payée : présentez son code au magasin pour la retirer."
+		if order.PickupStart != nil && order.PickupEnd != nil {
+			loc := order.Basket.Store.Location()
+			start, end := order.PickupStart.In(loc), order.PickupEnd.In(loc)
+			message.Body += fmt.Sprintf(" Retrait le %s entre %s et %s.", start.Format("02/01"), start.Format("15:04"), end.Format("15:04"))
+		}
+	case models.OrderCancelled:
+		message.Type = models.NotificationTypeOrderCancelled
+		message.Title = "Commande annulée"
+		message.Body = label + " a été annulée."
+	default:
+		return
+	}
+	s.Inbox(order.UserID, message)
+
+	if order.Status == models.OrderConfirmed {
+		s.email(order.UserID, mailer.TemplateOrderReady, orderEmailData(order))
+	}
+}
+
+// email met en file un email transactionnel pour l'utilisateur. Comme la boîte de réception, ces emails
+// ne dépendent pas des préférences de notification ; un échec est journalisé.
+func (s *NotificationService) email(userID uint, template string, data interface{}) {
+	user, err := s.userRepo.FindByID(userID)
+	if err == nil {
+		err = s.mail.Enqueue(user.Email, template, data)
+	}
+	if err != nil {
+		log.Printf("notifications: %s email to user %d: %v", template, userID, err)
+	}
+}
+
+// orderEmailData prépare les données des emails de commande ; les heures sont exprimées
+// dans le fuseau horaire du magasin
+func orderEmailData(order *models.Order) mailer.OrderData {
+	store := &order.Basket.Store
+	loc := store.Location()
+	data := mailer.OrderData{
+		Code:       order.Code,
+		BasketName: order.Basket.Name,
+		StoreName:  store.Name,
+		Price:      fmt.Sprintf("%.2f €", order.Price),
+	}
+	if store.Address != "" {
+		data.StoreAddress = fmt.Sprintf("%s, %s %s", store.Address, store.PostalCode, store.City)
+	}
+	if order.Status == models.OrderPending && order.ExpiredAt != nil {
+		data.ExpiresAt = order.ExpiredAt.In(loc).Format("15:04")
+	}
+	if order.PickupStart != nil && order.PickupEnd != nil {
+		start, end := order.PickupStart.In(loc), order.PickupEnd.In(loc)
+		data.Pickup = fmt.Sprintf("le %s entre %s et %s", start.Format("02/01"), start.Format("15:04"), end.Format("15:04"))
+	}
+	return data
+}
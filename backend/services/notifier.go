@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+)
+
+// NotificationMessage est le contenu d'une notification, indépendant du canal de diffusion
+type NotificationMessage struct {
+	Type     string // Un des models.NotificationType*
+	Title    string
+	Body     string
+	StoreID  *uint
+	BasketID *uint
+	OrderID  *uint
+}
+
+// Notifier diffuse une notification sur un canal (email, push, in-app)
+type Notifier interface {
+	// Channel retourne le canal du notifier, un des models.NotificationChannel*
+	Channel() string
+	Notify(ctx context.Context, recipient *models.User, message NotificationMessage) error
+}
+
+// EmailNotifier diffuse les notifications par email, via l'outbox du MailService
+type EmailNotifier struct {
+	mail *MailService
+}
+
+func NewEmailNotifier(mail *MailService) *EmailNotifier {
+	return &EmailNotifier{mail: mail}
+}
+
+func (n *EmailNotifier) Channel() string {
+	return models.NotificationChannelEmail
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, recipient *models.User, message NotificationMessage) error {
+	return n.mail.Enqueue(recipient.Email, mailer.TemplateNotification, mailer.NotificationData{
+		Title: message.Title,
+		Body:  message.Body,
+	})
+}
+
+// PushNotifier est un emplacement pour les notifications push : tant qu'aucun prestataire
+// n'est branché, les notifications sont seulement journalisées
+type PushNotifier struct{}
+
+func NewPushNotifier() *PushNotifier {
+	return &PushNotifier{}
+}
+
+func (n *PushNotifier) Channel() string {
+	return models.NotificationChannelPush
+}
+
+func (n *PushNotifier) Notify(ctx context.Context, recipient *models.User, message NotificationMessage) error {
+	log.Printf("push: user %d: %s", recipient.ID, message.Title)
+	return nil
+}
+
+// InAppNotifier dépose les notifications dans la boîte de réception de l'utilisateur
+type InAppNotifier struct {
+	notificationRepo *repositories.NotificationRepository
+}
+
+func NewInAppNotifier(notificationRepo *repositories.NotificationRepository) *InAppNotifier {
+	return &InAppNotifier{notificationRepo: notificationRepo}
+}
+
+func (n *InAppNotifier) Channel() string {
+	return models.NotificationChannelInApp
+}
+
+func (n *InAppNotifier) Notify(ctx context.Context, recipient *models.User, message NotificationMessage) error {
+	return n.notificationRepo.Create(&models.Notification{
+		UserID:   recipient.ID,
+		Type:     message.Type,
+		Title:    message.Title,
+		Body:     message.Body,
+		StoreID:  message.StoreID,
+		BasketID: message.BasketID,
+		OrderID:  message.OrderID,
+	})
+}
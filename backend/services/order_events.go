@@ -0,0 +1,107 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/events"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+)
+
+// Types des événements du suivi des commandes par le staff
+const (
+	OrderEventReserved  = "order.reserved"
+	OrderEventPaid      = "order.paid"
+	OrderEventCancelled = "order.cancelled"
+	OrderEventPickedUp  = "order.picked_up"
+)
+
+// OrderEvent est l'état d'une commande après un changement, avec ce qu'il faut pour l'afficher
+// dans la liste des retraits du magasin
+type OrderEvent struct {
+	OrderID       uint
+	StoreID       uint
+	Code          string
+	Status        string
+	BasketID      uint
+	BasketName    string
+	Price         float64
+	CustomerEmail string
+	ReservedAt    *time.Time
+	ExpiredAt     *time.Time
+	PickupStart   *time.Time
+	PickupEnd     *time.Time
+	DeliveredAt   *time.Time
+}
+
+// OrderEvents publie le cycle de vie des commandes sur le broker du suivi des commandes
+type OrderEvents struct {
+	broker    *events.Broker
+	orderRepo *repositories.OrderRepository
+}
+
+func NewOrderEvents(broker *events.Broker, orderRepo *repositories.OrderRepository) *OrderEvents {
+	return &OrderEvents{broker: broker, orderRepo: orderRepo}
+}
+
+// Publish relit la commande et diffuse son état. Un échec est journalisé sans interrompre l'opération
+// qui l'a déclenché.
+func (e *OrderEvents) Publish(eventType string, orderID uint) {
+	order, err := e.orderRepo.GetWithCustomer(orderID)
+	if err != nil {
+		log.Printf("order events: order %d: %v", orderID, err)
+		return
+	}
+	e.broker.Publish(eventType, NewOrderEvent(order))
+}
+
+// Subscribe abonne un client aux commandes des magasins donnés. Si lastID est renseigné, les événements
+// manqués depuis sont renvoyés d'abord ; le booléen vaut false si le client doit se resynchroniser.
+func (e *OrderEvents) Subscribe(storeIDs []uint, lastID *uint64) (*events.Subscription, bool) {
+	stores := make(map[uint]bool, len(storeIDs))
+	for _, id := range storeIDs {
+		stores[id] = true
+	}
+	match := func(event events.Event) bool {
+		order, ok := event.Data.(OrderEvent)
+		return ok && stores[order.StoreID]
+	}
+
+	if lastID == nil {
+		return e.broker.Subscribe(match), false
+	}
+	return e.broker.SubscribeAfter(*lastID, match)
+}
+
+// OpenOrders retourne l'état des commandes en attente de paiement ou de retrait des magasins donnés
+func (e *OrderEvents) OpenOrders(storeIDs []uint) ([]OrderEvent, error) {
+	orders, err := e.orderRepo.FindOpenByStores(storeIDs)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make([]OrderEvent, 0, len(orders))
+	for i := range orders {
+		snapshot = append(snapshot, NewOrderEvent(&orders[i]))
+	}
+	return snapshot, nil
+}
+
+// NewOrderEvent construit l'état diffusé d'une commande chargée avec son panier et son client
+func NewOrderEvent(order *models.Order) OrderEvent {
+	return OrderEvent{
+		OrderID:       order.ID,
+		StoreID:       order.StoreID,
+		Code:          order.Code,
+		Status:        order.Status,
+		BasketID:      order.BasketID,
+		BasketName:    order.Basket.Name,
+		Price:         order.Price,
+		CustomerEmail: order.User.Email,
+		ReservedAt:    order.ReservedAt,
+		ExpiredAt:     order.ExpiredAt,
+		PickupStart:   order.PickupStart,
+		PickupEnd:     order.PickupEnd,
+		DeliveredAt:   order.DeliveredAt,
+	}
+}
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+)
+
+// reservationDuration est la durée pendant laquelle un panier réservé est conservé pour le client
+const reservationDuration = 2 * time.Hour
+
+// paymentCurrency est la devise utilisée pour tous les paiements
+const paymentCurrency = "eur"
+
+// maxOrderCodeAttempts borne le nombre de tentatives pour générer un code de retrait unique
+const maxOrderCodeAttempts = 5
+
+var (
+	ErrOrderCodeGeneration = errors.New("failed to generate a unique order code")
+	ErrOrderWrongStore     = errors.New("order does not belong to this store")
+	ErrOrderNotPending     = errors.New("order is not pending")
+	ErrOrderExpired        = errors.New("order has expired")
+	ErrOrderNotOwned       = errors.New("order does not belong to you")
+	ErrOrderNotPaid        = errors.New("order has not been paid")
+	ErrOrderClosed         = errors.New("order is already delivered or cancelled")
+)
+
+// errPaymentEventIgnored signale un événement de paiement sans effet sur la commande
+var errPaymentEventIgnored = errors.New("payment event ignored")
+
+type OrderService struct {
+	orderRepo     *repositories.OrderRepository
+	customerRepo  *repositories.StripeCustomerRepository
+	userRepo      *repositories.UserRepository
+	eventRepo     *repositories.PaymentEventRepository
+	payments      PaymentProvider
+	refunds       *RefundService
+	notifications *NotificationService
+	basketEvents  *BasketEvents
+	orderEvents   *OrderEvents
+}
+
+func NewOrderService(
+	orderRepo *repositories.OrderRepository,
+	customerRepo *repositories.StripeCustomerRepository,
+	userRepo *repositories.UserRepository,
+	eventRepo *repositories.PaymentEventRepository,
+	payments PaymentProvider,
+	refunds *RefundService,
+	notifications *NotificationService,
+	basketEvents *BasketEvents,
+	orderEvents *OrderEvents,
+) *OrderService {
+	return &OrderService{
+		orderRepo:     orderRepo,
+		customerRepo:  customerRepo,
+		userRepo:      userRepo,
+		eventRepo:     eventRepo,
+		payments:      payments,
+		refunds:       refunds,
+		notifications: notifications,
+		basketEvents:  basketEvents,
+		orderEvents:   orderEvents,
+	}
+}
+
+// ReserveBasket réserve un exemplaire du panier pour l'utilisateur.
+// Un panier dont la plage de retrait est terminée ne peut plus être réservé, et la réservation
+// n'est jamais conservée au-delà de la fin de la plage de retrait.
+func (s *OrderService) ReserveBasket(userID, basketID uint) (*models.Order, error) {
+	code, err := s.generateUniqueCode()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiredAt := now.Add(reservationDuration)
+	order := &models.Order{
+		BasketID:   basketID,
+		UserID:     userID,
+		Code:       code,
+		Status:     models.OrderPending,
+		ReservedAt: &now,
+		ExpiredAt:  &expiredAt,
+	}
+
+	err = s.orderRepo.ReserveBasket(order, func(basket *models.Basket) error {
+		if basket.PickupEnd == nil {
+			return nil
+		}
+		if !now.Before(*basket.PickupEnd) {
+			return ErrPickupWindowClosed
+		}
+		if expiredAt.After(*basket.PickupEnd) {
+			expiredAt = *basket.PickupEnd
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.basketEvents.PublishReservation(basketID)
+	s.orderEvents.Publish(OrderEventReserved, order.ID)
+
+	reserved, err := s.orderRepo.GetByID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifications.NotifyOrderReserved(reserved)
+	return reserved, nil
+}
+
+// GetUserOrders retourne l'historique des commandes de l'utilisateur
+func (s *OrderService) GetUserOrders(userID uint) ([]models.Order, error) {
+	return s.orderRepo.GetByUser(userID)
+}
+
+// VerifyOrder valide au comptoir la commande portant ce code et la marque comme remise.
+// Le code peut être saisi directement ou extrait d'un QR code (qrPayload).
+// Si le panier a une plage de retrait, la commande ne peut être remise que pendant celle-ci.
+// ExpiredAt ne borne que le délai de paiement : une commande payée reste à remettre après.
+func (s *OrderService) VerifyOrder(storeID, staffID uint, code, qrPayload string) (*models.Order, error) {
+	if code == "" {
+		parsed, err := utils.ParseOrderQRPayload(qrPayload)
+		if err != nil {
+			return nil, err
+		}
+		code = parsed
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	order, err := s.orderRepo.UpdateByCode(code, func(order *models.Order) error {
+		if order.StoreID != storeID {
+			return ErrOrderWrongStore
+		}
+		switch order.Status {
+		case models.OrderConfirmed:
+		case models.OrderPending:
+			return ErrOrderNotPaid
+		default:
+			return ErrOrderClosed
+		}
+		now := time.Now()
+		if order.PickupStart != nil && now.Before(*order.PickupStart) {
+			return ErrPickupNotStarted
+		}
+		if order.PickupEnd != nil && now.After(*order.PickupEnd) {
+			return ErrPickupWindowClosed
+		}
+
+		order.Status = models.OrderDelivered
+		order.DeliveredAt = &now
+		order.DeliveredByID = &staffID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.orderEvents.Publish(OrderEventPickedUp, order.ID)
+	return order, nil
+}
+
+// PayOrder règle une commande en attente avec le moyen de paiement donné.
+// La commande ne passe à l'état confirmé qu'une fois le paiement réussi ; si le prestataire
+// traite le paiement de façon asynchrone, la commande reste en attente jusqu'à sa notification.
+func (s *OrderService) PayOrder(userID, orderID uint, paymentMethodID string) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrOrderNotOwned
+	}
+	if order.Status != models.OrderPending {
+		return nil, ErrOrderNotPending
+	}
+	if order.ExpiredAt != nil && time.Now().After(*order.ExpiredAt) {
+		return nil, ErrOrderExpired
+	}
+
+	customerID, err := s.ensureCustomer(userID, paymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	var intentID string
+	if order.StripePaymentIntentID != nil {
+		intentID = *order.StripePaymentIntentID
+	} else {
+		// La clé d'idempotence empêche deux paiements simultanés de la commande de créer deux intentions
+		orderID := strconv.FormatUint(uint64(order.ID), 10)
+		intent, err := s.payments.CreatePaymentIntent(amountInCents(order.Price), paymentCurrency, customerID, "order-"+orderID, map[string]string{
+			"order_id": orderID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		intentID = intent.ID
+		order, err = s.orderRepo.UpdateByID(order.ID, func(o *models.Order) error {
+			o.StripePaymentIntentID = &intentID
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	intent, err := s.payments.ConfirmPaymentIntent(intentID, paymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != PaymentIntentSucceeded {
+		return order, nil
+	}
+
+	return s.confirmPayment(order.ID, intentID)
+}
+
+// confirmPayment passe la commande à l'état confirmé. Si la commande a été annulée
+// entre-temps (réservation expirée), le remboursement du paiement est programmé.
+func (s *OrderService) confirmPayment(orderID uint, intentID string) (*models.Order, error) {
+	confirmed := false
+	order, err := s.orderRepo.UpdateByID(orderID, func(o *models.Order) error {
+		switch o.Status {
+		case models.OrderPending:
+			o.Status = models.OrderConfirmed
+			confirmed = true
+			return nil
+		case models.OrderConfirmed, models.OrderDelivered:
+			return nil
+		default:
+			return ErrOrderClosed
+		}
+	})
+	if errors.Is(err, ErrOrderClosed) {
+		if err := s.refunds.Schedule(orderID, intentID); err != nil {
+			return nil, err
+		}
+		return nil, ErrOrderExpired
+	}
+	if err == nil && confirmed {
+		s.notifications.NotifyOrderStatus(order)
+		s.orderEvents.Publish(OrderEventPaid, order.ID)
+	}
+	return order, err
+}
+
+// ApplyPaymentEvent applique un événement de paiement à la commande concernée, une seule fois
+// par ID d'événement. Le remboursement d'un paiement réussi sur une commande déjà annulée
+// est programmé dans la même transaction que l'enregistrement de l'événement.
+func (s *OrderService) ApplyPaymentEvent(event PaymentEvent) error {
+	var changedOrderID, restockedBasketID uint
+
+	_, err := s.eventRepo.ProcessOnce(event.ID, event.Type, func(tx *gorm.DB) error {
+		orders := s.orderRepo.WithTx(tx)
+
+		orderID, err := orders.FindIDByPaymentIntent(event.Data.PaymentIntentID)
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case PaymentEventSucceeded:
+			refund := false
+			_, err = orders.UpdateByID(orderID, func(o *models.Order) error {
+				switch o.Status {
+				case models.OrderPending:
+					o.Status = models.OrderConfirmed
+					changedOrderID = o.ID
+					return nil
+				case models.OrderCancelled:
+					refund = true
+				}
+				return errPaymentEventIgnored
+			})
+			if refund {
+				err = s.refunds.WithTx(tx).Schedule(orderID, event.Data.PaymentIntentID)
+			}
+		case PaymentEventFailed:
+			err = orders.CancelOrder(orderID, func(o *models.Order) error {
+				if o.Status != models.OrderPending {
+					return errPaymentEventIgnored
+				}
+				changedOrderID, restockedBasketID = o.ID, o.BasketID
+				return nil
+			})
+		case PaymentEventRefunded:
+			err = orders.CancelOrder(orderID, func(o *models.Order) error {
+				if o.Status != models.OrderPending && o.Status != models.OrderConfirmed {
+					return errPaymentEventIgnored
+				}
+				changedOrderID, restockedBasketID = o.ID, o.BasketID
+				return nil
+			})
+		}
+
+		if errors.Is(err, errPaymentEventIgnored) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if changedOrderID != 0 {
+		s.notifyOrderStatus(changedOrderID)
+		eventType := OrderEventPaid
+		if restockedBasketID != 0 {
+			eventType = OrderEventCancelled
+		}
+		s.orderEvents.Publish(eventType, changedOrderID)
+	}
+	if restockedBasketID != 0 {
+		s.basketEvents.Publish(BasketEventRestocked, restockedBasketID)
+	}
+	return nil
+}
+
+// notifyOrderStatus prévient le client du nouveau statut de la commande, une fois la transaction validée
+func (s *OrderService) notifyOrderStatus(orderID uint) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		log.Printf("notifications: order %d: %v", orderID, err)
+		return
+	}
+	s.notifications.NotifyOrderStatus(order)
+}
+
+// ensureCustomer retourne le client du prestataire de paiement associé à l'utilisateur,
+// en le créant si besoin, et lui rattache le moyen de paiement
+func (s *OrderService) ensureCustomer(userID uint, paymentMethodID string) (string, error) {
+	customer, err := s.customerRepo.FindByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	if customer == nil {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return "", err
+		}
+		customerID, err := s.payments.CreateCustomer(user.Email)
+		if err != nil {
+			return "", err
+		}
+		customer = &models.StripeCustomer{UserID: userID, StripeCustomerID: customerID}
+	}
+
+	if customer.StripePaymentMethodID != paymentMethodID {
+		if err := s.payments.AttachPaymentMethod(customer.StripeCustomerID, paymentMethodID); err != nil {
+			return "", err
+		}
+		customer.StripePaymentMethodID = paymentMethodID
+		if err := s.customerRepo.Save(customer); err != nil {
+			return "", err
+		}
+	}
+
+	return customer.StripeCustomerID, nil
+}
+
+func amountInCents(price float64) int64 {
+	return int64(math.Round(price * 100))
+}
+
+func (s *OrderService) generateUniqueCode() (string, error) {
+	for i := 0; i < maxOrderCodeAttempts; i++ {
+		code := utils.GenerateOrderCode()
+		exists, err := s.orderRepo.CodeExists(code)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return code, nil
+		}
+	}
+	return "", ErrOrderCodeGeneration
+}
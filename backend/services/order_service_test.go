@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm/clause"
+)
+
+func TestVerifyOrder(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := utils.SystemClock{}
+	orders := newTestOrderService(db, newTestServices(db, clock), NewFakePaymentProvider(), clock)
+
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	staff := testutil.CreateUser(t, db, "staff@example.com")
+	customer := testutil.CreateUser(t, db, "customer@example.com")
+	merchant := testutil.CreateMerchant(t, db, owner)
+	store := testutil.CreateStore(t, db, merchant, "Boulangerie")
+	otherStore := testutil.CreateStore(t, db, merchant, "Épicerie")
+	basket := testutil.CreateBasket(t, db, store, "Panier", models.BasketStatusAvailable, nil)
+
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	withPickup := func(start, end time.Time) func(order *models.Order) {
+		return func(order *models.Order) {
+			order.PickupStart, order.PickupEnd = &start, &end
+		}
+	}
+
+	tests := []struct {
+		name    string
+		status  string
+		store   *models.Store
+		setup   func(order *models.Order)
+		wantErr error
+	}{
+		// Le délai de réservation ne concerne que le paiement
+		{"payée sans plage, délai de réservation dépassé", models.OrderConfirmed, store, nil, nil},
+		{"payée, dans la plage de retrait", models.OrderConfirmed, store, withPickup(past, future), nil},
+		{"payée, avant la plage de retrait", models.OrderConfirmed, store, withPickup(future, future.Add(time.Hour)), ErrPickupNotStarted},
+		{"payée, après la plage de retrait", models.OrderConfirmed, store, withPickup(past.Add(-time.Hour), past), ErrPickupWindowClosed},
+		{"non payée", models.OrderPending, store, nil, ErrOrderNotPaid},
+		{"déjà remise", models.OrderDelivered, store, nil, ErrOrderClosed},
+		{"autre magasin", models.OrderConfirmed, otherStore, nil, ErrOrderWrongStore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := testutil.CreateOrder(t, db, basket, customer, tt.status, past)
+			if tt.setup != nil {
+				tt.setup(order)
+				if err := db.Omit(clause.Associations).Save(order).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			delivered, err := orders.VerifyOrder(tt.store.ID, staff.ID, order.Code, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (delivered.Status != models.OrderDelivered || delivered.DeliveredByID == nil || *delivered.DeliveredByID != staff.ID) {
+				t.Errorf("order = %s delivered by %v, want delivered by %d", delivered.Status, delivered.DeliveredByID, staff.ID)
+			}
+		})
+	}
+}
+
+func TestFakePaymentProviderAfterRestart(t *testing.T) {
+	before := NewFakePaymentProvider()
+	customerID, err := before.CreateCustomer("customer@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intent, err := before.CreatePaymentIntent(1000, paymentCurrency, customerID, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Les identifiants enregistrés en base restent utilisables avec une nouvelle instance
+	after := NewFakePaymentProvider()
+	if err := after.AttachPaymentMethod(customerID, "pm_card_visa"); err != nil {
+		t.Errorf("AttachPaymentMethod after restart: %v", err)
+	}
+	confirmed, err := after.ConfirmPaymentIntent(intent.ID, "pm_card_visa")
+	if err != nil || confirmed.Status != PaymentIntentSucceeded {
+		t.Fatalf("ConfirmPaymentIntent after restart = %+v, %v; want succeeded", confirmed, err)
+	}
+	if err := after.RefundPaymentIntent(intent.ID); err != nil || !after.Refunded(intent.ID) {
+		t.Errorf("RefundPaymentIntent after restart: %v", err)
+	}
+
+	if err := after.AttachPaymentMethod("cus_unknown", "pm_card_visa"); err == nil {
+		t.Error("AttachPaymentMethod accepted a customer not created by the fake provider")
+	}
+	if other, _ := after.CreateCustomer("other@example.com"); other == customerID {
+		t.Error("CreateCustomer reused an ID created before the restart")
+	}
+}
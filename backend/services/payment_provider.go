@@ -0,0 +1,44 @@
+package services
+
+import "errors"
+
+// Statuts d'une intention de paiement (mêmes valeurs que l'API Stripe)
+const (
+	PaymentIntentRequiresPaymentMethod = "requires_payment_method"
+	PaymentIntentRequiresConfirmation  = "requires_confirmation"
+	PaymentIntentRequiresAction        = "requires_action"
+	PaymentIntentProcessing            = "processing"
+	PaymentIntentSucceeded             = "succeeded"
+	PaymentIntentCanceled              = "canceled"
+)
+
+var (
+	ErrPaymentDeclined        = errors.New("payment declined")
+	ErrPaymentAlreadyRefunded = errors.New("payment already refunded")
+)
+
+// PaymentIntent représente une intention de paiement chez le prestataire
+type PaymentIntent struct {
+	ID           string `json:"id"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+	CustomerID   string `json:"customer"`
+	Status       string `json:"status"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// PaymentProvider abstrait le prestataire de paiement (Stripe ou fake en local)
+type PaymentProvider interface {
+	// CreateCustomer crée un client chez le prestataire et retourne son identifiant
+	CreateCustomer(email string) (string, error)
+	// AttachPaymentMethod rattache un moyen de paiement au client
+	AttachPaymentMethod(customerID, paymentMethodID string) error
+	// CreatePaymentIntent crée une intention de paiement ; amount est exprimé en centimes.
+	// Un second appel avec la même idempotencyKey retourne l'intention déjà créée.
+	CreatePaymentIntent(amount int64, currency, customerID, idempotencyKey string, metadata map[string]string) (*PaymentIntent, error)
+	// ConfirmPaymentIntent confirme l'intention avec le moyen de paiement donné
+	ConfirmPaymentIntent(intentID, paymentMethodID string) (*PaymentIntent, error)
+	// RefundPaymentIntent rembourse intégralement une intention de paiement réussie ;
+	// retourne ErrPaymentAlreadyRefunded si elle l'a déjà été
+	RefundPaymentIntent(intentID string) error
+}
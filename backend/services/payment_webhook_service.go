@@ -0,0 +1,59 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+// webhookTolerance est l'écart maximal accepté entre l'horodatage signé et l'heure du serveur
+const webhookTolerance = 5 * time.Minute
+
+// Types d'événements de paiement reçus par webhook
+const (
+	PaymentEventSucceeded = "payment_succeeded"
+	PaymentEventFailed    = "payment_failed"
+	PaymentEventRefunded  = "payment_refunded"
+)
+
+var ErrInvalidPaymentEvent = errors.New("invalid payment event")
+
+// PaymentEvent est le contenu d'un webhook envoyé par le prestataire de paiement
+type PaymentEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		PaymentIntentID string `json:"payment_intent_id"`
+	} `json:"data"`
+}
+
+// PaymentWebhookService authentifie les webhooks de paiement et les applique aux commandes
+type PaymentWebhookService struct {
+	secret       string
+	orderService *OrderService
+	clock        utils.Clock
+}
+
+func NewPaymentWebhookService(secret string, orderService *OrderService, clock utils.Clock) *PaymentWebhookService {
+	return &PaymentWebhookService{secret: secret, orderService: orderService, clock: clock}
+}
+
+// HandleWebhook vérifie la signature du webhook puis applique l'événement.
+// Un événement déjà traité est ignoré sans erreur pour que le prestataire cesse ses renvois.
+func (s *PaymentWebhookService) HandleWebhook(payload []byte, signature string) error {
+	if err := utils.VerifyWebhookSignature(s.secret, signature, payload, s.clock.Now(), webhookTolerance); err != nil {
+		return err
+	}
+
+	var event PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return ErrInvalidPaymentEvent
+	}
+	if event.ID == "" || event.Type == "" {
+		return ErrInvalidPaymentEvent
+	}
+
+	return s.orderService.ApplyPaymentEvent(event)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+var (
+	ErrPickupWindowIncomplete = errors.New("pickup start and end must be provided together")
+	ErrPickupWindowInvalid    = errors.New("pickup end must be after pickup start")
+	ErrPickupWindowPast       = errors.New("pickup window is already over")
+	ErrPickupNotStarted       = errors.New("pickup window has not started yet")
+	ErrPickupWindowClosed     = errors.New("pickup window is over")
+)
+
+// validatePickupWindow vérifie la plage de retrait d'un panier. Une plage absente est acceptée.
+func validatePickupWindow(start, end *time.Time, now time.Time) error {
+	if start == nil && end == nil {
+		return nil
+	}
+	if start == nil || end == nil {
+		return ErrPickupWindowIncomplete
+	}
+	if !end.After(*start) {
+		return ErrPickupWindowInvalid
+	}
+	if !end.After(now) {
+		return ErrPickupWindowPast
+	}
+	return nil
+}
+
+// validatePickupTimes vérifie les heures de retrait (HH:MM) d'une configuration de panier
+func validatePickupTimes(start, end string) error {
+	if start == "" && end == "" {
+		return nil
+	}
+	if start == "" || end == "" {
+		return ErrPickupWindowIncomplete
+	}
+	startOffset, err := utils.ParseTimeOfDay(start)
+	if err != nil {
+		return err
+	}
+	endOffset, err := utils.ParseTimeOfDay(end)
+	if err != nil {
+		return err
+	}
+	if endOffset <= startOffset {
+		return ErrPickupWindowInvalid
+	}
+	return nil
+}
+
+// configurationPickupWindow retourne la plage de retrait de la configuration pour le jour day,
+// dans le fuseau horaire du magasin. Retourne nil, nil si la configuration n'a pas d'heures de retrait.
+func configurationPickupWindow(config *models.BasketConfiguration, day time.Time) (*time.Time, *time.Time, error) {
+	if config.PickupStartTime == "" || config.PickupEndTime == "" {
+		return nil, nil, nil
+	}
+	startOffset, err := utils.ParseTimeOfDay(config.PickupStartTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	endOffset, err := utils.ParseTimeOfDay(config.PickupEndTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loc := config.Store.Location()
+	start := utils.AtTimeOfDay(day, startOffset, loc)
+	end := utils.AtTimeOfDay(day, endOffset, loc)
+	return &start, &end, nil
+}
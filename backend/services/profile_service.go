@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPhone   = errors.New("phone number must be in international format")
+	ErrEmailUnchanged = errors.New("new email is the current email")
+)
+
+// phonePattern est un numéro au format international E.164, une fois les séparateurs retirés
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// Profile est le profil de l'utilisateur, avec ses rôles, son changement d'email en attente
+// et ses préférences de notification
+type Profile struct {
+	User          *models.User
+	IsMerchant    bool
+	StaffStoreIDs []uint
+	PendingEmail  *models.EmailChangeRequest // nil sans changement d'email en attente
+	Preferences   *models.NotificationPreference
+}
+
+// ProfileService gère le profil de l'utilisateur connecté et le changement de son adresse email,
+// appliqué une fois la nouvelle adresse vérifiée par un code
+type ProfileService struct {
+	userRepo        *repositories.UserRepository
+	emailChangeRepo *repositories.EmailChangeRepository
+	notifications   *NotificationService
+	mail            *MailService
+}
+
+func NewProfileService(
+	userRepo *repositories.UserRepository,
+	emailChangeRepo *repositories.EmailChangeRepository,
+	notifications *NotificationService,
+	mail *MailService,
+) *ProfileService {
+	return &ProfileService{
+		userRepo:        userRepo,
+		emailChangeRepo: emailChangeRepo,
+		notifications:   notifications,
+		mail:            mail,
+	}
+}
+
+// GetProfile retourne le profil de l'utilisateur
+func (s *ProfileService) GetProfile(userID uint) (*Profile, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	isMerchant, err := s.userRepo.IsMerchant(userID)
+	if err != nil {
+		return nil, err
+	}
+	staffStoreIDs, err := s.userRepo.GetStaffStoreIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.emailChangeRepo.FindPending(userID, time.Now())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	preferences, err := s.notifications.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{
+		User:          user,
+		IsMerchant:    isMerchant,
+		StaffStoreIDs: staffStoreIDs,
+		PendingEmail:  pending,
+		Preferences:   preferences,
+	}, nil
+}
+
+// UpdateProfile applique les champs renseignés au profil et aux préférences de notification
+// de l'utilisateur, puis retourne le profil à jour
+func (s *ProfileService) UpdateProfile(userID uint, req requests.UpdateProfileRequest) (*Profile, error) {
+	var phone string
+	if req.Phone != nil {
+		var err error
+		if phone, err = normalizePhone(*req.Phone); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.userRepo.UpdateByID(userID, func(user *models.User) bool {
+		if req.DisplayName != nil {
+			user.DisplayName = strings.TrimSpace(*req.DisplayName)
+		}
+		if req.Phone != nil {
+			user.Phone = phone
+		}
+		if req.PreferredLanguage != nil {
+			user.PreferredLanguage = *req.PreferredLanguage
+		}
+		if location := req.DefaultLocation; location != nil {
+			user.DefaultLocationLabel = strings.TrimSpace(location.Label)
+			user.DefaultLatitude = location.Latitude
+			user.DefaultLongitude = location.Longitude
+			user.DefaultRadiusKm = location.RadiusKm
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if prefs := req.NotificationPreferences; prefs != nil {
+		_, err := s.notifications.UpdatePreferences(userID, prefs.NewBaskets, prefs.Email, prefs.Push, prefs.InApp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetProfile(userID)
+}
+
+// ClearDefaultLocation efface le lieu de recherche par défaut de l'utilisateur
+func (s *ProfileService) ClearDefaultLocation(userID uint) error {
+	return s.userRepo.UpdateByID(userID, func(user *models.User) bool {
+		user.DefaultLocationLabel = ""
+		user.DefaultLatitude = nil
+		user.DefaultLongitude = nil
+		user.DefaultRadiusKm = nil
+		return true
+	})
+}
+
+// RequestEmailChange envoie un code de vérification à newEmail après vérification du mot de passe.
+// L'email du compte n'est remplacé qu'à la saisie du code ; une nouvelle demande remplace la précédente.
+func (s *ProfileService) RequestEmailChange(userID uint, newEmail, password string) (*models.EmailChangeRequest, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return nil, ErrInvalidPassword
+	}
+
+	newEmail = strings.TrimSpace(newEmail)
+	if newEmail == user.Email {
+		return nil, ErrEmailUnchanged
+	}
+	_, err = s.userRepo.FindByEmail(newEmail)
+	if err == nil {
+		return nil, repositories.ErrEmailAlreadyUsed
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	code := utils.GenerateValidationCode()
+	request := &models.EmailChangeRequest{
+		UserID:    userID,
+		NewEmail:  newEmail,
+		CodeHash:  utils.HashToken(code),
+		ExpiresAt: time.Now().Add(validationCodeValidity),
+	}
+
+	// La demande et l'email du code sont enregistrés ensemble : pas de demande sans code envoyé
+	err = s.userRepo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := repositories.NewEmailChangeRepository(tx).Create(request); err != nil {
+			return err
+		}
+		return s.mail.WithTx(tx).Enqueue(newEmail, mailer.TemplateEmailChange, mailer.ValidationCodeData{
+			Code:     code,
+			ValidFor: fmt.Sprintf("%d minutes", int(validationCodeValidity.Minutes())),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// ConfirmEmailChange remplace l'email du compte par l'adresse vérifiée par code, puis prévient
+// l'ancienne adresse. Comme pour la validation du compte, le code expire après validationCodeValidity
+// et est bloqué après maxValidationAttempts saisies erronées.
+func (s *ProfileService) ConfirmEmailChange(userID uint, code string) (*Profile, error) {
+	oldEmail, request, err := s.emailChangeRepo.Confirm(userID, utils.HashToken(code), maxValidationAttempts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mail.Enqueue(oldEmail, mailer.TemplateEmailChanged, mailer.EmailChangedData{NewEmail: request.NewEmail})
+	if err != nil {
+		log.Printf("profile: failed to notify %s of email change for user %d: %v", oldEmail, userID, err)
+	}
+
+	return s.GetProfile(userID)
+}
+
+// CancelEmailChange annule le changement d'email en attente de l'utilisateur
+func (s *ProfileService) CancelEmailChange(userID uint) error {
+	return s.emailChangeRepo.DeletePending(userID)
+}
+
+// normalizePhone retire les séparateurs usuels du numéro et vérifie son format international ;
+// une valeur vide efface le numéro
+func normalizePhone(phone string) (string, error) {
+	phone = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '-', '(', ')':
+			return -1
+		}
+		return r
+	}, phone)
+	if phone == "" {
+		return "", nil
+	}
+	if !phonePattern.MatchString(phone) {
+		return "", ErrInvalidPhone
+	}
+	return phone, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// refundBatchSize est le nombre maximal de remboursements effectués à chaque passage du scheduler
+	refundBatchSize = 20
+	// refundLease est le délai après lequel un remboursement réservé mais non confirmé est retenté
+	refundLease = 5 * time.Minute
+	// refundRetryBase et refundRetryMax bornent l'attente entre deux tentatives, doublée à chaque échec
+	refundRetryBase = time.Minute
+	refundRetryMax  = 6 * time.Hour
+)
+
+// RefundService enregistre les remboursements à effectuer ; Process les effectue ensuite en arrière-plan
+// chez le prestataire de paiement, en retentant les échecs avec un délai croissant. Un remboursement
+// n'est jamais abandonné : l'argent reste dû au client.
+type RefundService struct {
+	refundRepo *repositories.PaymentRefundRepository
+	payments   PaymentProvider
+	clock      utils.Clock
+}
+
+func NewRefundService(refundRepo *repositories.PaymentRefundRepository, payments PaymentProvider, clock utils.Clock) *RefundService {
+	return &RefundService{refundRepo: refundRepo, payments: payments, clock: clock}
+}
+
+// WithTx retourne un RefundService dont les remboursements sont enregistrés dans la transaction tx :
+// ils ne sont effectués que si la transaction est validée
+func (s *RefundService) WithTx(tx *gorm.DB) *RefundService {
+	scoped := *s
+	scoped.refundRepo = s.refundRepo.WithTx(tx)
+	return &scoped
+}
+
+// Schedule enregistre le remboursement de l'intention de paiement de la commande
+func (s *RefundService) Schedule(orderID uint, intentID string) error {
+	return s.refundRepo.Schedule(&models.PaymentRefund{
+		OrderID:         orderID,
+		PaymentIntentID: intentID,
+		Status:          models.RefundPending,
+		NextAttemptAt:   s.clock.Now(),
+	})
+}
+
+// Process effectue les remboursements dus ; destiné au scheduler.
+// Un échec est retenté plus tard et n'interrompt pas le traitement des autres remboursements.
+func (s *RefundService) Process(ctx context.Context) error {
+	refunds, err := s.refundRepo.ClaimDue(s.clock.Now(), refundBatchSize, refundLease)
+	if err != nil {
+		return err
+	}
+
+	done := 0
+	for i := range refunds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if s.refund(&refunds[i]) {
+			done++
+		}
+	}
+
+	if len(refunds) > 0 {
+		log.Printf("refunds: %d/%d payment(s) refunded", done, len(refunds))
+	}
+	return nil
+}
+
+// refund effectue un remboursement réservé et enregistre le résultat de la tentative. Un paiement déjà
+// remboursé (tentative précédente aboutie mais non enregistrée) compte comme remboursé.
+func (s *RefundService) refund(refund *models.PaymentRefund) bool {
+	err := s.payments.RefundPaymentIntent(refund.PaymentIntentID)
+
+	now := s.clock.Now()
+	if err == nil || errors.Is(err, ErrPaymentAlreadyRefunded) {
+		if err := s.refundRepo.MarkRefunded(refund.ID, now); err != nil {
+			log.Printf("refunds: payment %s of order %d refunded but not marked: %v", refund.PaymentIntentID, refund.OrderID, err)
+		}
+		return true
+	}
+
+	log.Printf("refunds: payment %s of order %d, attempt %d: %v", refund.PaymentIntentID, refund.OrderID, refund.Attempts, err)
+	if err := s.refundRepo.MarkRetry(refund.ID, now.Add(utils.Backoff(refundRetryBase, refundRetryMax, refund.Attempts)), err.Error()); err != nil {
+		log.Printf("refunds: refund %d: %v", refund.ID, err)
+	}
+	return false
+}
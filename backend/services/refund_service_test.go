@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+func TestRefundAlreadyRefunded(t *testing.T) {
+	db := testutil.OpenDB(t)
+	clock := utils.FixedClock{Time: time.Now()}
+	payments := NewFakePaymentProvider()
+	refunds := NewRefundService(repositories.NewPaymentRefundRepository(db), payments, clock)
+
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+	basket := testutil.CreateBasket(t, db, store, "Panier", models.BasketStatusAvailable, nil)
+	order := testutil.CreateOrder(t, db, basket, testutil.CreateUser(t, db, "customer@example.com"), models.OrderCancelled, clock.Time)
+
+	intent, err := payments.CreatePaymentIntent(1000, paymentCurrency, "cus_fake_test", "", nil)
+	if err == nil {
+		_, err = payments.ConfirmPaymentIntent(intent.ID, "pm_card_visa")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Le remboursement a abouti chez le prestataire sans être enregistré : la tentative suivante
+	// le constate et ne le retente pas indéfiniment
+	if err := payments.RefundPaymentIntent(intent.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := refunds.Schedule(order.ID, intent.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := refunds.Process(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var refund models.PaymentRefund
+	if err := db.Where("payment_intent_id = ?", intent.ID).First(&refund).Error; err != nil {
+		t.Fatal(err)
+	}
+	if refund.Status != models.RefundRefunded || refund.Attempts != 1 {
+		t.Errorf("refund = %s after %d attempts, want refunded after 1", refund.Status, refund.Attempts)
+	}
+}
+
+func TestFakePaymentIntentIdempotency(t *testing.T) {
+	payments := NewFakePaymentProvider()
+
+	first, err := payments.CreatePaymentIntent(1000, paymentCurrency, "cus_fake_test", "order-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := payments.CreatePaymentIntent(1000, paymentCurrency, "cus_fake_test", "order-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := payments.CreatePaymentIntent(1000, paymentCurrency, "cus_fake_test", "order-2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID || other.ID == first.ID {
+		t.Errorf("intents = %s, %s, %s; want the same intent for the same key only", first.ID, again.ID, other.ID)
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+)
+
+var (
+	ErrReviewNotAllowed   = errors.New("only delivered orders can be reviewed")
+	ErrReviewWrongStore   = errors.New("review does not belong to this store")
+	ErrInvalidReviewPage  = errors.New("page and page_size must be positive")
+	ErrInvalidReviewScore = errors.New("rating must be between 1 and 5")
+)
+
+const (
+	defaultReviewPageSize = 20
+	maxReviewPageSize     = 100
+)
+
+// ReviewPage est une page d'avis d'un magasin
+type ReviewPage struct {
+	Reviews  []models.Review
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+type ReviewService struct {
+	reviewRepo *repositories.ReviewRepository
+	orderRepo  *repositories.OrderRepository
+}
+
+func NewReviewService(reviewRepo *repositories.ReviewRepository, orderRepo *repositories.OrderRepository) *ReviewService {
+	return &ReviewService{reviewRepo: reviewRepo, orderRepo: orderRepo}
+}
+
+// CreateReview enregistre l'avis du client sur une de ses commandes remises
+func (s *ReviewService) CreateReview(userID, orderID uint, rating int, comment string) (*models.Review, error) {
+	if rating < 1 || rating > 5 {
+		return nil, ErrInvalidReviewScore
+	}
+
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrOrderNotOwned
+	}
+	if order.Status != models.OrderDelivered {
+		return nil, ErrReviewNotAllowed
+	}
+
+	review := &models.Review{
+		OrderID: order.ID,
+		StoreID: order.StoreID,
+		UserID:  userID,
+		Rating:  rating,
+		Comment: comment,
+	}
+	if err := s.reviewRepo.Create(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetStoreReviews retourne une page des avis visibles du magasin (page commence à 1)
+func (s *ReviewService) GetStoreReviews(storeID uint, page, pageSize int) (*ReviewPage, error) {
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultReviewPageSize
+	}
+	if page < 0 || pageSize < 0 {
+		return nil, ErrInvalidReviewPage
+	}
+	if pageSize > maxReviewPageSize {
+		pageSize = maxReviewPageSize
+	}
+
+	reviews, total, err := s.reviewRepo.ListVisibleByStore(storeID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ReviewPage{Reviews: reviews, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// ReplyToReview enregistre la réponse du magasin à un de ses avis ; le droit de répondre
+// (authz.PermissionReviewsReply) est vérifié en amont
+func (s *ReviewService) ReplyToReview(storeID, reviewID uint, reply string) (*models.Review, error) {
+	return s.reviewRepo.Update(reviewID, func(review *models.Review) error {
+		if review.StoreID != storeID {
+			return ErrReviewWrongStore
+		}
+		now := time.Now()
+		review.Reply = reply
+		review.RepliedAt = &now
+		return nil
+	})
+}
+
+// SetReviewHidden masque ou réaffiche un avis ; la note du magasin est recalculée
+func (s *ReviewService) SetReviewHidden(reviewID, adminID uint, hidden bool) (*models.Review, error) {
+	return s.reviewRepo.Update(reviewID, func(review *models.Review) error {
+		review.Hidden = hidden
+		if hidden {
+			now := time.Now()
+			review.HiddenAt = &now
+			review.HiddenByID = &adminID
+		} else {
+			review.HiddenAt = nil
+			review.HiddenByID = nil
+		}
+		return nil
+	})
+}
+
+// DeleteReview supprime un avis ; la note du magasin est recalculée
+func (s *ReviewService) DeleteReview(reviewID uint) error {
+	return s.reviewRepo.Delete(reviewID)
+}
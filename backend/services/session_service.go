@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+// maxUserAgentLength borne la taille du User-Agent enregistré avec une session
+const maxUserAgentLength = 512
+
+// SessionService gère les sessions des utilisateurs, une par appareil connecté. Chaque session a un
+// refresh token renouvelé à chaque rafraîchissement ; un jeton réutilisé révoque toute la session.
+type SessionService struct {
+	sessionRepo *repositories.SessionRepository
+	userRepo    *repositories.UserRepository
+}
+
+func NewSessionService(sessionRepo *repositories.SessionRepository, userRepo *repositories.UserRepository) *SessionService {
+	return &SessionService{sessionRepo: sessionRepo, userRepo: userRepo}
+}
+
+// Open ouvre une session pour l'utilisateur authentifié et retourne un jeton d'accès et un refresh token
+func (s *SessionService) Open(user *models.User, userAgent, ipAddress string) (string, string, error) {
+	refreshToken, expiresAt, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	session := &models.Session{
+		UserID:     user.ID,
+		UserAgent:  truncate(userAgent, maxUserAgentLength),
+		IPAddress:  ipAddress,
+		LastUsedAt: time.Now(),
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.sessionRepo.Create(session, utils.HashToken(refreshToken)); err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := s.accessToken(user, session.ID)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+// Refresh échange un refresh token contre un nouveau jeton d'accès et un nouveau refresh token.
+// L'ancien refresh token ne peut plus servir : s'il est présenté de nouveau, la session est révoquée.
+func (s *SessionService) Refresh(refreshToken, userAgent, ipAddress string) (string, string, error) {
+	newRefreshToken, expiresAt, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	session, err := s.sessionRepo.Rotate(utils.HashToken(refreshToken), utils.HashToken(newRefreshToken), func(session *models.Session) {
+		session.UserAgent = truncate(userAgent, maxUserAgentLength)
+		session.IPAddress = ipAddress
+		session.LastUsedAt = now
+		session.ExpiresAt = expiresAt
+	}, now)
+	if errors.Is(err, repositories.ErrRefreshTokenReused) {
+		log.Printf("sessions: refresh token reused from %s, session revoked", ipAddress)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil {
+		return "", "", err
+	}
+	accessToken, err := s.accessToken(user, session.ID)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, newRefreshToken, nil
+}
+
+// accessToken signe un jeton d'accès reflétant les droits actuels de l'utilisateur
+func (s *SessionService) accessToken(user *models.User, sessionID uint) (string, error) {
+	isMerchant, err := s.userRepo.IsMerchant(user.ID)
+	if err != nil {
+		return "", err
+	}
+	staffStoreIDs, err := s.userRepo.GetStaffStoreIDs(user.ID)
+	if err != nil {
+		return "", err
+	}
+	return utils.GenerateToken(user.Email, user.ID, user.IsAdmin, isMerchant, staffStoreIDs, sessionID)
+}
+
+// GetSessions retourne les sessions actives de l'utilisateur
+func (s *SessionService) GetSessions(userID uint) ([]models.Session, error) {
+	return s.sessionRepo.FindActiveByUser(userID, time.Now())
+}
+
+// Revoke déconnecte une session de l'utilisateur
+func (s *SessionService) Revoke(userID, sessionID uint) error {
+	return s.sessionRepo.Revoke(userID, sessionID, time.Now())
+}
+
+// Logout déconnecte la session du refresh token
+func (s *SessionService) Logout(refreshToken string) error {
+	return s.sessionRepo.RevokeByToken(utils.HashToken(refreshToken), time.Now())
+}
+
+// LogoutAll déconnecte toutes les sessions de l'utilisateur et retourne leur nombre
+func (s *SessionService) LogoutAll(userID uint) (int64, error) {
+	return s.sessionRepo.RevokeAll(userID, 0, time.Now())
+}
+
+// truncate coupe s à au plus limit octets sans couper de caractère
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+)
+
+var (
+	ErrInvalidOpeningHours       = errors.New("closing time must be after opening time")
+	ErrOverlappingOpeningHours   = errors.New("opening hours overlap on the same day")
+	ErrInvalidClosureDates       = errors.New("closure dates must be YYYY-MM-DD and end on or after the start date")
+	ErrPickupOutsideOpeningHours = errors.New("pickup window must fall within the store opening hours")
+)
+
+// dateLayout est le format des dates (sans heure) échangées avec l'API et la base
+const dateLayout = "2006-01-02"
+
+// scheduleLookahead borne la recherche de la prochaine ouverture d'un magasin
+const scheduleLookahead = 366
+
+// openingSlot est une plage d'ouverture exprimée en durées depuis minuit
+type openingSlot struct {
+	opens  time.Duration
+	closes time.Duration
+}
+
+// storeSchedule regroupe les horaires hebdomadaires et les fermetures exceptionnelles d'un magasin
+type storeSchedule struct {
+	loc      *time.Location
+	hours    []models.StoreOpeningHour
+	slots    map[int][]openingSlot // Par jour de la semaine (1 = lundi ... 7 = dimanche)
+	closures []models.StoreClosure
+}
+
+// loadStoreSchedule charge les horaires du magasin et ses fermetures à partir du jour de from
+func loadStoreSchedule(repo *repositories.StoreScheduleRepository, store *models.Store, from time.Time) (*storeSchedule, error) {
+	hours, err := repo.GetOpeningHours(store.ID)
+	if err != nil {
+		return nil, err
+	}
+	loc := store.Location()
+	closures, err := repo.GetClosuresFrom(store.ID, from.In(loc).Format(dateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &storeSchedule{loc: loc, hours: hours, slots: map[int][]openingSlot{}, closures: closures}
+	for _, hour := range hours {
+		opens, err := utils.ParseTimeOfDay(hour.OpensAt)
+		if err != nil {
+			return nil, err
+		}
+		closes, err := utils.ParseTimeOfDay(hour.ClosesAt)
+		if err != nil {
+			return nil, err
+		}
+		schedule.slots[hour.Weekday] = append(schedule.slots[hour.Weekday], openingSlot{opens: opens, closes: closes})
+	}
+	for day := range schedule.slots {
+		slots := schedule.slots[day]
+		sort.Slice(slots, func(i, j int) bool { return slots[i].opens < slots[j].opens })
+	}
+	return schedule, nil
+}
+
+// defined indique si le magasin a renseigné ses horaires ; sans horaires, aucune contrainte n'est appliquée
+func (s *storeSchedule) defined() bool {
+	return len(s.slots) > 0
+}
+
+// closedOn indique si le magasin est fermé exceptionnellement le jour de t
+func (s *storeSchedule) closedOn(t time.Time) bool {
+	day := t.In(s.loc).Format(dateLayout)
+	for _, closure := range s.closures {
+		if DateOnly(closure.StartDate) <= day && day <= DateOnly(closure.EndDate) {
+			return true
+		}
+	}
+	return false
+}
+
+// slotsOn retourne les plages d'ouverture du jour de t, ou aucune si le magasin est fermé ce jour-là
+func (s *storeSchedule) slotsOn(t time.Time) []openingSlot {
+	if s.closedOn(t) {
+		return nil
+	}
+	return s.slots[isoWeekday(t.In(s.loc).Weekday())]
+}
+
+// openSlotAt retourne la plage d'ouverture en cours à l'instant t
+func (s *storeSchedule) openSlotAt(t time.Time) (openingSlot, bool) {
+	offset := timeOfDay(t.In(s.loc))
+	for _, slot := range s.slotsOn(t) {
+		if slot.opens <= offset && offset < slot.closes {
+			return slot, true
+		}
+	}
+	return openingSlot{}, false
+}
+
+// closesAt retourne l'heure de fermeture de la plage en cours, si le magasin est ouvert à l'instant t
+func (s *storeSchedule) closesAt(t time.Time) *time.Time {
+	slot, ok := s.openSlotAt(t)
+	if !ok {
+		return nil
+	}
+	closes := utils.AtTimeOfDay(t, slot.closes, s.loc)
+	return &closes
+}
+
+// nextOpening retourne la prochaine ouverture strictement postérieure à t
+func (s *storeSchedule) nextOpening(t time.Time) *time.Time {
+	local := t.In(s.loc)
+	for i := 0; i < scheduleLookahead; i++ {
+		day := local.AddDate(0, 0, i)
+		for _, slot := range s.slotsOn(day) {
+			opens := utils.AtTimeOfDay(day, slot.opens, s.loc)
+			if opens.After(t) {
+				return &opens
+			}
+		}
+	}
+	return nil
+}
+
+// covers indique si la plage de retrait [start, end] tient dans une seule plage d'ouverture
+func (s *storeSchedule) covers(start, end time.Time) bool {
+	start, end = start.In(s.loc), end.In(s.loc)
+	if start.Format(dateLayout) != end.Format(dateLayout) {
+		return false
+	}
+	from, to := timeOfDay(start), timeOfDay(end)
+	for _, slot := range s.slotsOn(start) {
+		if slot.opens <= from && to <= slot.closes {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPickup indique si une plage de retrait est possible : jamais un jour de fermeture exceptionnelle,
+// et dans une plage d'ouverture si le magasin a renseigné ses horaires
+func (s *storeSchedule) allowsPickup(start, end time.Time) bool {
+	if s.closedOn(start) || s.closedOn(end) {
+		return false
+	}
+	return !s.defined() || s.covers(start, end)
+}
+
+// coversTimesOn indique si les heures de retrait tiennent dans une plage d'ouverture du jour de la semaine,
+// sans tenir compte des fermetures exceptionnelles
+func (s *storeSchedule) coversTimesOn(weekday int, from, to time.Duration) bool {
+	for _, slot := range s.slots[weekday] {
+		if slot.opens <= from && to <= slot.closes {
+			return true
+		}
+	}
+	return false
+}
+
+// validateOpeningHours vérifie un planning hebdomadaire : jours valides, heures cohérentes et sans chevauchement
+func validateOpeningHours(hours []models.StoreOpeningHour) error {
+	byDay := map[int][]openingSlot{}
+	for _, hour := range hours {
+		if hour.Weekday < 1 || hour.Weekday > 7 {
+			return ErrInvalidWeekday
+		}
+		opens, err := utils.ParseTimeOfDay(hour.OpensAt)
+		if err != nil {
+			return err
+		}
+		closes, err := utils.ParseTimeOfDay(hour.ClosesAt)
+		if err != nil {
+			return err
+		}
+		if closes <= opens {
+			return ErrInvalidOpeningHours
+		}
+		for _, other := range byDay[hour.Weekday] {
+			if opens < other.closes && other.opens < closes {
+				return ErrOverlappingOpeningHours
+			}
+		}
+		byDay[hour.Weekday] = append(byDay[hour.Weekday], openingSlot{opens: opens, closes: closes})
+	}
+	return nil
+}
+
+// validateClosureDates vérifie les dates d'une fermeture exceptionnelle
+func validateClosureDates(startDate, endDate string) error {
+	start, err := time.Parse(dateLayout, startDate)
+	if err != nil {
+		return ErrInvalidClosureDates
+	}
+	end, err := time.Parse(dateLayout, endDate)
+	if err != nil || end.Before(start) {
+		return ErrInvalidClosureDates
+	}
+	return nil
+}
+
+// isoWeekday convertit un jour de la semaine Go en numérotation ISO (1 = lundi ... 7 = dimanche)
+func isoWeekday(day time.Weekday) int {
+	if day == time.Sunday {
+		return 7
+	}
+	return int(day)
+}
+
+// timeOfDay retourne la durée écoulée depuis minuit (heure murale) pour t
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// DateOnly ne garde que la partie YYYY-MM-DD d'une date lue en base
+func DateOnly(value string) string {
+	if len(value) > len(dateLayout) {
+		return value[:len(dateLayout)]
+	}
+	return value
+}
@@ -1,22 +1,46 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/authz"
 	"github.com/Sebiche09/app-anti-gaspillage.git/geocoding"
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStoreNotFound        = errors.New("store not found")
+	ErrInvalidTimeZone      = errors.New("time_zone must be an IANA time zone such as Europe/Brussels")
+	ErrStaffMemberNotFound  = errors.New("staff member not found")
+	ErrInvalidStaffRole     = errors.New("role must be clerk or manager")
+	ErrStaffChangeForbidden = errors.New("you are not allowed to manage this staff member")
 )
 
 type StoreService struct {
 	storeRepo        *repositories.StoreRepository
 	merchantRepo     *repositories.MerchantRepository
+	scheduleRepo     *repositories.StoreScheduleRepository
+	staffRepo        *repositories.StoreStaffRepository
 	geocodingService *geocoding.Service
 }
 
-func NewStoreService(storeRepo *repositories.StoreRepository, merchantRepo *repositories.MerchantRepository, geocodingService *geocoding.Service) *StoreService {
-	return &StoreService{storeRepo: storeRepo, merchantRepo: merchantRepo, geocodingService: geocodingService}
+func NewStoreService(storeRepo *repositories.StoreRepository, merchantRepo *repositories.MerchantRepository, scheduleRepo *repositories.StoreScheduleRepository, staffRepo *repositories.StoreStaffRepository, geocodingService *geocoding.Service) *StoreService {
+	return &StoreService{storeRepo: storeRepo, merchantRepo: merchantRepo, scheduleRepo: scheduleRepo, staffRepo: staffRepo, geocodingService: geocodingService}
+}
+
+// StoreAvailability décrit l'état d'ouverture d'un magasin à un instant donné
+type StoreAvailability struct {
+	OpenNow       bool
+	ClosesAt      *time.Time // Fin de la plage d'ouverture en cours, si le magasin est ouvert
+	NextOpeningAt *time.Time // Prochaine ouverture, si le magasin est fermé
+	OpeningHours  []models.StoreOpeningHour
+	Closures      []models.StoreClosure // Fermetures exceptionnelles à venir
 }
 
 func (s *StoreService) GetCategories() ([]models.Category, error) {
@@ -31,6 +55,14 @@ func (s *StoreService) GetCategories() ([]models.Category, error) {
 }
 
 func (s *StoreService) CreateStore(req requests.CreateStoreRequest, userID uint) error {
+	timeZone := models.DefaultTimeZone
+	if req.TimeZone != "" {
+		if err := validateTimeZone(req.TimeZone); err != nil {
+			return err
+		}
+		timeZone = req.TimeZone
+	}
+
 	merchand, err := s.merchantRepo.FindMerchantByUserID(userID)
 	if err != nil {
 		return err
@@ -51,6 +83,7 @@ func (s *StoreService) CreateStore(req requests.CreateStoreRequest, userID uint)
 		CategoryID:  req.CategoryID,
 		Latitude:    coordinates.Latitude,
 		Longitude:   coordinates.Longitude,
+		TimeZone:    timeZone,
 	}
 
 	return s.storeRepo.CreateStore(store)
@@ -74,6 +107,12 @@ func (s *StoreService) GetStoreByID(id uint) (*models.Store, error) {
 }
 
 func (s *StoreService) UpdateStore(req requests.UpdateStoreRequest, id uint) error {
+	if req.TimeZone != "" {
+		if err := validateTimeZone(req.TimeZone); err != nil {
+			return err
+		}
+	}
+
 	store, err := s.storeRepo.GetStoreByID(id)
 	if err != nil {
 		return err
@@ -84,10 +123,24 @@ func (s *StoreService) UpdateStore(req requests.UpdateStoreRequest, id uint) err
 	store.City = req.City
 	store.PostalCode = req.PostalCode
 	store.PhoneNumber = req.PhoneNumber
+	if req.TimeZone != "" {
+		store.TimeZone = req.TimeZone
+	}
 
 	return s.storeRepo.UpdateStore(store)
 }
 
+// validateTimeZone vérifie que name est un fuseau horaire IANA connu ; le fuseau local du serveur est refusé
+func validateTimeZone(name string) error {
+	if name == "Local" {
+		return ErrInvalidTimeZone
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ErrInvalidTimeZone
+	}
+	return nil
+}
+
 func (s *StoreService) DeleteStore(id uint) error {
 	store, err := s.storeRepo.GetStoreByID(id)
 	if err != nil {
@@ -97,7 +150,7 @@ func (s *StoreService) DeleteStore(id uint) error {
 	return s.storeRepo.DeleteStore(store)
 }
 func (s *StoreService) GetStoreStaff(id uint) ([]models.StoreStaff, error) {
-	store, err := s.storeRepo.GetStoreByID(id)
+	store, err := s.getStore(id)
 	if err != nil {
 		return nil, err
 	}
@@ -109,63 +162,303 @@ func (s *StoreService) GetStoreStaff(id uint) ([]models.StoreStaff, error) {
 
 	return staff, nil
 }
-func (s *StoreService) GetStoreBasketConfig(id uint) (*models.BasketConfiguration, error) {
-	store, err := s.storeRepo.GetStoreByID(id)
+
+// UpdateStaffRole change le rôle d'un membre du staff ; réservé au commerçant propriétaire et aux administrateurs.
+// Les permissions étant évaluées depuis la base de données, le nouveau rôle s'applique immédiatement.
+func (s *StoreService) UpdateStaffRole(storeID, userID uint, role string, actor authz.Role) (*models.StoreStaff, error) {
+	if role != models.StaffRoleClerk && role != models.StaffRoleManager {
+		return nil, ErrInvalidStaffRole
+	}
+	if actor != authz.RoleOwner && actor != authz.RoleAdmin {
+		return nil, ErrStaffChangeForbidden
+	}
+
+	member, err := s.staffMember(storeID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.staffRepo.UpdateRole(storeID, userID, role); err != nil {
+		return nil, err
+	}
+	member.Role = role
+	return member, nil
+}
 
-	config, err := s.storeRepo.GetStoreBasketConfig(store.ID)
+// RemoveStaffMember retire un membre du staff, qui perd ses accès immédiatement, même avec un JWT
+// encore valide. Un responsable ne peut retirer que des employés, ou lui-même.
+func (s *StoreService) RemoveStaffMember(storeID, userID, actorID uint, actor authz.Role) error {
+	member, err := s.staffMember(storeID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération de la configuration du panier du magasin: %w", err)
+		return err
 	}
 
-	return config, nil
+	switch actor {
+	case authz.RoleOwner, authz.RoleAdmin:
+	case authz.RoleManager:
+		if member.Role == models.StaffRoleManager && member.UserID != actorID {
+			return ErrStaffChangeForbidden
+		}
+	default:
+		return ErrStaffChangeForbidden
+	}
+
+	return s.staffRepo.RemoveStaffMember(storeID, userID)
 }
-func (s *StoreService) CreateStoreBasketConfig(req requests.CreateBasketConfigurationRequest, id uint) error {
-	store, err := s.storeRepo.GetStoreByID(id)
+
+// staffMember retourne l'appartenance de l'utilisateur au staff du magasin
+func (s *StoreService) staffMember(storeID, userID uint) (*models.StoreStaff, error) {
+	member, err := s.staffRepo.FindMember(storeID, userID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrStaffMemberNotFound
+	}
+	return member, nil
+}
+
+// getStore retourne le magasin ; les droits de l'utilisateur sont vérifiés en amont par
+// middlewares.RequireStorePermission
+func (s *StoreService) getStore(storeID uint) (*models.Store, error) {
+	store, err := s.storeRepo.GetStoreByID(storeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStoreNotFound
+		}
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *StoreService) GetStoreBasketConfigs(storeID uint) ([]models.BasketConfiguration, error) {
+	store, err := s.getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := s.storeRepo.GetStoreBasketConfigs(store.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des configurations panier du magasin: %w", err)
+	}
+
+	return configs, nil
+}
+func (s *StoreService) CreateStoreBasketConfig(req requests.CreateBasketConfigurationRequest, storeID uint) (*models.BasketConfiguration, error) {
+	store, err := s.getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateBasketSchedule(req.Weekdays, req.PickupStartTime, req.PickupEndTime); err != nil {
+		return nil, err
+	}
+	if err := s.validateScheduleAgainstHours(store, req.Weekdays, req.PickupStartTime, req.PickupEndTime); err != nil {
+		return nil, err
 	}
 
 	config := &models.BasketConfiguration{
 		Name:               req.Name,
 		Description:        req.Description,
 		DiscountPercentage: req.DiscountPercentage,
+		OriginalPrice:      req.OriginalPrice,
 		Quantity:           req.Quantity,
+		PickupStartTime:    req.PickupStartTime,
+		PickupEndTime:      req.PickupEndTime,
+		Weekdays:           models.FormatWeekdays(normalizeWeekdays(req.Weekdays)),
+		Active:             req.Active == nil || *req.Active,
 		StoreID:            store.ID,
 	}
 
-	return s.storeRepo.CreateStoreBasketConfig(config)
+	if err := s.storeRepo.CreateStoreBasketConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
-func (s *StoreService) UpdateStoreBasketConfig(req requests.UpdateBasketConfigurationRequest, id uint) error {
-	store, err := s.storeRepo.GetStoreByID(id)
+func (s *StoreService) UpdateStoreBasketConfig(req requests.UpdateBasketConfigurationRequest, storeID, configID uint) (*models.BasketConfiguration, error) {
+	store, err := s.getStore(storeID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := validateBasketSchedule(req.Weekdays, req.PickupStartTime, req.PickupEndTime); err != nil {
+		return nil, err
+	}
+	if err := s.validateScheduleAgainstHours(store, req.Weekdays, req.PickupStartTime, req.PickupEndTime); err != nil {
+		return nil, err
 	}
 
-	config, err := s.storeRepo.GetStoreBasketConfig(store.ID)
+	config, err := s.storeRepo.GetStoreBasketConfig(store.ID, configID)
 	if err != nil {
-		return fmt.Errorf("erreur lors de la récupération de la configuration du panier du magasin: %w", err)
+		return nil, err
 	}
 
 	config.Name = req.Name
 	config.Description = req.Description
 	config.DiscountPercentage = req.DiscountPercentage
+	config.OriginalPrice = req.OriginalPrice
 	config.Quantity = req.Quantity
+	config.PickupStartTime = req.PickupStartTime
+	config.PickupEndTime = req.PickupEndTime
+	config.Weekdays = models.FormatWeekdays(normalizeWeekdays(req.Weekdays))
+	if req.Active != nil {
+		config.Active = *req.Active
+	}
 
-	return s.storeRepo.UpdateStoreBasketConfig(config)
+	if err := s.storeRepo.UpdateStoreBasketConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
-func (s *StoreService) DeleteStoreBasketConfig(id uint) error {
-	store, err := s.storeRepo.GetStoreByID(id)
+func (s *StoreService) DeleteStoreBasketConfig(storeID, configID uint) error {
+	store, err := s.getStore(storeID)
 	if err != nil {
 		return err
 	}
 
-	config, err := s.storeRepo.GetStoreBasketConfig(store.ID)
+	config, err := s.storeRepo.GetStoreBasketConfig(store.ID, configID)
 	if err != nil {
-		return fmt.Errorf("erreur lors de la récupération de la configuration du panier du magasin: %w", err)
+		return err
 	}
 
 	return s.storeRepo.DeleteStoreBasketConfig(config)
 }
+
+// validateScheduleAgainstHours vérifie que les heures de retrait d'un planning hebdomadaire
+// tiennent dans les horaires d'ouverture du magasin chacun des jours de publication
+func (s *StoreService) validateScheduleAgainstHours(store *models.Store, weekdays []int, pickupStart, pickupEnd string) error {
+	if len(weekdays) == 0 || pickupStart == "" {
+		return nil
+	}
+	schedule, err := loadStoreSchedule(s.scheduleRepo, store, time.Now())
+	if err != nil {
+		return err
+	}
+	if !schedule.defined() {
+		return nil
+	}
+
+	from, err := utils.ParseTimeOfDay(pickupStart)
+	if err != nil {
+		return err
+	}
+	to, err := utils.ParseTimeOfDay(pickupEnd)
+	if err != nil {
+		return err
+	}
+	for _, day := range weekdays {
+		if !schedule.coversTimesOn(day, from, to) {
+			return ErrPickupOutsideOpeningHours
+		}
+	}
+	return nil
+}
+
+// GetStoreAvailability retourne les horaires du magasin et son état d'ouverture à l'instant now
+func (s *StoreService) GetStoreAvailability(store *models.Store, now time.Time) (*StoreAvailability, error) {
+	schedule, err := loadStoreSchedule(s.scheduleRepo, store, now)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := &StoreAvailability{OpeningHours: schedule.hours, Closures: schedule.closures}
+	if !schedule.defined() {
+		return availability, nil
+	}
+	availability.ClosesAt = schedule.closesAt(now)
+	availability.OpenNow = availability.ClosesAt != nil
+	if !availability.OpenNow {
+		availability.NextOpeningAt = schedule.nextOpening(now)
+	}
+	return availability, nil
+}
+
+// ReplaceOpeningHours remplace le planning hebdomadaire du magasin
+func (s *StoreService) ReplaceOpeningHours(req requests.UpdateOpeningHoursRequest, storeID uint) ([]models.StoreOpeningHour, error) {
+	store, err := s.getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := make([]models.StoreOpeningHour, 0, len(req.OpeningHours))
+	for _, hour := range req.OpeningHours {
+		hours = append(hours, models.StoreOpeningHour{
+			Weekday:  hour.Weekday,
+			OpensAt:  hour.OpensAt,
+			ClosesAt: hour.ClosesAt,
+		})
+	}
+	if err := validateOpeningHours(hours); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.ReplaceOpeningHours(store.ID, hours); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetOpeningHours(store.ID)
+}
+
+// GetStoreClosures retourne les fermetures exceptionnelles à venir du magasin
+func (s *StoreService) GetStoreClosures(storeID uint) ([]models.StoreClosure, error) {
+	store, err := s.getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetClosuresFrom(store.ID, time.Now().In(store.Location()).Format(dateLayout))
+}
+
+func (s *StoreService) CreateStoreClosure(req requests.StoreClosureRequest, storeID uint) (*models.StoreClosure, error) {
+	store, err := s.getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateClosureDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	closure := &models.StoreClosure{
+		StoreID:   store.ID,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Reason:    req.Reason,
+	}
+	if err := s.scheduleRepo.CreateClosure(closure); err != nil {
+		return nil, err
+	}
+	return closure, nil
+}
+
+func (s *StoreService) UpdateStoreClosure(req requests.StoreClosureRequest, storeID, closureID uint) (*models.StoreClosure, error) {
+	store, err := s.getStore(storeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateClosureDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	closure, err := s.scheduleRepo.GetClosure(store.ID, closureID)
+	if err != nil {
+		return nil, err
+	}
+	closure.StartDate = req.StartDate
+	closure.EndDate = req.EndDate
+	closure.Reason = req.Reason
+
+	if err := s.scheduleRepo.UpdateClosure(closure); err != nil {
+		return nil, err
+	}
+	return closure, nil
+}
+
+func (s *StoreService) DeleteStoreClosure(storeID, closureID uint) error {
+	store, err := s.getStore(storeID)
+	if err != nil {
+		return err
+	}
+
+	closure, err := s.scheduleRepo.GetClosure(store.ID, closureID)
+	if err != nil {
+		return err
+	}
+	return s.scheduleRepo.DeleteClosure(closure)
+}
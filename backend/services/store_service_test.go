@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
+	"github.com/Sebiche09/app-anti-gaspillage.git/testutil"
+)
+
+func TestUpdateStoreTimeZone(t *testing.T) {
+	db := testutil.OpenDB(t)
+	stores := NewStoreService(
+		repositories.NewStoreRepository(db),
+		repositories.NewMerchantRepository(db),
+		repositories.NewStoreScheduleRepository(db),
+		repositories.NewStoreStaffRepository(db),
+		nil,
+	)
+	owner := testutil.CreateUser(t, db, "owner@example.com")
+	store := testutil.CreateStore(t, db, testutil.CreateMerchant(t, db, owner), "Boulangerie")
+
+	update := func(timeZone string) (string, error) {
+		t.Helper()
+		err := stores.UpdateStore(requests.UpdateStoreRequest{Name: store.Name, Address: store.Address, TimeZone: timeZone}, store.ID)
+		var saved models.Store
+		if err := db.First(&saved, store.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return saved.TimeZone, err
+	}
+
+	for _, tt := range []struct {
+		timeZone string
+		want     string
+		wantErr  error
+	}{
+		{"America/Cayenne", "America/Cayenne", nil},
+		{"", "America/Cayenne", nil}, // Absent : le fuseau est conservé
+		{"Mars/Olympus", "America/Cayenne", ErrInvalidTimeZone},
+		{"Local", "America/Cayenne", ErrInvalidTimeZone},
+	} {
+		got, err := update(tt.timeZone)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("time_zone %q: saved %q, err %v; want %q, %v", tt.timeZone, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
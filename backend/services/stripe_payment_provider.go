@@ -0,0 +1,133 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultStripeBaseURL = "https://api.stripe.com/v1"
+
+// StripePaymentProvider implémente PaymentProvider avec l'API HTTP de Stripe
+// (ou de tout service compatible via BaseURL)
+type StripePaymentProvider struct {
+	BaseURL   string
+	SecretKey string
+	client    *http.Client
+}
+
+func NewStripePaymentProvider(secretKey, baseURL string) *StripePaymentProvider {
+	if baseURL == "" {
+		baseURL = defaultStripeBaseURL
+	}
+	return &StripePaymentProvider{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		SecretKey: secretKey,
+		client:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type stripeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *StripePaymentProvider) CreateCustomer(email string) (string, error) {
+	var customer struct {
+		ID string `json:"id"`
+	}
+	if err := p.post("/customers", url.Values{"email": {email}}, "", &customer); err != nil {
+		return "", err
+	}
+	return customer.ID, nil
+}
+
+func (p *StripePaymentProvider) AttachPaymentMethod(customerID, paymentMethodID string) error {
+	path := "/payment_methods/" + url.PathEscape(paymentMethodID) + "/attach"
+	return p.post(path, url.Values{"customer": {customerID}}, "", nil)
+}
+
+func (p *StripePaymentProvider) CreatePaymentIntent(amount int64, currency, customerID, idempotencyKey string, metadata map[string]string) (*PaymentIntent, error) {
+	form := url.Values{
+		"amount":   {fmt.Sprintf("%d", amount)},
+		"currency": {currency},
+		"customer": {customerID},
+	}
+	for key, value := range metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	var intent PaymentIntent
+	if err := p.post("/payment_intents", form, idempotencyKey, &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func (p *StripePaymentProvider) ConfirmPaymentIntent(intentID, paymentMethodID string) (*PaymentIntent, error) {
+	path := "/payment_intents/" + url.PathEscape(intentID) + "/confirm"
+	var intent PaymentIntent
+	if err := p.post(path, url.Values{"payment_method": {paymentMethodID}}, "", &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// RefundPaymentIntent rembourse l'intention avec une clé d'idempotence propre à celle-ci : une tentative
+// répétée après une réponse perdue ne crée pas de second remboursement
+func (p *StripePaymentProvider) RefundPaymentIntent(intentID string) error {
+	return p.post("/refunds", url.Values{"payment_intent": {intentID}}, "refund-"+intentID, nil)
+}
+
+// post envoie une requête form-encoded à l'API et décode la réponse JSON dans out.
+// Avec une idempotencyKey, l'API rejoue la réponse d'une requête déjà traitée sous cette clé.
+func (p *StripePaymentProvider) post(path string, form url.Values, idempotencyKey string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, p.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("erreur lors de la création de la requête: %w", err)
+	}
+	req.SetBasicAuth(p.SecretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'appel au prestataire de paiement: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la lecture de la réponse: %w", err)
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		var apiErr stripeError
+		if err := json.Unmarshal(body, &apiErr); err == nil {
+			switch {
+			case apiErr.Error.Type == "card_error":
+				return fmt.Errorf("%w: %s", ErrPaymentDeclined, apiErr.Error.Message)
+			case apiErr.Error.Code == "charge_already_refunded":
+				return fmt.Errorf("%w: %s", ErrPaymentAlreadyRefunded, apiErr.Error.Message)
+			}
+		}
+		return fmt.Errorf("erreur du prestataire de paiement (%d): %s", res.StatusCode, apiErr.Error.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("erreur lors de l'analyse de la réponse JSON: %w", err)
+	}
+	return nil
+}
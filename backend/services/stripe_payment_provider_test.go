@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStripeIdempotencyKeys(t *testing.T) {
+	keys := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys[r.URL.Path] = r.Header.Get("Idempotency-Key")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"pi_123","status":"requires_payment_method"}`))
+	}))
+	defer server.Close()
+	stripe := NewStripePaymentProvider("sk_test", server.URL)
+
+	if _, err := stripe.CreatePaymentIntent(1000, paymentCurrency, "cus_123", "order-42", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := stripe.RefundPaymentIntent("pi_123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stripe.ConfirmPaymentIntent("pi_123", "pm_card_visa"); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"/payment_intents":                "order-42",
+		"/refunds":                        "refund-pi_123",
+		"/payment_intents/pi_123/confirm": "",
+	} {
+		if got := keys[path]; got != want {
+			t.Errorf("Idempotency-Key for %s = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestStripeAlreadyRefunded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"type":"invalid_request_error","code":"charge_already_refunded","message":"Charge has already been refunded."}}`))
+	}))
+	defer server.Close()
+
+	err := NewStripePaymentProvider("sk_test", server.URL).RefundPaymentIntent("pi_123")
+	if !errors.Is(err, ErrPaymentAlreadyRefunded) {
+		t.Errorf("err = %v, want %v", err, ErrPaymentAlreadyRefunded)
+	}
+}
@@ -1,22 +1,63 @@
 package services
 
 import (
+	"crypto/subtle"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/Sebiche09/app-anti-gaspillage.git/api/requests"
+	"github.com/Sebiche09/app-anti-gaspillage.git/mailer"
 	"github.com/Sebiche09/app-anti-gaspillage.git/models"
 	"github.com/Sebiche09/app-anti-gaspillage.git/repositories"
 	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
 	"gorm.io/gorm"
 )
 
+const (
+	// validationCodeValidity est la durée de validité d'un code de validation
+	validationCodeValidity = 10 * time.Minute
+	// maxValidationAttempts est le nombre de saisies erronées après lequel le code est bloqué :
+	// un nouveau code doit être demandé
+	maxValidationAttempts = 5
+	// validationCodeCooldown est le délai minimal entre deux envois du code de validation
+	validationCodeCooldown = time.Minute
+)
+
+// passwordResetValidity est la durée de validité d'un lien de réinitialisation du mot de passe
+const passwordResetValidity = time.Hour
+
+var (
+	ErrInvalidPassword       = errors.New("current password is incorrect")
+	ErrInvalidValidationCode = errors.New("invalid or expired validation code")
+)
+
 type UserService struct {
-	UserRepo *repositories.UserRepository
+	UserRepo  *repositories.UserRepository
+	resetRepo *repositories.PasswordResetRepository
+	sessions  *SessionService
+	mail      *MailService
+	appURL    string
 }
 
-func NewUserService(userRepo *repositories.UserRepository) *UserService {
-	return &UserService{UserRepo: userRepo}
+// NewUserService construit le service des utilisateurs ; appURL est l'adresse de l'application
+// web vers laquelle pointent les liens envoyés par email
+func NewUserService(
+	userRepo *repositories.UserRepository,
+	resetRepo *repositories.PasswordResetRepository,
+	sessions *SessionService,
+	mail *MailService,
+	appURL string,
+) *UserService {
+	return &UserService{
+		UserRepo:  userRepo,
+		resetRepo: resetRepo,
+		sessions:  sessions,
+		mail:      mail,
+		appURL:    strings.TrimRight(appURL, "/"),
+	}
 }
 
 func (s *UserService) Create(req requests.RegisterRequest) (*models.User, error) {
@@ -36,108 +77,182 @@ func (s *UserService) Create(req requests.RegisterRequest) (*models.User, error)
 	}
 
 	code := utils.GenerateValidationCode()
+	now := time.Now()
 
 	user := &models.User{
-		Email:            req.Email,
-		PasswordHash:     hashedPassword,
-		ValidationCode:   code,
-		IsEmailConfirmed: false,
+		Email:                req.Email,
+		PasswordHash:         hashedPassword,
+		ValidationCode:       code,
+		ValidationCodeSentAt: &now,
+		IsEmailConfirmed:     false,
 	}
 
-	if err := s.UserRepo.Create(user); err != nil {
+	// Le compte et l'email du code de validation sont enregistrés ensemble : pas de compte sans email
+	err = s.UserRepo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := repositories.NewUserRepository(tx).Create(user); err != nil {
+			return err
+		}
+		return s.sendValidationCode(s.mail.WithTx(tx), user)
+	})
+	if err != nil {
 		return nil, errors.New("failed to create user")
 	}
 
 	return user, nil
 }
 
-func (s *UserService) GetByEmail(email string) (*models.User, error) {
-	return s.UserRepo.FindByEmail(email)
-}
+// ConfirmEmail confirme l'email du compte si le code est le dernier envoyé et qu'il n'a pas expiré.
+// Après maxValidationAttempts saisies erronées, le code est bloqué jusqu'à l'envoi d'un nouveau code.
+// Toutes les erreurs (email inconnu ou déjà confirmé, code erroné, expiré ou bloqué) sont
+// ErrInvalidValidationCode, pour ne pas révéler quels comptes existent.
+func (s *UserService) ConfirmEmail(email, code string) error {
+	now := time.Now()
+	valid := false
+	err := s.UserRepo.UpdateByEmail(email, func(user *models.User) bool {
+		if user.IsEmailConfirmed || user.ValidationCode == "" || user.ValidationAttempts >= maxValidationAttempts {
+			return false
+		}
+		if user.ValidationCodeSentAt == nil || !now.Before(user.ValidationCodeSentAt.Add(validationCodeValidity)) {
+			return false
+		}
+		if subtle.ConstantTimeCompare([]byte(user.ValidationCode), []byte(code)) != 1 {
+			user.ValidationAttempts++
+			return true
+		}
 
-func (s *UserService) Save(user *models.User) error {
-	return s.UserRepo.Update(user)
+		valid = true
+		user.IsEmailConfirmed = true
+		user.ValidationCode = ""
+		user.ValidationCodeSentAt = nil
+		user.ValidationAttempts = 0
+		return true
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if !valid {
+		return ErrInvalidValidationCode
+	}
+	return nil
 }
 
-func (s *UserService) Login(email string, password string) (string, string, error) {
-	user, err := s.UserRepo.FindByEmail(email)
-	if err != nil {
-		return "", "", errors.New("invalid credentials")
-	}
+// ResendValidationCode envoie un nouveau code de validation si le compte existe, n'est pas confirmé et
+// qu'aucun code n'a été envoyé depuis validationCodeCooldown. Le nouveau code remplace le précédent et
+// débloque les saisies. Aucun de ces cas n'est une erreur, pour ne pas révéler quels comptes existent.
+func (s *UserService) ResendValidationCode(email string) error {
+	now := time.Now()
+	var resent *models.User
+	err := s.UserRepo.UpdateByEmail(email, func(user *models.User) bool {
+		if user.IsEmailConfirmed {
+			return false
+		}
+		if user.ValidationCodeSentAt != nil && now.Before(user.ValidationCodeSentAt.Add(validationCodeCooldown)) {
+			return false
+		}
 
-	if !utils.CheckPasswordHash(password, user.PasswordHash) {
-		return "", "", errors.New("invalid credentials")
+		user.ValidationCode = utils.GenerateValidationCode()
+		user.ValidationCodeSentAt = &now
+		user.ValidationAttempts = 0
+		resent = user
+		return true
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
 	}
-
-	if !user.IsEmailConfirmed {
-		return "", "", errors.New("email not confirmed")
+	if err != nil || resent == nil {
+		return err
 	}
+	return s.sendValidationCode(s.mail, resent)
+}
 
-	isMerchant, err := s.UserRepo.IsMerchant(user.ID)
-	if err != nil {
-		return "", "", errors.New("failed to check merchant status")
-	}
+func (s *UserService) sendValidationCode(mail *MailService, user *models.User) error {
+	return mail.Enqueue(user.Email, mailer.TemplateValidationCode, mailer.ValidationCodeData{
+		Code:     user.ValidationCode,
+		ValidFor: fmt.Sprintf("%d minutes", int(validationCodeValidity.Minutes())),
+	})
+}
 
-	staffStoreIDs, err := s.UserRepo.GetStaffStoreIDs(user.ID)
-	if err != nil {
-		return "", "", errors.New("failed to get staff store IDs")
+// RequestPasswordReset envoie par email un lien de réinitialisation du mot de passe, à usage unique.
+// Un email inconnu n'est pas une erreur, pour ne pas révéler quels comptes existent.
+func (s *UserService) RequestPasswordReset(email string) error {
+	user, err := s.UserRepo.FindByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
 	}
-
-	token, err := utils.GenerateToken(user.Email, user.ID, user.IsAdmin, isMerchant, staffStoreIDs)
 	if err != nil {
-		return "", "", errors.New("failed to generate token")
+		return err
 	}
 
-	refreshToken, expiredTime, err := utils.GenerateRefreshToken()
+	token, err := utils.GenerateSecureToken()
 	if err != nil {
-		return "", "", errors.New("failed to generate refresh token")
+		return err
 	}
-	println("Generated refresh token:", refreshToken)
-	err = s.UserRepo.StoreRefreshToken(user.ID, refreshToken, expiredTime)
+	err = s.resetRepo.Create(&models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetValidity),
+	})
 	if err != nil {
-		return "", "", errors.New("failed to store refresh token")
+		return err
 	}
-	return token, refreshToken, nil
-}
 
-func (s *UserService) GetUsers() ([]models.User, error) {
-	return s.UserRepo.GetUsers()
+	return s.mail.Enqueue(user.Email, mailer.TemplatePasswordReset, mailer.PasswordResetData{
+		URL:      s.appURL + "/reset-password?token=" + url.QueryEscape(token),
+		ValidFor: "1 heure",
+	})
 }
 
-func (s *UserService) RefreshToken(refreshToken string) (string, string, error) {
-	user, err := s.UserRepo.FindByRefreshToken(refreshToken)
+// ResetPassword remplace le mot de passe du compte associé au jeton de réinitialisation
+// et déconnecte toutes ses sessions
+func (s *UserService) ResetPassword(token, newPassword string) error {
+	hashedPassword, err := utils.HashPassword(newPassword)
 	if err != nil {
-		return "", "", errors.New("invalid refresh token")
+		return err
 	}
+	_, err = s.resetRepo.ResetPassword(utils.HashToken(token), hashedPassword, time.Now())
+	return err
+}
 
-	if time.Now().After(user.ExpiryTime) {
-		return "", "", errors.New("refresh token expired")
+// ChangePassword remplace le mot de passe de l'utilisateur après vérification du mot de passe actuel
+// et déconnecte ses autres sessions que sessionID
+func (s *UserService) ChangePassword(userID, sessionID uint, currentPassword, newPassword string) error {
+	user, err := s.UserRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(currentPassword, user.PasswordHash) {
+		return ErrInvalidPassword
 	}
 
-	isMerchant, err := s.UserRepo.IsMerchant(user.ID)
+	hashedPassword, err := utils.HashPassword(newPassword)
 	if err != nil {
-		return "", "", errors.New("failed to check merchant status")
+		return err
 	}
+	return s.UserRepo.UpdatePassword(user.ID, hashedPassword, sessionID)
+}
 
-	staffStoreIDs, err := s.UserRepo.GetStaffStoreIDs(user.ID)
+// Login vérifie les identifiants et ouvre une session pour l'appareil décrit par userAgent et ipAddress
+func (s *UserService) Login(email, password, userAgent, ipAddress string) (string, string, error) {
+	user, err := s.UserRepo.FindByEmail(email)
 	if err != nil {
-		return "", "", errors.New("failed to get staff store IDs")
+		return "", "", errors.New("invalid credentials")
 	}
 
-	newAccessToken, err := utils.GenerateToken(user.Email, user.ID, user.IsAdmin, isMerchant, staffStoreIDs)
-	if err != nil {
-		return "", "", errors.New("failed to generate new access token")
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return "", "", errors.New("invalid credentials")
 	}
 
-	newRefreshToken, newExpiryTime, err := utils.GenerateRefreshToken()
-	if err != nil {
-		return "", "", errors.New("failed to generate new refresh token")
+	if !user.IsEmailConfirmed {
+		return "", "", errors.New("email not confirmed")
 	}
 
-	err = s.UserRepo.StoreRefreshToken(user.ID, newRefreshToken, newExpiryTime)
+	token, refreshToken, err := s.sessions.Open(user, userAgent, ipAddress)
 	if err != nil {
-		return "", "", errors.New("failed to store new refresh token")
+		return "", "", errors.New("failed to open session")
 	}
+	return token, refreshToken, nil
+}
 
-	return newAccessToken, newRefreshToken, nil
+func (s *UserService) GetUsers() ([]models.User, error) {
+	return s.UserRepo.GetUsers()
 }
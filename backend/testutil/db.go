@@ -0,0 +1,68 @@
+// Package testutil fournit aux tests une base SQLite en mémoire, migrée comme la base PostgreSQL
+package testutil
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/db"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+var dbCount atomic.Int64
+
+// sqliteTypes remplace les types PostgreSQL des modèles que le pilote SQLite relit mal :
+// timestamptz serait relu comme du texte et date comme un horodatage
+var sqliteTypes = map[schema.DataType]string{
+	"timestamptz": "datetime",
+	"date":        "text",
+}
+
+type dialector struct {
+	sqlite.Dialector
+}
+
+func (d dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return sqlite.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+func (d dialector) DataTypeOf(field *schema.Field) string {
+	if dataType, ok := sqliteTypes[field.DataType]; ok {
+		return dataType
+	}
+	return d.Dialector.DataTypeOf(field)
+}
+
+// OpenDB ouvre une base en mémoire propre au test et y applique les migrations
+func OpenDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:testdb%d?mode=memory&cache=shared", dbCount.Add(1))
+	conn, err := gorm.Open(dialector{sqlite.Dialector{DSN: dsn}}, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Une seule connexion, pour éviter les conflits de verrous de SQLite entre connexions
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.Migrate(conn); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
@@ -0,0 +1,134 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Sebiche09/app-anti-gaspillage.git/models"
+	"github.com/Sebiche09/app-anti-gaspillage.git/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TestPassword est le mot de passe des utilisateurs créés par CreateUser
+const TestPassword = "password123"
+
+func create(t testing.TB, db *gorm.DB, value interface{}) {
+	t.Helper()
+	if err := db.Omit(clause.Associations).Create(value).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CreateUser crée un utilisateur confirmé
+func CreateUser(t testing.TB, db *gorm.DB, email string) *models.User {
+	t.Helper()
+	hash, err := utils.HashPassword(TestPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{Email: email, PasswordHash: hash, IsEmailConfirmed: true}
+	create(t, db, user)
+	return user
+}
+
+// CreateMerchant fait de l'utilisateur un commerçant
+func CreateMerchant(t testing.TB, db *gorm.DB, user *models.User) *models.Merchant {
+	t.Helper()
+	merchant := &models.Merchant{
+		BusinessName: "Commerce " + user.Email,
+		EmailPro:     user.Email,
+		SIREN:        fmt.Sprintf("%09d", user.ID),
+		UserID:       user.ID,
+	}
+	create(t, db, merchant)
+	return merchant
+}
+
+// CreateStore crée un magasin du commerçant, à Bruxelles
+func CreateStore(t testing.TB, db *gorm.DB, merchant *models.Merchant, name string) *models.Store {
+	t.Helper()
+	store := &models.Store{
+		MerchantID: merchant.ID,
+		Name:       name,
+		Latitude:   50.8467,
+		Longitude:  4.3525,
+		Address:    "Grand-Place 1",
+		City:       "Bruxelles",
+		PostalCode: "1000",
+		CategoryID: 1,
+		TimeZone:   models.DefaultTimeZone,
+	}
+	create(t, db, store)
+	return store
+}
+
+// AddStaff ajoute l'utilisateur à l'équipe du magasin avec le rôle donné (models.StaffRole*)
+func AddStaff(t testing.TB, db *gorm.DB, store *models.Store, user *models.User, role string) {
+	t.Helper()
+	create(t, db, &models.StoreStaff{StoreID: store.ID, UserID: user.ID, Role: role})
+}
+
+// BasketStatusID retourne l'ID du statut de panier portant ce nom (models.BasketStatus*)
+func BasketStatusID(t testing.TB, db *gorm.DB, name string) int {
+	t.Helper()
+	var status models.BasketStatus
+	if err := db.Where("name = ?", name).First(&status).Error; err != nil {
+		t.Fatal(err)
+	}
+	return status.ID
+}
+
+// CreateBasket crée un panier du magasin au statut donné ; setup, s'il est fourni, complète le panier avant sa création
+func CreateBasket(t testing.TB, db *gorm.DB, store *models.Store, name, status string, setup func(basket *models.Basket)) *models.Basket {
+	t.Helper()
+	basket := &models.Basket{
+		StoreID:       int(store.ID),
+		Name:          name,
+		OriginalPrice: 12,
+		Quantity:      1,
+		StatusID:      BasketStatusID(t, db, status),
+	}
+	if setup != nil {
+		setup(basket)
+	}
+	create(t, db, basket)
+	return basket
+}
+
+// CreateOrder crée une commande du client sur le panier
+func CreateOrder(t testing.TB, db *gorm.DB, basket *models.Basket, user *models.User, status string, expiredAt time.Time) *models.Order {
+	t.Helper()
+	order := &models.Order{
+		BasketID:  basket.ID,
+		StoreID:   uint(basket.StoreID),
+		UserID:    user.ID,
+		Code:      utils.GenerateOrderCode(),
+		Status:    status,
+		Price:     basket.FinalPrice(),
+		ExpiredAt: &expiredAt,
+	}
+	create(t, db, order)
+	return order
+}
+
+// LoadBasket relit le panier en base, avec son statut
+func LoadBasket(t testing.TB, db *gorm.DB, id uint) models.Basket {
+	t.Helper()
+	var basket models.Basket
+	if err := db.Preload("Status").First(&basket, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return basket
+}
+
+// OrderStatus relit le statut de la commande en base
+func OrderStatus(t testing.TB, db *gorm.DB, id uint) string {
+	t.Helper()
+	var order models.Order
+	if err := db.First(&order, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return order.Status
+}
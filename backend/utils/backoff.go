@@ -0,0 +1,16 @@
+package utils
+
+import "time"
+
+// Backoff retourne l'attente avant la tentative suivant la n-ième : base, puis doublée à chaque
+// nouvelle tentative et plafonnée à maxDelay
+func Backoff(base, maxDelay time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
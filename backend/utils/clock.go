@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"time"
+)
+
+// Clock abstrait l'heure courante pour que les traitements périodiques puissent être testés
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock retourne l'heure système
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock retourne toujours la même heure, utile dans les tests
+type FixedClock struct {
+	Time time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
+
+var ErrInvalidTimeOfDay = errors.New("invalid time of day, expected HH:MM")
+
+// ParseTimeOfDay lit une heure au format HH:MM et retourne la durée écoulée depuis minuit
+func ParseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, ErrInvalidTimeOfDay
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// AtTimeOfDay retourne l'instant correspondant à l'heure offset le jour day, dans le fuseau loc
+func AtTimeOfDay(day time.Time, offset time.Duration, loc *time.Location) time.Time {
+	day = day.In(loc)
+	hours := int(offset / time.Hour)
+	minutes := int((offset % time.Hour) / time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, 0, 0, loc)
+}
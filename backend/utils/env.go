@@ -12,3 +12,12 @@ func GetEnv(key string) string {
 	}
 	return value
 }
+
+// GetEnvDefault retourne la variable d'environnement ou la valeur par défaut si elle n'est pas définie
+func GetEnvDefault(key, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
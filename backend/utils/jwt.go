@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,18 +14,23 @@ func getSecretKey() []byte {
 	return []byte(GetEnv("JWT_SECRET"))
 }
 
-func GenerateInvitationToken(storeID uint, email string) (string, error) {
+// GenerateInvitationToken signe le lien d'une invitation. Le code est inclus pour qu'un renvoi,
+// qui change le code, invalide les liens précédents.
+func GenerateInvitationToken(storeID uint, email, code string, expiresAt time.Time) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"store_id":   storeID,
 		"email":      email,
+		"code":       code,
 		"invitation": true,
-		"exp":        time.Now().Add(time.Hour * 24 * 7).Unix(),
+		"exp":        expiresAt.Unix(),
 	})
 
 	return token.SignedString(getSecretKey())
 }
 
-func VerifyInvitationToken(tokenString string) (uint, string, error) {
+// VerifyInvitationToken vérifie la signature et l'expiration du lien d'une invitation
+// et retourne le magasin, l'email et le code qu'il contient
+func VerifyInvitationToken(tokenString string) (uint, string, string, error) {
 	parseToken, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
 		if !ok {
@@ -34,27 +40,31 @@ func VerifyInvitationToken(tokenString string) (uint, string, error) {
 	})
 
 	if err != nil {
-		return 0, "", err
+		return 0, "", "", err
 	}
 
 	if !parseToken.Valid {
-		return 0, "", errors.New("invalid invitation token")
+		return 0, "", "", errors.New("invalid invitation token")
 	}
 
 	claims, ok := parseToken.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, "", errors.New("invalid token claims")
+		return 0, "", "", errors.New("invalid token claims")
 	}
 
 	isInvitation, ok := claims["invitation"].(bool)
 	if !ok || !isInvitation {
-		return 0, "", errors.New("not an invitation token")
+		return 0, "", "", errors.New("not an invitation token")
 	}
 
-	storeID := uint(claims["store_id"].(float64))
-	email := claims["email"].(string)
+	storeID, _ := claims["store_id"].(float64)
+	email, _ := claims["email"].(string)
+	code, _ := claims["code"].(string)
+	if email == "" || code == "" {
+		return 0, "", "", errors.New("invalid token claims")
+	}
 
-	return storeID, email, nil
+	return uint(storeID), email, code, nil
 }
 
 // ---------------------------------------------------------------
@@ -74,13 +84,50 @@ func GenerateValidationCode() string {
 	return string(b)
 }
 
+// orderCodeAlphabet exclut les caractères ambigus (0/O, 1/I) pour la lecture au comptoir
+const orderCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func GenerateOrderCode() string {
+	n := 8
+	b := make([]byte, n)
+	rand.Read(b)
+	for i := range b {
+		b[i] = orderCodeAlphabet[int(b[i])%len(orderCodeAlphabet)]
+	}
+	return string(b)
+}
+
+var ErrInvalidQRPayload = errors.New("invalid QR payload")
+
+// orderQRPrefix préfixe le code de retrait dans le contenu des QR codes affichés par l'application
+const orderQRPrefix = "sovemanje://orders/"
+
+func OrderQRPayload(code string) string {
+	return orderQRPrefix + code
+}
+
+// ParseOrderQRPayload extrait le code de retrait d'un QR code scanné au comptoir
+func ParseOrderQRPayload(payload string) (string, error) {
+	payload = strings.TrimSpace(payload)
+	if !strings.HasPrefix(payload, orderQRPrefix) {
+		return "", ErrInvalidQRPayload
+	}
+	code := strings.TrimPrefix(payload, orderQRPrefix)
+	if code == "" {
+		return "", ErrInvalidQRPayload
+	}
+	return code, nil
+}
+
 // ---------------------------------------------------------------
 
+// GenerateToken signe un jeton d'accès pour l'utilisateur, rattaché à sa session sessionID
 func GenerateToken(email string, userId uint, isAdmin bool,
-	isMerchant bool, staffStoreIDs []uint) (string, error) {
+	isMerchant bool, staffStoreIDs []uint, sessionID uint) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"email":       email,
 		"user_id":     userId,
+		"session_id":  sessionID,
 		"isAdmin":     isAdmin,
 		"isMerchant":  isMerchant,
 		"staffStores": staffStoreIDs,
@@ -90,7 +137,8 @@ func GenerateToken(email string, userId uint, isAdmin bool,
 	return token.SignedString(getSecretKey())
 }
 
-func VerifyToken(tokenString string) (uint, bool, bool, []uint, error) {
+// VerifyToken vérifie un jeton d'accès et retourne l'ID de l'utilisateur, ses droits et l'ID de sa session
+func VerifyToken(tokenString string) (uint, bool, bool, []uint, uint, error) {
 	parseToken, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
 
@@ -101,24 +149,30 @@ func VerifyToken(tokenString string) (uint, bool, bool, []uint, error) {
 		return getSecretKey(), nil
 	})
 	if err != nil {
-		return 0, false, false, nil, errors.New("could not parse token")
+		return 0, false, false, nil, 0, errors.New("could not parse token")
 	}
 
 	tokenIsValid := parseToken.Valid
 
 	if !tokenIsValid {
-		return 0, false, false, nil, errors.New("invalid token")
+		return 0, false, false, nil, 0, errors.New("invalid token")
 	}
 
 	claims, ok := parseToken.Claims.(jwt.MapClaims)
 
 	if !ok {
-		return 0, false, false, nil, errors.New("invalid token claims")
+		return 0, false, false, nil, 0, errors.New("invalid token claims")
 	}
 
-	userId := uint(claims["user_id"].(float64))
+	// Les liens d'invitation sont signés avec la même clé : ils ne doivent pas servir de jeton d'accès
+	userIdFloat, ok := claims["user_id"].(float64)
+	if isInvitation, _ := claims["invitation"].(bool); !ok || isInvitation {
+		return 0, false, false, nil, 0, errors.New("invalid token claims")
+	}
+	userId := uint(userIdFloat)
 	isAdmin, _ := claims["isAdmin"].(bool)
 	isMerchant, _ := claims["isMerchant"].(bool)
+	sessionID, _ := claims["session_id"].(float64)
 
 	var staffStoreIDs []uint
 	if staffStoresIntf, exists := claims["staffStores"]; exists && staffStoresIntf != nil {
@@ -131,7 +185,7 @@ func VerifyToken(tokenString string) (uint, bool, bool, []uint, error) {
 		}
 	}
 
-	return userId, isAdmin, isMerchant, staffStoreIDs, nil
+	return userId, isAdmin, isMerchant, staffStoreIDs, uint(sessionID), nil
 }
 
 func GenerateRefreshToken() (string, time.Time, error) {
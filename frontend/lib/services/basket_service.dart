@@ -8,11 +8,20 @@ class BasketService {
   BasketService({required ApiService apiService})
       : _apiService = apiService;
 
+  // L'API renvoie les paniers par pages ({data, nextCursor, total}) : on suit nextCursor
+  // jusqu'à la dernière page pour retourner la liste complète
   Future<List<Basket>> getBaskets() async {
     try {
-      final data = await _apiService.get('/api/baskets/');
-      if (data == null) return [];
-      return (data as List<dynamic>).map((json) => Basket.fromJson(json)).toList();
+      final baskets = <Basket>[];
+      String? cursor;
+      do {
+        final query = cursor == null ? '' : '?cursor=${Uri.encodeQueryComponent(cursor)}';
+        final data = await _apiService.get('/api/baskets/$query');
+        if (data == null) break;
+        baskets.addAll((data['data'] as List<dynamic>).map((json) => Basket.fromJson(json)));
+        cursor = data['nextCursor'] as String?;
+      } while (cursor != null && cursor.isNotEmpty);
+      return baskets;
     } catch (e) {
       throw Exception('Failed to load baskets: $e');
     }